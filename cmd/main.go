@@ -3,12 +3,12 @@ package main
 import (
 	"database/sql"
 	"log"
-	"net/http"
 
 	"github.com/Desgue/SpicyDice/internal/config"
 	"github.com/Desgue/SpicyDice/internal/repository"
 	"github.com/Desgue/SpicyDice/internal/server"
 	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/Desgue/SpicyDice/postgres/migrations"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
@@ -21,6 +21,23 @@ func init() {
 
 func main() {
 	conf := config.New()
+	if err := conf.Server.Validate(); err != nil {
+		log.Fatalf("invalid server configuration: %s", err)
+	}
+
+	rtpReports, err := service.CheckRTP(conf.Game)
+	if err != nil {
+		log.Fatalf("invalid game configuration: %s", err)
+	}
+	for _, report := range rtpReports {
+		log.Printf("variant %s: multiplier %.2f, theoretical RTP %.4f, jackpot RTP %.4f", report.Variant, report.Multiplier, report.TheoreticalRTP, report.JackpotRTP)
+	}
+
+	newDice, err := service.NewDiceFactory(conf.Environment, conf.Dice)
+	if err != nil {
+		log.Fatalf("invalid dice configuration: %s", err)
+	}
+
 	connStr := conf.Postgres.String()
 
 	db, err := sql.Open("postgres", connStr)
@@ -33,27 +50,15 @@ func main() {
 		log.Fatalf("could not reach database: %s", err)
 	}
 
-	gameRepository := repository.NewGameRepository(db)
-	gameService := service.NewGameService(gameRepository)
-	gameServer := server.NewWebSocketServer(gameService)
+	if err := repository.Migrate(db, migrations.Files); err != nil {
+		log.Fatalf("could not migrate database: %s", err)
+	}
 
-	http.HandleFunc("/", serveHome)
-	fs := http.FileServer(http.Dir("./frontend"))
-	http.Handle("/frontend/", http.StripPrefix("/frontend/", fs))
+	gameRepository := repository.NewGameRepository(db)
+	gameService := service.NewGameService(gameRepository, conf.Game)
+	tableService := service.NewTableService(gameRepository, gameService, conf.Tables.Count)
+	tournamentService := service.NewTournamentService(gameRepository, gameService)
+	gameServer := server.NewWebSocketServer(gameService, conf, newDice, tableService, tournamentService)
 
 	gameServer.Run()
-
-}
-
-func serveHome(w http.ResponseWriter, r *http.Request) {
-	log.Println(r.URL)
-	if r.URL.Path != "/" {
-		http.Error(w, "Not found", http.StatusNotFound)
-		return
-	}
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	http.ServeFile(w, r, "./frontend/index.html")
 }
@@ -0,0 +1,156 @@
+// Command simulate runs millions of rounds against the in-process game rules
+// to measure the empirical RTP of each bet type and staking strategy
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/joho/godotenv"
+)
+
+// Report summarizes the outcome of a simulation for one bet type and strategy
+type Report struct {
+	BetType      domain.BetType `json:"bet_type"`
+	Strategy     string         `json:"strategy"`
+	Rounds       int            `json:"rounds"`
+	TotalStaked  float64        `json:"total_staked"`
+	TotalPaid    float64        `json:"total_paid"`
+	RTP          float64        `json:"rtp"`
+	Variance     float64        `json:"variance"`
+	HitFrequency float64        `json:"hit_frequency"`
+	MaxDrawdown  float64        `json:"max_drawdown"`
+}
+
+func main() {
+	rounds := flag.Int("rounds", 1_000_000, "rounds simulated for each bet type and strategy")
+	seed := flag.Uint64("seed", 1, "seed of the dice random source")
+	baseBet := flag.Float64("bet", 0, "base bet amount, defaults to the configured minimum bet")
+	balance := flag.Float64("balance", 1e12, "starting balance of each simulated player")
+	betTypes := flag.String("bets", "even,odd,exact", "comma separated bet types to simulate")
+	betNumber := flag.Int("number", 6, "dice face used by exact bets")
+	strategies := flag.String("strategies", "flat,martingale,dalembert", "comma separated staking strategies to simulate")
+	format := flag.String("format", "table", "output format: table or json")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Print("No .env file found")
+	}
+	conf := config.New().Game
+	if _, err := service.CheckRTP(conf); err != nil {
+		log.Printf("warning: %s", err)
+	}
+	if *baseBet == 0 {
+		*baseBet = conf.MinBetAmount
+	}
+
+	// GameService logs every play, which would flood the output over millions of rounds
+	log.SetOutput(io.Discard)
+
+	repo := repository.NewMemoryRepository()
+	gameService := service.NewGameService(repo, conf)
+
+	var reports []Report
+	playerID := 0
+	for _, betType := range strings.Split(*betTypes, ",") {
+		for _, strategyName := range strings.Split(*strategies, ",") {
+			strategy, err := newStrategy(strategyName, *baseBet, conf.MinBetAmount, conf.MaxBetAmount)
+			if err != nil {
+				fatal(err)
+			}
+
+			playerID++
+			repo.AddPlayer(playerID, *balance)
+			dice := service.NewSeededDice(service.DiceSides, *seed+uint64(playerID))
+			bet := domain.PlayRequest{ClientID: playerID, BetType: domain.BetType(betType)}
+			if bet.BetType == domain.Exact {
+				bet.BetNumber = *betNumber
+			}
+
+			report, err := simulate(gameService, dice, bet, strategy, *rounds)
+			if err != nil {
+				fatal(fmt.Errorf("%s/%s: %w", betType, strategyName, err))
+			}
+			report.Strategy = strategyName
+			reports = append(reports, report)
+		}
+	}
+
+	if err := writeReports(os.Stdout, reports, *format); err != nil {
+		fatal(err)
+	}
+}
+
+// simulate plays the given number of rounds through the game service, collecting the results
+func simulate(gs *service.GameService, dice service.DiceRoller, bet domain.PlayRequest, strategy strategy, rounds int) (Report, error) {
+	report := Report{BetType: bet.BetType, Rounds: rounds}
+	var wins int
+	var net, peak float64
+	var sumReturn, sumSquaredReturn float64
+
+	for i := 0; i < rounds; i++ {
+		bet.BetAmount = strategy.nextBet()
+		result, err := gs.ProcessPlay(bet, dice)
+		if err != nil {
+			return Report{}, err
+		}
+		strategy.record(result.Won)
+
+		report.TotalStaked += result.BetAmount
+		report.TotalPaid += result.Payout + result.JackpotWon
+		if result.Won {
+			wins++
+		}
+
+		// Returns are measured per unit staked so strategies with varying bets stay comparable
+		unitReturn := result.NetResult / result.BetAmount
+		sumReturn += unitReturn
+		sumSquaredReturn += unitReturn * unitReturn
+
+		net += result.NetResult
+		peak = math.Max(peak, net)
+		report.MaxDrawdown = math.Max(report.MaxDrawdown, peak-net)
+	}
+
+	mean := sumReturn / float64(rounds)
+	report.Variance = sumSquaredReturn/float64(rounds) - mean*mean
+	report.RTP = report.TotalPaid / report.TotalStaked
+	report.HitFrequency = float64(wins) / float64(rounds)
+	return report, nil
+}
+
+// writeReports prints the reports as an aligned table or as JSON
+func writeReports(w io.Writer, reports []Report, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, "BET\tSTRATEGY\tROUNDS\tSTAKED\tPAID\tRTP\tVARIANCE\tHIT FREQ\tMAX DRAWDOWN\t")
+		for _, r := range reports {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%.2f\t%.2f\t%.4f\t%.4f\t%.4f\t%.2f\t\n",
+				r.BetType, r.Strategy, r.Rounds, r.TotalStaked, r.TotalPaid, r.RTP, r.Variance, r.HitFrequency, r.MaxDrawdown)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "simulate: %s\n", err)
+	os.Exit(1)
+}
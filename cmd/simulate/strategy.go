@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// strategy decides the stake of the next round based on previous outcomes
+type strategy interface {
+	nextBet() float64
+	record(won bool)
+}
+
+// newStrategy builds a staking strategy bounded by the configured bet limits
+func newStrategy(name string, baseBet, minBet, maxBet float64) (strategy, error) {
+	limits := betLimits{min: minBet, max: maxBet}
+	switch name {
+	case "flat":
+		return &flatStrategy{bet: limits.clamp(baseBet)}, nil
+	case "martingale":
+		return &martingaleStrategy{base: baseBet, bet: baseBet, limits: limits}, nil
+	case "dalembert":
+		return &dalembertStrategy{unit: baseBet, bet: baseBet, limits: limits}, nil
+	default:
+		return nil, fmt.Errorf("unknown strategy: %s", name)
+	}
+}
+
+type betLimits struct {
+	min float64
+	max float64
+}
+
+func (l betLimits) clamp(bet float64) float64 {
+	return math.Min(math.Max(bet, l.min), l.max)
+}
+
+// flatStrategy always stakes the same amount
+type flatStrategy struct {
+	bet float64
+}
+
+func (s *flatStrategy) nextBet() float64 { return s.bet }
+func (s *flatStrategy) record(bool)      {}
+
+// martingaleStrategy doubles the stake after a loss and goes back to the base after a win
+type martingaleStrategy struct {
+	base   float64
+	bet    float64
+	limits betLimits
+}
+
+func (s *martingaleStrategy) nextBet() float64 { return s.limits.clamp(s.bet) }
+
+func (s *martingaleStrategy) record(won bool) {
+	if won {
+		s.bet = s.base
+		return
+	}
+	s.bet = s.limits.clamp(s.bet * 2)
+}
+
+// dalembertStrategy raises the stake by one unit after a loss and lowers it by one unit after a win
+type dalembertStrategy struct {
+	unit   float64
+	bet    float64
+	limits betLimits
+}
+
+func (s *dalembertStrategy) nextBet() float64 { return s.limits.clamp(s.bet) }
+
+func (s *dalembertStrategy) record(won bool) {
+	if won {
+		s.bet = math.Max(s.bet-s.unit, s.unit)
+		return
+	}
+	s.bet = s.limits.clamp(s.bet + s.unit)
+}
@@ -1,11 +1,26 @@
 const SPIN_DURATION = 3000;
 let selectedBetType = null;
 let ws = null;
-let clientId = Math.ceil(Math.random() * 1);
+const TOKEN_COOKIE = 'spicy_dice_token';
+let clientId = authenticate();
+const PROTOCOL = 'spicydice.v1';
+let serverFeatures = [];
+
+// authenticate stores the player token handed in the page fragment in the cookie sent with the WebSocket
+// upgrade, and returns the id of the player it was issued to. Tokens are formatted as <player id>.<expiry>.<signature>
+function authenticate() {
+    const fragment = new URLSearchParams(document.location.hash.slice(1));
+    if (fragment.has('token')) {
+        document.cookie = `${TOKEN_COOKIE}=${fragment.get('token')}; path=/; SameSite=Strict`;
+        history.replaceState(null, '', document.location.pathname);
+    }
+    const cookie = document.cookie.split('; ').find((entry) => entry.startsWith(`${TOKEN_COOKIE}=`));
+    return cookie ? parseInt(cookie.split('=')[1].split('.')[0], 10) : 0;
+}
 
 function connectWebSocket() {
     if (window["WebSocket"]) {
-        ws = new WebSocket(`ws://${document.location.host}/ws/spicy-dice`);
+        ws = new WebSocket(`ws://${document.location.host}/ws/spicy-dice`, PROTOCOL);
         ws.onerror = (error) => {
             console.error('WebSocket error:', error);
         };
@@ -15,6 +30,13 @@ function connectWebSocket() {
         };
         ws.onopen = () => {
             console.log('Connected to WebSocket');
+            ws.send(JSON.stringify({
+                type: 'hello',
+                payload: {
+                    version: 0,
+                    features: ['settlement_redelivery']
+                }
+            }));
             // Request initial wallet balance
             ws.send(JSON.stringify({
                 type: 'wallet',
@@ -22,6 +44,18 @@ function connectWebSocket() {
                     client_id: clientId
                 }
             }));
+            // Request the player's own bet limits
+            ws.send(JSON.stringify({
+                type: 'limits',
+                payload: {
+                    client_id: clientId
+                }
+            }));
+            // Request the current jackpot pool, later updates are pushed by the server
+            ws.send(JSON.stringify({
+                type: 'jackpot',
+                payload: {}
+            }));
         };
         ws.onmessage = handleWebSocketMessage
 
@@ -35,16 +69,20 @@ function connectWebSocket() {
 function handleWebSocketMessage(event) {
     const data = JSON.parse(event.data);
     console.log(data)
+    if (data.seq) {
+        acknowledge(data.seq);
+    }
     switch (data.type) {
         case "wallet":
             console.log("wallet: ", data.payload.balance)
             updateBalance(data.payload.balance);
+            updateBonus(data.payload);
             break;
         case "play":
             setTimeout(() => {
                 handleGameResult(data.payload);
                 ws.send(JSON.stringify({
-                    type: 'endplay',
+                    type: 'wallet',
                     payload: {
                         client_id: clientId
                     }
@@ -53,7 +91,7 @@ function handleWebSocketMessage(event) {
             }, SPIN_DURATION);
             break;
         case "endplay":
-            console.log(data.payload)
+            handleSessionSummary(data.payload);
             ws.send(JSON.stringify({
                 type: 'wallet',
                 payload: {
@@ -61,6 +99,25 @@ function handleWebSocketMessage(event) {
                 }
             }));
             break;
+        case "limits":
+            updateLimits(data.payload);
+            break;
+        case "jackpot":
+            updateJackpot(data.payload);
+            break;
+        case "reality_check":
+            handleRealityCheck(data.payload);
+            break;
+        case "achievement_unlocked":
+            alert(`Achievement unlocked: ${data.payload.achievement.name} - ${data.payload.achievement.description}`);
+            break;
+        case "round_voided":
+            updateBalance(data.payload.balance);
+            alert(`Round ${data.payload.round_id} was voided: ${data.payload.reason}`);
+            break;
+        case "hello":
+            serverFeatures = data.payload.features;
+            break;
         case "error":
             console.error(data.payload)
             break;
@@ -68,11 +125,68 @@ function handleWebSocketMessage(event) {
     }
 }
 
+// Acknowledge a settlement message so the server stops redelivering it
+function acknowledge(seq) {
+    ws.send(JSON.stringify({
+        type: 'ack',
+        payload: {
+            client_id: clientId,
+            seq: seq
+        }
+    }));
+}
+
+// Show the totals of the session that was just ended
+function handleSessionSummary(summary) {
+    const minutes = Math.floor(summary.duration_seconds / 60);
+    alert(`Session ended: ${summary.rounds_played} round(s) in ${minutes} minute(s), ` +
+        `staked $${summary.total_staked.toFixed(2)}, paid $${summary.total_payout.toFixed(2)}, ` +
+        `net result $${summary.net_result.toFixed(2)}.`);
+}
+
+// Show the reality check reminder and acknowledge it so play can continue
+function handleRealityCheck(check) {
+    const minutes = Math.floor(check.elapsed_seconds / 60);
+    alert(`Reality check: you have been playing for ${minutes} minute(s), ` +
+        `${check.rounds_played} round(s), net result $${check.net_result.toFixed(2)}.`);
+    ws.send(JSON.stringify({
+        type: 'reality_check_ack',
+        payload: {
+            client_id: clientId
+        }
+    }));
+}
+
 // Update UI balance
 function updateBalance(amount) {
     document.getElementById('balance').textContent = `$${amount.toFixed(2)}`;
 }
 
+// Update UI bonus balance with its wagering progress
+function updateBonus(wallet) {
+    const element = document.getElementById('bonus');
+    element.textContent = `$${wallet.bonus_balance.toFixed(2)}`;
+    element.title = wallet.bonus
+        ? `Wagered $${wallet.bonus.wagered.toFixed(2)} of $${wallet.bonus.wagering_requirement.toFixed(2)}`
+        : '';
+}
+
+// Update UI jackpot pool
+function updateJackpot(jackpot) {
+    document.getElementById('jackpot').textContent = `$${jackpot.amount.toFixed(2)}`;
+    if (jackpot.last_win_amount) {
+        console.log(`Jackpot of $${jackpot.last_win_amount.toFixed(2)} was won!`);
+    }
+}
+
+// Apply the player's bet limits to the bet input
+function updateLimits(limits) {
+    const betAmount = document.getElementById('betAmount');
+    betAmount.min = limits.min_bet_amount;
+    betAmount.max = limits.max_bet_amount;
+    betAmount.placeholder = `Bet between $${limits.min_bet_amount.toFixed(2)} and $${limits.max_bet_amount.toFixed(2)}`;
+}
+
 // Handle game result
 function handleGameResult(result) {
     const diceContainer = document.getElementById('diceContainer');
@@ -108,7 +222,7 @@ function initializeGame() {
     const betOddBtn = document.getElementById('betOdd');
     const playButton = document.getElementById('playButton');
     const betAmount = document.getElementById('betAmount');
-
+    const endSessionButton = document.getElementById('endSessionButton');
 
     betEvenBtn.addEventListener('click', () => {
         selectedBetType = 'even';
@@ -153,6 +267,15 @@ function initializeGame() {
 
 
     });
+
+    endSessionButton.addEventListener('click', () => {
+        ws.send(JSON.stringify({
+            type: 'endplay',
+            payload: {
+                client_id: clientId
+            }
+        }));
+    });
 }
 
 // Start the game
@@ -0,0 +1,119 @@
+package appErrors
+
+// Keys of the catalog templates translating error details, see GameError.WithDetail
+const (
+	DetailInsufficientFunds = "detail.insufficient_funds"
+	DetailBetNegative       = "detail.bet_negative"
+	DetailBetZero           = "detail.bet_zero"
+	DetailBetBelowMin       = "detail.bet_below_min"
+	DetailBetAboveMax       = "detail.bet_above_max"
+	DetailBetType           = "detail.bet_type"
+	DetailBetNumber         = "detail.bet_number"
+	DetailAutoplayRounds    = "detail.autoplay_rounds"
+	DetailStopConditions    = "detail.stop_conditions"
+	DetailAutoplayRunning   = "detail.autoplay_running"
+	DetailAutoplayIdle      = "detail.autoplay_idle"
+	DetailUserNotFound      = "detail.user_not_found"
+	DetailNotConnected      = "detail.not_connected"
+	DetailForbiddenPlayer   = "detail.forbidden_player"
+	DetailNoActiveSession   = "detail.no_active_session"
+	DetailRealityCheck      = "detail.reality_check"
+	DetailInvalidSeq        = "detail.invalid_seq"
+	DetailUnknownRound      = "detail.unknown_round"
+
+	DetailBonusAmount   = "detail.bonus_amount"
+	DetailBonusWagering = "detail.bonus_wagering"
+	DetailBonusValidity = "detail.bonus_validity"
+	DetailBonusInactive = "detail.bonus_inactive"
+	DetailBonusActive   = "detail.bonus_active"
+
+	DetailPromoCodeRequired  = "detail.promo_code_required"
+	DetailPromoUnknown       = "detail.promo_unknown"
+	DetailPromoExpired       = "detail.promo_expired"
+	DetailPromoExhausted     = "detail.promo_exhausted"
+	DetailPromoRedeemed      = "detail.promo_redeemed"
+	DetailPromoCodeLength    = "detail.promo_code_length"
+	DetailPromoKind          = "detail.promo_kind"
+	DetailPromoBetAmount     = "detail.promo_bet_amount"
+	DetailPromoFreeBetCount  = "detail.promo_free_bet_count"
+	DetailPromoValidity      = "detail.promo_validity"
+	DetailPromoLimits        = "detail.promo_limits"
+	DetailPromoExpiry        = "detail.promo_expiry"
+	DetailPromoExists        = "detail.promo_exists"
+	DetailFreeBetUnknown     = "detail.free_bet_unknown"
+	DetailFreeBetUnavailable = "detail.free_bet_unavailable"
+
+	DetailVoidRoundID      = "detail.void_round_id"
+	DetailVoidReason       = "detail.void_reason"
+	DetailVoidOperator     = "detail.void_operator"
+	DetailVoidBalance      = "detail.void_balance"
+	DetailVoidBonusClosed  = "detail.void_bonus_closed"
+	DetailVoidBonusBalance = "detail.void_bonus_balance"
+	DetailVoidBonusActive  = "detail.void_bonus_active"
+	DetailVoidGambled      = "detail.void_gambled"
+
+	DetailGambleDisabled      = "detail.gamble_disabled"
+	DetailGambleBetType       = "detail.gamble_bet_type"
+	DetailGambleNotOpen       = "detail.gamble_not_open"
+	DetailGambleRoundRequired = "detail.gamble_round_required"
+	DetailGamblePending       = "detail.gamble_pending"
+	DetailGambleOpen          = "detail.gamble_open"
+	DetailGambleOtherSession  = "detail.gamble_other_session"
+	DetailGambleNoWinnings    = "detail.gamble_no_winnings"
+	DetailGambleNotCash       = "detail.gamble_not_cash"
+	DetailGambleNotLast       = "detail.gamble_not_last"
+	DetailGambleRepeated      = "detail.gamble_repeated"
+	DetailGambleAlreadyOpen   = "detail.gamble_already_open"
+	DetailGambleBalance       = "detail.gamble_balance"
+	DetailGambleSettled       = "detail.gamble_settled"
+
+	DetailTableUnknown       = "detail.table_unknown"
+	DetailTableRoundRunning  = "detail.table_round_running"
+	DetailTableBettingClosed = "detail.table_betting_closed"
+	DetailTableNotRolled     = "detail.table_not_rolled"
+	DetailTableNoRound       = "detail.table_no_round"
+	DetailTableRoundOver     = "detail.table_round_over"
+	DetailTableNotSeated     = "detail.table_not_seated"
+	DetailTableJoinFirst     = "detail.table_join_first"
+
+	DetailTournamentUnknown       = "detail.tournament_unknown"
+	DetailTournamentEnded         = "detail.tournament_ended"
+	DetailTournamentNotRunning    = "detail.tournament_not_running"
+	DetailTournamentBuyIn         = "detail.tournament_buy_in"
+	DetailTournamentRegistered    = "detail.tournament_registered"
+	DetailTournamentNotRegistered = "detail.tournament_not_registered"
+	DetailTournamentName          = "detail.tournament_name"
+	DetailTournamentBuyInAmount   = "detail.tournament_buy_in_amount"
+	DetailTournamentStack         = "detail.tournament_stack"
+	DetailTournamentRanking       = "detail.tournament_ranking"
+	DetailTournamentShares        = "detail.tournament_shares"
+	DetailTournamentSchedule      = "detail.tournament_schedule"
+)
+
+// detailKeys lists every detail key so the catalog can be checked for missing translations
+var detailKeys = []string{
+	DetailInsufficientFunds, DetailBetNegative, DetailBetZero, DetailBetBelowMin, DetailBetAboveMax, DetailBetType,
+	DetailBetNumber, DetailAutoplayRounds, DetailStopConditions, DetailAutoplayRunning, DetailAutoplayIdle,
+	DetailUserNotFound, DetailNotConnected, DetailForbiddenPlayer, DetailNoActiveSession, DetailRealityCheck,
+	DetailInvalidSeq, DetailUnknownRound,
+	DetailBonusAmount, DetailBonusWagering, DetailBonusValidity, DetailBonusInactive, DetailBonusActive,
+	DetailPromoCodeRequired, DetailPromoUnknown, DetailPromoExpired, DetailPromoExhausted, DetailPromoRedeemed,
+	DetailPromoCodeLength, DetailPromoKind, DetailPromoBetAmount, DetailPromoFreeBetCount, DetailPromoValidity,
+	DetailPromoLimits, DetailPromoExpiry, DetailPromoExists,
+	DetailFreeBetUnknown, DetailFreeBetUnavailable,
+	DetailVoidRoundID, DetailVoidReason, DetailVoidOperator, DetailVoidBalance, DetailVoidBonusClosed,
+	DetailVoidBonusBalance, DetailVoidBonusActive, DetailVoidGambled,
+	DetailGambleDisabled, DetailGambleBetType, DetailGambleNotOpen, DetailGambleRoundRequired, DetailGamblePending,
+	DetailGambleOpen, DetailGambleOtherSession, DetailGambleNoWinnings, DetailGambleNotCash, DetailGambleNotLast,
+	DetailGambleRepeated, DetailGambleAlreadyOpen, DetailGambleBalance, DetailGambleSettled,
+	DetailTableUnknown, DetailTableRoundRunning, DetailTableBettingClosed, DetailTableNotRolled, DetailTableNoRound,
+	DetailTableRoundOver, DetailTableNotSeated, DetailTableJoinFirst,
+	DetailTournamentUnknown, DetailTournamentEnded, DetailTournamentNotRunning, DetailTournamentBuyIn,
+	DetailTournamentRegistered, DetailTournamentNotRegistered, DetailTournamentName, DetailTournamentBuyInAmount,
+	DetailTournamentStack, DetailTournamentRanking, DetailTournamentShares, DetailTournamentSchedule,
+}
+
+// DetailKeys lists every detail key
+func DetailKeys() []string {
+	return append([]string(nil), detailKeys...)
+}
@@ -1,6 +1,9 @@
 package appErrors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // Error codes for game-related operations
 const (
@@ -11,13 +14,44 @@ const (
 	UserNotFoundErrorCode
 	ActiveSessionErrorCode
 	DiceRollErrorCode
+	RealityCheckPendingErrorCode
+	TableRoundErrorCode
+	TournamentErrorCode
+	BonusErrorCode
+	PromoErrorCode
+	VoidErrorCode
+	GambleErrorCode
+	ForbiddenErrorCode
+)
+
+// Constraints reported by field errors
+const (
+	ConstraintRequired = "required"
+	ConstraintMin      = "min"
+	ConstraintMax      = "max"
+	ConstraintOneOf    = "one_of"
 )
 
 // GameError provides structured error information for client feedback
+// Type is the stable string form of Code, clients should branch on it rather than on Message or Details
 type GameError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code      int          `json:"code"`
+	Type      string       `json:"type"`
+	Message   string       `json:"message"`
+	Details   string       `json:"details,omitempty"`
+	Retryable bool         `json:"retryable"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	// DetailKey names the catalog template translating Details, filled with DetailArgs
+	DetailKey  string        `json:"-"`
+	DetailArgs []interface{} `json:"-"`
+}
+
+// FieldError names a request field that failed validation and the constraint it broke
+type FieldError struct {
+	Field      string `json:"field"`
+	Constraint string `json:"constraint"`
+	Limit      string `json:"limit,omitempty"`
 }
 
 // Error satisfies the error interface and formats the error message
@@ -25,65 +59,118 @@ func (e *GameError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Message, e.Details)
 }
 
-// NewInternalError creates errors for unexpected system failures
-func NewInternalError(details string) *GameError {
+// WithField adds the field that failed validation, limit is the bound of min and max constraints or the allowed values
+func (e *GameError) WithField(field, constraint, limit string) *GameError {
+	e.Fields = append(e.Fields, FieldError{Field: field, Constraint: constraint, Limit: limit})
+	return e
+}
+
+// WithDetail sets the catalog template translating Details and the values of its placeholders
+func (e *GameError) WithDetail(key string, args ...interface{}) *GameError {
+	e.DetailKey = key
+	e.DetailArgs = args
+	return e
+}
+
+// WithRequestID returns a copy of the error carrying the correlation id of the request that failed
+func (e *GameError) WithRequestID(requestID string) *GameError {
+	withID := *e
+	withID.RequestID = requestID
+	return &withID
+}
+
+// AsGameError returns the game error of err, errors of other types are reported as internal errors
+func AsGameError(err error) *GameError {
+	gameErr := &GameError{}
+	if errors.As(err, &gameErr) {
+		return gameErr
+	}
+	return NewInternalError(err.Error())
+}
+
+// newGameError creates an error of a registered code
+func newGameError(code int, details string) *GameError {
+	definition := Definition(code)
 	return &GameError{
-		Code:    InternalErrorCode,
-		Message: "Internal server error",
-		Details: details,
+		Code:      code,
+		Type:      definition.Type,
+		Message:   definition.Message,
+		Details:   details,
+		Retryable: definition.Retryable,
 	}
 }
 
+// NewInternalError creates errors for unexpected system failures
+func NewInternalError(details string) *GameError {
+	return newGameError(InternalErrorCode, details)
+}
+
 // NewInvalidInputError creates errors for malformed request data
 func NewInvalidInputError(details string) *GameError {
-	return &GameError{
-		Code:    InvalidBetAmountErrorCode,
-		Message: "Invalid input provided",
-		Details: details,
-	}
+	return newGameError(InvalidInputErrorCode, details)
 }
 
 // NewInsufficientFundsError creates errors when bet exceeds player balance
 func NewInsufficientFundsError(details string) *GameError {
-	return &GameError{
-		Code:    InsufficientFundsErrorCode,
-		Message: "Bet amount exceeds available balance",
-		Details: details,
-	}
+	return newGameError(InsufficientFundsErrorCode, details)
 }
 
 // NewInvalidBetAmountError creates errors when bet doesn't meet game rules
 func NewInvalidBetAmountError(details string) *GameError {
-	return &GameError{
-		Code:    InvalidBetAmountErrorCode,
-		Message: "Invalid bet amount",
-		Details: details,
-	}
+	return newGameError(InvalidBetAmountErrorCode, details)
 }
 
 // NewUserNotFoundError creates errors for non-existent player lookups
 func NewUserNotFoundError(details string) *GameError {
-	return &GameError{
-		Code:    UserNotFoundErrorCode,
-		Message: "User not found",
-		Details: details,
-	}
+	return newGameError(UserNotFoundErrorCode, details)
 }
 
 // NewActiveSessionError creates errors for concurrent session conflicts
 func NewActiveSessionError(details string) *GameError {
-	return &GameError{
-		Code:    ActiveSessionErrorCode,
-		Message: "Active session error",
-		Details: details,
-	}
+	return newGameError(ActiveSessionErrorCode, details)
 }
 
 // NewDiceRollError creates errors for randomization failures
 func NewDiceRollError(details string) *GameError {
-	return &GameError{
-		Code:    DiceRollErrorCode,
-		Message: "Error rolling dice",
-		Details: details,
-	}
+	return newGameError(DiceRollErrorCode, details)
+}
+
+// NewRealityCheckPendingError creates errors when a play is attempted before acknowledging a reality check
+func NewRealityCheckPendingError(details string) *GameError {
+	return newGameError(RealityCheckPendingErrorCode, details)
+}
+
+// NewTableRoundError creates errors for actions not allowed in the current state of a table round
+func NewTableRoundError(details string) *GameError {
+	return newGameError(TableRoundErrorCode, details)
+}
+
+// NewTournamentError creates errors for tournament registration and play rule violations
+func NewTournamentError(details string) *GameError {
+	return newGameError(TournamentErrorCode, details)
+}
+
+// NewBonusError creates errors for bonus grants and bonus funds that can no longer be used
+func NewBonusError(details string) *GameError {
+	return newGameError(BonusErrorCode, details)
+}
+
+// NewPromoError creates errors for promo codes that cannot be redeemed and free bets that cannot be played
+func NewPromoError(details string) *GameError {
+	return newGameError(PromoErrorCode, details)
+}
+
+// NewVoidError creates errors for rounds that cannot be found or reversed when voided
+func NewVoidError(details string) *GameError {
+	return newGameError(VoidErrorCode, details)
+}
+
+// NewGambleError creates errors for gambles that cannot be started, rolled or collected
+func NewGambleError(details string) *GameError {
+	return newGameError(GambleErrorCode, details)
+}
+
+// NewForbiddenError creates errors for requests acting on another player than the authenticated one
+func NewForbiddenError(details string) *GameError {
+	return newGameError(ForbiddenErrorCode, details)
 }
@@ -0,0 +1,59 @@
+package appErrors
+
+// ErrorDefinition documents an error code for client developers
+type ErrorDefinition struct {
+	Code        int    `json:"code"`
+	Type        string `json:"type"`
+	Message     string `json:"message"`
+	Retryable   bool   `json:"retryable"`
+	Description string `json:"description"`
+}
+
+// definitions registers every error code, in code order. Retryable errors may succeed when the same request is sent again
+var definitions = []ErrorDefinition{
+	{Code: InternalErrorCode, Type: "internal", Message: "Internal server error",
+		Description: "Unexpected server failure, a play may still have been settled, check the wallet or history before sending it again"},
+	{Code: InvalidInputErrorCode, Type: "invalid_input", Message: "Invalid input provided",
+		Description: "The request is malformed or a field is out of range, see fields"},
+	{Code: InsufficientFundsErrorCode, Type: "insufficient_funds", Message: "Bet amount exceeds available balance",
+		Description: "The balance does not cover the stake"},
+	{Code: InvalidBetAmountErrorCode, Type: "invalid_bet_amount", Message: "Invalid bet amount",
+		Description: "The stake is outside the betting limits of the player, see fields"},
+	{Code: UserNotFoundErrorCode, Type: "user_not_found", Message: "User not found",
+		Description: "No player has the given client id"},
+	{Code: ActiveSessionErrorCode, Type: "active_session", Message: "Active session error",
+		Description: "The player has an autoplay, a gamble or a session in a state that blocks the request"},
+	{Code: DiceRollErrorCode, Type: "dice_roll", Message: "Error rolling dice", Retryable: true,
+		Description: "The dice could not be rolled, nothing was charged"},
+	{Code: RealityCheckPendingErrorCode, Type: "reality_check_pending", Message: "Reality check must be acknowledged",
+		Description: "Send reality_check_ack before playing again"},
+	{Code: TableRoundErrorCode, Type: "table_round", Message: "Table round error", Retryable: true,
+		Description: "The table round does not accept the action in its current state, retry on the next round"},
+	{Code: TournamentErrorCode, Type: "tournament", Message: "Tournament error",
+		Description: "The tournament rules do not allow the registration or play"},
+	{Code: BonusErrorCode, Type: "bonus", Message: "Bonus error",
+		Description: "The bonus cannot be granted or its funds can no longer be used"},
+	{Code: PromoErrorCode, Type: "promo", Message: "Promotion error",
+		Description: "The promo code cannot be redeemed or the free bet cannot be played"},
+	{Code: VoidErrorCode, Type: "void", Message: "Round void error",
+		Description: "The round cannot be found or its result cannot be reversed"},
+	{Code: GambleErrorCode, Type: "gamble", Message: "Gamble error",
+		Description: "The gamble cannot be started, rolled or collected"},
+	{Code: ForbiddenErrorCode, Type: "forbidden", Message: "Forbidden",
+		Description: "The request names another player than the one the connection is authenticated as"},
+}
+
+// Definitions lists every error code, in code order
+func Definitions() []ErrorDefinition {
+	return append([]ErrorDefinition(nil), definitions...)
+}
+
+// Definition returns the registered definition of a code, unknown codes are described as internal errors
+func Definition(code int) ErrorDefinition {
+	for _, definition := range definitions {
+		if definition.Code == code {
+			return definition
+		}
+	}
+	return definitions[0]
+}
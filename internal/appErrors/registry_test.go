@@ -0,0 +1,68 @@
+package appErrors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinitions_AreUnique(t *testing.T) {
+	codes := map[int]bool{}
+	types := map[string]bool{}
+	for _, definition := range Definitions() {
+		assert.False(t, codes[definition.Code], "duplicate code %d", definition.Code)
+		assert.False(t, types[definition.Type], "duplicate type %s", definition.Type)
+		assert.NotEmpty(t, definition.Description)
+		codes[definition.Code] = true
+		types[definition.Type] = true
+	}
+	assert.Len(t, codes, ForbiddenErrorCode-InternalErrorCode+1)
+}
+
+func TestConstructors_UseRegisteredCode(t *testing.T) {
+	tests := []struct {
+		err          *GameError
+		expectedCode int
+	}{
+		{err: NewInternalError(""), expectedCode: InternalErrorCode},
+		{err: NewInvalidInputError(""), expectedCode: InvalidInputErrorCode},
+		{err: NewInsufficientFundsError(""), expectedCode: InsufficientFundsErrorCode},
+		{err: NewInvalidBetAmountError(""), expectedCode: InvalidBetAmountErrorCode},
+		{err: NewUserNotFoundError(""), expectedCode: UserNotFoundErrorCode},
+		{err: NewActiveSessionError(""), expectedCode: ActiveSessionErrorCode},
+		{err: NewDiceRollError(""), expectedCode: DiceRollErrorCode},
+		{err: NewRealityCheckPendingError(""), expectedCode: RealityCheckPendingErrorCode},
+		{err: NewTableRoundError(""), expectedCode: TableRoundErrorCode},
+		{err: NewTournamentError(""), expectedCode: TournamentErrorCode},
+		{err: NewBonusError(""), expectedCode: BonusErrorCode},
+		{err: NewPromoError(""), expectedCode: PromoErrorCode},
+		{err: NewVoidError(""), expectedCode: VoidErrorCode},
+		{err: NewGambleError(""), expectedCode: GambleErrorCode},
+		{err: NewForbiddenError(""), expectedCode: ForbiddenErrorCode},
+	}
+
+	for _, tt := range tests {
+		definition := Definition(tt.expectedCode)
+		t.Run(definition.Type, func(t *testing.T) {
+			assert.Equal(t, tt.expectedCode, tt.err.Code)
+			assert.Equal(t, definition.Type, tt.err.Type)
+			assert.Equal(t, definition.Message, tt.err.Message)
+			assert.Equal(t, definition.Retryable, tt.err.Retryable)
+		})
+	}
+}
+
+func TestAsGameError(t *testing.T) {
+	invalid := NewInvalidInputError("Invalid seq").WithField("seq", ConstraintMin, "1")
+	assert.Same(t, invalid, AsGameError(invalid))
+
+	internal := AsGameError(errors.New("connection refused"))
+	assert.Equal(t, InternalErrorCode, internal.Code)
+	assert.False(t, internal.Retryable)
+
+	withID := invalid.WithRequestID("req-1")
+	assert.Equal(t, "req-1", withID.RequestID)
+	assert.Empty(t, invalid.RequestID)
+	assert.Equal(t, []FieldError{{Field: "seq", Constraint: ConstraintMin, Limit: "1"}}, withID.Fields)
+}
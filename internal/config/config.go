@@ -5,6 +5,8 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // PostgresConfig holds database connection parameters
@@ -32,24 +34,153 @@ func (p PostgresConfig) String() string {
 // ServerConfig contains HTTP server settings
 type ServerConfig struct {
 	Port string
+	// AuthSecret signs the player tokens guarding the player WebSocket, SSE and REST endpoints,
+	// they refuse every request while it is empty. AuthTokenTTL is how long issued tokens stay valid
+	AuthSecret   string
+	AuthTokenTTL time.Duration
+	// ReadBufferSize and WriteBufferSize size the I/O buffers of each WebSocket connection in bytes
+	ReadBufferSize  int
+	WriteBufferSize int
+	// MaxMessageSize is the largest message accepted from a client in bytes, larger WebSocket messages close
+	// the connection with the message too big close code
+	MaxMessageSize int64
+	// EnableCompression negotiates permessage-deflate with the clients offering it, at CompressionLevel
+	EnableCompression bool
+	CompressionLevel  int
+	// PingInterval is how often WebSocket connections are pinged and SSE streams sent a keepalive
+	// ReadTimeout closes WebSocket connections silent for longer, so it must exceed PingInterval
+	// WriteTimeout bounds every write to a client
+	PingInterval time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
 }
 
-// GameConfig defines the betting constraints
+// Validate refuses keepalive settings that would panic the ping tickers or drop healthy connections
+func (c ServerConfig) Validate() error {
+	if c.PingInterval <= 0 {
+		return fmt.Errorf("WS_PING_INTERVAL must be positive, got %s", c.PingInterval)
+	}
+	if c.ReadTimeout <= c.PingInterval {
+		return fmt.Errorf("WS_READ_TIMEOUT %s must exceed WS_PING_INTERVAL %s", c.ReadTimeout, c.PingInterval)
+	}
+	return nil
+}
+
+// VariantConfig declares the payout multiplier of a game variant and the return to player it is expected to produce
+type VariantConfig struct {
+	Multiplier float64
+	TargetRTP  float64
+}
+
+// JackpotConfig controls the optional progressive jackpot funded by a share of every stake
+// The jackpot is won when every one of the jackpot dice lands on its highest face
+type JackpotConfig struct {
+	Enabled           bool
+	ContributionRate  float64
+	Dice              int
+	SeedAmount        float64
+	BroadcastInterval time.Duration
+}
+
+// BonusConfig controls how stakes consume bonus funds and how often expired bonuses are forfeited
+// StakeOrder is either cash_first or bonus_first
+type BonusConfig struct {
+	StakeOrder     string
+	ExpiryInterval time.Duration
+}
+
+// AutoplayConfig caps the rounds of an autoplay series and paces them, zero rounds disables autoplay
+type AutoplayConfig struct {
+	MaxRounds  int
+	RoundDelay time.Duration
+}
+
+// GambleConfig caps the double-or-nothing ladder offered after a win, zero steps disables the gamble
+// Each won step multiplies the amount by Multiplier, which keeps the house edge of the ladder below MaxRTP
+type GambleConfig struct {
+	MaxSteps   int
+	Multiplier float64
+}
+
+// TableConfig controls the shared multiplayer tables, zero tables disables them
+type TableConfig struct {
+	Count         int
+	BettingWindow time.Duration
+	Intermission  time.Duration
+}
+
+// TournamentConfig controls how often finished tournaments are closed and their prizes paid
+type TournamentConfig struct {
+	CloseInterval time.Duration
+}
+
+// LocaleConfig selects the locale of clients that declare none and the currency amounts are formatted in
+type LocaleConfig struct {
+	Default  string
+	Currency string
+}
+
+// DeliveryConfig bounds how long settlement messages are kept for redelivery and how often expired ones are purged
+type DeliveryConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// PresenceConfig bounds how long offline players are remembered and how often they are evicted
+type PresenceConfig struct {
+	TTL           time.Duration
+	EvictInterval time.Duration
+}
+
+// AdminConfig protects the operator endpoints, an empty token disables them
+type AdminConfig struct {
+	Token string
+}
+
+// GameConfig defines the betting constraints and the payout rules of each game variant
 type GameConfig struct {
 	MinBetAmount float64
 	MaxBetAmount float64
+	MaxRTP       float64
+	Variants     map[string]VariantConfig
+	Jackpot      JackpotConfig
+	Bonus        BonusConfig
+	Gamble       GambleConfig
+	Autoplay     AutoplayConfig
+}
+
+// RealityCheckConfig defines how often players are reminded of their play time and net result
+type RealityCheckConfig struct {
+	Interval time.Duration
+}
+
+// DiceConfig selects the dice implementation, deterministic modes are meant for non-production environments
+type DiceConfig struct {
+	Mode   string
+	Seed   uint64
+	Script []int
 }
 
 // Config aggregates all application configuration categories
 type Config struct {
-	Postgres PostgresConfig
-	Server   ServerConfig
-	Game     GameConfig
+	Environment  string
+	Postgres     PostgresConfig
+	Server       ServerConfig
+	Game         GameConfig
+	RealityCheck RealityCheckConfig
+	Dice         DiceConfig
+	Tables       TableConfig
+	Tournaments  TournamentConfig
+	Delivery     DeliveryConfig
+	Presence     PresenceConfig
+	Admin        AdminConfig
+	Locale       LocaleConfig
 }
 
 // New initializes configuration with environment variables or defaults
 func New() *Config {
 	return &Config{
+		Environment: getEnv("APP_ENV", "production"),
 		Postgres: PostgresConfig{
 			host:     getEnv("DB_HOST", ""),
 			user:     getEnv("DB_USER", ""),
@@ -58,10 +189,83 @@ func New() *Config {
 			ssl:      getEnv("DB_SSL", "disable"),
 			port:     getEnvAsInt("DB_PORT", 5432),
 		},
-		Server: ServerConfig{Port: getEnv("SERVER_PORT", "80")},
+		Server: ServerConfig{
+			Port:              getEnv("SERVER_PORT", "80"),
+			AuthSecret:        getEnv("AUTH_SECRET", ""),
+			AuthTokenTTL:      getEnvAsDuration("AUTH_TOKEN_TTL", 24*time.Hour),
+			ReadBufferSize:    getEnvAsInt("WS_READ_BUFFER_SIZE", 1024),
+			WriteBufferSize:   getEnvAsInt("WS_WRITE_BUFFER_SIZE", 1024),
+			MaxMessageSize:    int64(getEnvAsInt("WS_MAX_MESSAGE_SIZE", 64*1024)),
+			EnableCompression: getEnvAsBool("WS_COMPRESSION", false),
+			CompressionLevel:  getEnvAsInt("WS_COMPRESSION_LEVEL", 1),
+			PingInterval:      getEnvAsDuration("WS_PING_INTERVAL", 30*time.Second),
+			ReadTimeout:       getEnvAsDuration("WS_READ_TIMEOUT", 60*time.Second),
+			WriteTimeout:      getEnvAsDuration("WS_WRITE_TIMEOUT", 10*time.Second),
+		},
 		Game: GameConfig{
 			MinBetAmount: getEnvAsFloat("MIN_BET", 10.0),
 			MaxBetAmount: getEnvAsFloat("MAX_BET", 100.0),
+			MaxRTP:       getEnvAsFloat("MAX_RTP", 0.99),
+			Variants: map[string]VariantConfig{
+				"parity": {
+					Multiplier: getEnvAsFloat("PARITY_MULTIPLIER", 1.96),
+					TargetRTP:  getEnvAsFloat("PARITY_TARGET_RTP", 0.98),
+				},
+				"exact": {
+					Multiplier: getEnvAsFloat("EXACT_MULTIPLIER", 5.7),
+					TargetRTP:  getEnvAsFloat("EXACT_TARGET_RTP", 0.95),
+				},
+			},
+			Jackpot: JackpotConfig{
+				Enabled:           getEnvAsBool("JACKPOT_ENABLED", false),
+				ContributionRate:  getEnvAsFloat("JACKPOT_CONTRIBUTION_RATE", 0.01),
+				Dice:              getEnvAsInt("JACKPOT_DICE", 3),
+				SeedAmount:        getEnvAsFloat("JACKPOT_SEED", 0),
+				BroadcastInterval: getEnvAsDuration("JACKPOT_BROADCAST_INTERVAL", 5*time.Second),
+			},
+			Bonus: BonusConfig{
+				StakeOrder:     getEnv("BONUS_STAKE_ORDER", "cash_first"),
+				ExpiryInterval: getEnvAsDuration("BONUS_EXPIRY_INTERVAL", time.Minute),
+			},
+			Gamble: GambleConfig{
+				MaxSteps:   getEnvAsInt("GAMBLE_MAX_STEPS", 5),
+				Multiplier: getEnvAsFloat("GAMBLE_MULTIPLIER", 1.96),
+			},
+			Autoplay: AutoplayConfig{
+				MaxRounds:  getEnvAsInt("MAX_AUTOPLAY_ROUNDS", 100),
+				RoundDelay: getEnvAsDuration("AUTOPLAY_ROUND_DELAY", time.Second),
+			},
+		},
+		RealityCheck: RealityCheckConfig{
+			Interval: getEnvAsDuration("REALITY_CHECK_INTERVAL", 30*time.Minute),
+		},
+		Dice: DiceConfig{
+			Mode:   getEnv("DICE_MODE", "crypto"),
+			Seed:   uint64(getEnvAsInt("DICE_SEED", 1)),
+			Script: getEnvAsIntList("DICE_SCRIPT"),
+		},
+		Tables: TableConfig{
+			Count:         getEnvAsInt("TABLE_COUNT", 1),
+			BettingWindow: getEnvAsDuration("TABLE_BETTING_WINDOW", 15*time.Second),
+			Intermission:  getEnvAsDuration("TABLE_INTERMISSION", 5*time.Second),
+		},
+		Tournaments: TournamentConfig{
+			CloseInterval: getEnvAsDuration("TOURNAMENT_CLOSE_INTERVAL", 30*time.Second),
+		},
+		Delivery: DeliveryConfig{
+			Retention:     getEnvAsDuration("SETTLEMENT_RETENTION", 24*time.Hour),
+			PurgeInterval: getEnvAsDuration("SETTLEMENT_PURGE_INTERVAL", 10*time.Minute),
+		},
+		Presence: PresenceConfig{
+			TTL:           getEnvAsDuration("PRESENCE_TTL", 24*time.Hour),
+			EvictInterval: getEnvAsDuration("PRESENCE_EVICT_INTERVAL", 10*time.Minute),
+		},
+		Admin: AdminConfig{
+			Token: getEnv("ADMIN_TOKEN", ""),
+		},
+		Locale: LocaleConfig{
+			Default:  getEnv("DEFAULT_LOCALE", "en"),
+			Currency: getEnv("CURRENCY", "EUR"),
 		},
 	}
 }
@@ -92,3 +296,39 @@ func getEnvAsFloat(name string, defaultVal float64) float64 {
 	log.Printf("could not parse %s to float, using default value of %f", name, defaultVal)
 	return defaultVal
 }
+
+// getEnvAsBool parses boolean environment variables with fallback
+func getEnvAsBool(name string, defaultVal bool) bool {
+	valueStr := getEnv(name, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultVal
+}
+
+// getEnvAsDuration parses duration environment variables such as "30m" with logging on parse failures
+func getEnvAsDuration(name string, defaultVal time.Duration) time.Duration {
+	valueStr := getEnv(name, "")
+	if value, err := time.ParseDuration(valueStr); err == nil {
+		return value
+	}
+	log.Printf("could not parse %s to duration, using default value of %s", name, defaultVal)
+	return defaultVal
+}
+
+// getEnvAsIntList parses comma separated integer lists such as "1,6,3", skipping invalid entries
+func getEnvAsIntList(name string) []int {
+	var values []int
+	for _, valueStr := range strings.Split(getEnv(name, ""), ",") {
+		if valueStr = strings.TrimSpace(valueStr); valueStr == "" {
+			continue
+		}
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			log.Printf("could not parse %s entry %q to int, skipping it", name, valueStr)
+			continue
+		}
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerConfigValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		pingInterval time.Duration
+		readTimeout  time.Duration
+		expectError  bool
+	}{
+		{name: "valid", pingInterval: 30 * time.Second, readTimeout: 60 * time.Second},
+		{name: "zero_ping_interval", pingInterval: 0, readTimeout: 60 * time.Second, expectError: true},
+		{name: "negative_ping_interval", pingInterval: -time.Second, readTimeout: 60 * time.Second, expectError: true},
+		{name: "read_timeout_equal_to_ping_interval", pingInterval: 30 * time.Second, readTimeout: 30 * time.Second, expectError: true},
+		{name: "read_timeout_below_ping_interval", pingInterval: 30 * time.Second, readTimeout: 10 * time.Second, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ServerConfig{PingInterval: tt.pingInterval, ReadTimeout: tt.readTimeout}.Validate()
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"slices"
 	"time"
 )
 
@@ -10,20 +11,32 @@ type MessageType string
 // BetType represents the available betting options
 type BetType string
 
+// messageTypes lists every supported message type
+var messageTypes = []MessageType{
+	MessageTypeWallet, MessageTypePlay, MessageTypeEndPlay, MessageTypeError,
+	MessageTypeRealityCheck, MessageTypeRealityCheckAck, MessageTypeLimits,
+	MessageTypeAutoplay, MessageTypeAutoplayRound, MessageTypeAutoplayEnd, MessageTypeStopAutoplay,
+	MessageTypeJackpot, MessageTypeTableJoin, MessageTypeTableLeave, MessageTypeTableBet,
+	MessageTypeTableRound, MessageTypeTableSettlement, MessageTypeTableBetSettled, MessageTypeTournaments, MessageTypeTournamentRegister,
+	MessageTypeTournamentPlay, MessageTypeTournamentLeaderboard, MessageTypeRedeemPromo, MessageTypeFreeBets,
+	MessageTypeRoundVoided, MessageTypeGamble, MessageTypeGambleCollect,
+	MessageTypeAchievements, MessageTypeAchievementUnlocked, MessageTypeHello, MessageTypeAck,
+}
+
+// MessageTypes lists every supported message type
+func MessageTypes() []MessageType {
+	return append([]MessageType(nil), messageTypes...)
+}
+
 // IsValid checks if the message type is among the supported operations
 func (m MessageType) IsValid() bool {
-	switch m {
-	case MessageTypeWallet, MessageTypePlay, MessageTypeEndPlay, MessageTypeError:
-		return true
-	default:
-		return false
-	}
+	return slices.Contains(messageTypes, m)
 }
 
 // IsValid ensures the bet type matches the allowed game rules
 func (m BetType) IsValid() bool {
 	switch m {
-	case Odd, Even:
+	case Odd, Even, Exact:
 		return true
 	default:
 		return false
@@ -38,37 +51,180 @@ const (
 	MessageTypeEndPlay MessageType = "endplay"
 	Even               BetType     = "even"
 	Odd                BetType     = "odd"
+	Exact              BetType     = "exact"
+
+	MessageTypeRealityCheck    MessageType = "reality_check"
+	MessageTypeRealityCheckAck MessageType = "reality_check_ack"
+	MessageTypeLimits          MessageType = "limits"
+	MessageTypeAutoplay        MessageType = "autoplay"
+	MessageTypeAutoplayRound   MessageType = "autoplay_round"
+	MessageTypeAutoplayEnd     MessageType = "autoplay_end"
+	MessageTypeStopAutoplay    MessageType = "stop_autoplay"
+	MessageTypeJackpot         MessageType = "jackpot"
+	MessageTypeTableJoin       MessageType = "table_join"
+	MessageTypeTableLeave      MessageType = "table_leave"
+	MessageTypeTableBet        MessageType = "table_bet"
+	MessageTypeTableRound      MessageType = "table_round"
+	MessageTypeTableSettlement MessageType = "table_settlement"
+	MessageTypeTableBetSettled MessageType = "table_bet_settled"
+
+	MessageTypeTournaments           MessageType = "tournaments"
+	MessageTypeTournamentRegister    MessageType = "tournament_register"
+	MessageTypeTournamentPlay        MessageType = "tournament_play"
+	MessageTypeTournamentLeaderboard MessageType = "tournament_leaderboard"
+
+	MessageTypeRedeemPromo MessageType = "redeem_promo"
+	MessageTypeFreeBets    MessageType = "free_bets"
+
+	MessageTypeRoundVoided MessageType = "round_voided"
+
+	MessageTypeGamble        MessageType = "gamble"
+	MessageTypeGambleCollect MessageType = "gamble_collect"
+
+	MessageTypeAchievements        MessageType = "achievements"
+	MessageTypeAchievementUnlocked MessageType = "achievement_unlocked"
+
+	MessageTypeHello MessageType = "hello"
+	MessageTypeAck   MessageType = "ack"
+)
+
+// TableRoundState represents the stage of a shared table round
+type TableRoundState string
+
+// Table rounds move from betting open to settled, or to voided when settlement fails
+const (
+	RoundBettingOpen   TableRoundState = "betting_open"
+	RoundBettingClosed TableRoundState = "betting_closed"
+	RoundRolled        TableRoundState = "rolled"
+	RoundSettled       TableRoundState = "settled"
+	RoundVoided        TableRoundState = "voided"
 )
 
+// AutoplayStopReason explains why an autoplay series ended
+type AutoplayStopReason string
+
+// Reasons reported when an autoplay series ends
+const (
+	AutoplayCompleted    AutoplayStopReason = "completed"
+	AutoplayStopped      AutoplayStopReason = "stopped"
+	AutoplayLossLimit    AutoplayStopReason = "loss_limit"
+	AutoplayWinLimit     AutoplayStopReason = "win_limit"
+	AutoplayBalanceLimit AutoplayStopReason = "balance_limit"
+	AutoplayRealityCheck AutoplayStopReason = "reality_check"
+	AutoplayError        AutoplayStopReason = "error"
+)
+
+// PlayerTier groups players sharing the same betting limits
+type PlayerTier string
+
+// Player tiers known by the system, limits for each one are stored in the database
+const (
+	TierStandard   PlayerTier = "standard"
+	TierVIP        PlayerTier = "vip"
+	TierRestricted PlayerTier = "restricted"
+)
+
+// Amount marks a message or error detail argument holding money, formatted in the currency and locale of the player
+type Amount float64
+
 // WalletRequest initiates a balance check operation
 type WalletRequest struct {
 	ClientID int `json:"client_id"`
 }
 
 // WalletResponse carries the current balance state
+// Balance is the withdrawable cash, bonus funds are reported separately until their wagering is completed
 type WalletResponse struct {
-	ClientID int     `json:"client_id"`
-	Balance  float64 `json:"balance"`
+	ClientID     int          `json:"client_id"`
+	Balance      float64      `json:"balance"`
+	BonusBalance float64      `json:"bonus_balance"`
+	Bonus        *PlayerBonus `json:"bonus,omitempty"`
+}
+
+// BonusStakeOrder defines which funds are consumed first when a stake is covered by both wallets
+type BonusStakeOrder string
+
+// Stakes are taken from the cash balance first or from the bonus balance first
+const (
+	BonusStakeCashFirst  BonusStakeOrder = "cash_first"
+	BonusStakeBonusFirst BonusStakeOrder = "bonus_first"
+)
+
+// BonusStatus is the lifecycle state of a granted bonus
+type BonusStatus string
+
+// A bonus stays active until it is wagered through and converted to cash, or forfeited on expiry or when depleted
+const (
+	BonusActive    BonusStatus = "active"
+	BonusCompleted BonusStatus = "completed"
+	BonusForfeited BonusStatus = "forfeited"
+)
+
+// PlayerBonus holds non-withdrawable bonus funds and the progress of their wagering requirement
+// The requirement is the granted amount times the wagering multiplier, every stake counting towards it
+type PlayerBonus struct {
+	BonusID             int         `json:"bonus_id"`
+	PlayerID            int         `json:"player_id"`
+	Amount              float64     `json:"amount"`
+	GrantedAmount       float64     `json:"granted_amount"`
+	WageringRequirement float64     `json:"wagering_requirement"`
+	Wagered             float64     `json:"wagered"`
+	Status              BonusStatus `json:"status"`
+	GrantedAt           time.Time   `json:"granted_at"`
+	ExpiresAt           time.Time   `json:"expires_at"`
+}
+
+// GrantBonusRequest asks to grant bonus funds to a player, converting to cash once wagered WageringMultiplier times
+// within ValidForSeconds
+type GrantBonusRequest struct {
+	Amount             float64 `json:"amount"`
+	WageringMultiplier float64 `json:"wagering_multiplier"`
+	ValidForSeconds    int64   `json:"valid_for_seconds"`
 }
 
 // PlayRequest encapsulates the necessary information to start a game round
+// BetNumber is only used by exact bets and holds the dice face the player bets on
+// FreeBetID plays a free bet instead, whose fixed stake and bet replace the ones of the request
 type PlayRequest struct {
 	ClientID  int     `json:"client_id"`
 	BetAmount float64 `json:"bet_amount"`
 	BetType   BetType `json:"bet_type"`
+	BetNumber int     `json:"bet_number,omitempty"`
+	FreeBetID int     `json:"free_bet_id,omitempty"`
+
+	// AutoplayRound is the round of the autoplay series the play belongs to, 0 for single plays
+	AutoplayRound int `json:"-"`
 }
 
 // PlayResponse contains the game round results and updated balance
 type PlayResponse struct {
+	SessionID  int     `json:"session_id"`
+	RoundID    int     `json:"round_id"`
 	DiceResult int     `json:"dice_result"`
 	Won        bool    `json:"won"`
 	Balance    float64 `json:"balance"`
 	BetAmount  float64 `json:"bet_amount"`
+	Payout     float64 `json:"payout"`
+	NetResult  float64 `json:"net_result"`
+	JackpotWon float64 `json:"jackpot_won,omitempty"`
+
+	BonusBalance   float64 `json:"bonus_balance,omitempty"`
+	BonusConverted float64 `json:"bonus_converted,omitempty"`
+	FreeBetID      int     `json:"free_bet_id,omitempty"`
+
+	// Achievements unlocked by the round, pushed to the client as separate achievement_unlocked messages
+	Achievements []Achievement `json:"-"`
+	// Seq is the sequence number of the settlement message reporting the play, stored with the round
+	Seq int64 `json:"-"`
 }
 
-// EndPlayResponse confirms the termination of a game session
+// EndPlayResponse confirms the termination of a game session with the summary of its rounds
 type EndPlayResponse struct {
 	ClientID int `json:"client_id"`
+	SessionSummary
+
+	// CollectedGamble is the gamble left open on the last round and collected before the session closed
+	CollectedGamble *GambleResponse `json:"-"`
 }
 
 // EndPlayRequest signals the intention to terminate the current game session
@@ -76,33 +232,720 @@ type EndPlayRequest struct {
 	ClientID int `json:"client_id"`
 }
 
+// LimitsRequest asks for the betting limits that apply to a player
+type LimitsRequest struct {
+	ClientID int `json:"client_id"`
+}
+
+// LimitsResponse carries the resolved betting limits of a player
+type LimitsResponse struct {
+	ClientID     int        `json:"client_id"`
+	Tier         PlayerTier `json:"tier"`
+	MinBetAmount float64    `json:"min_bet_amount"`
+	MaxBetAmount float64    `json:"max_bet_amount"`
+}
+
+// TierLimits holds the limits stored for a player tier, nil values fall back to the global configuration
+type TierLimits struct {
+	Tier         PlayerTier
+	MinBetAmount *float64
+	MaxBetAmount *float64
+}
+
+// JackpotResponse carries the current value of the progressive jackpot pool
+// LastWinAmount is only set when the message announces a jackpot win
+type JackpotResponse struct {
+	Amount        float64 `json:"amount"`
+	LastWinAmount float64 `json:"last_win_amount,omitempty"`
+	Message       string  `json:"message,omitempty"`
+}
+
+// AutoplayRequest queues a series of identical bets with optional stop conditions
+// A zero stop condition is disabled
+type AutoplayRequest struct {
+	ClientID           int     `json:"client_id"`
+	Rounds             int     `json:"rounds"`
+	BetAmount          float64 `json:"bet_amount"`
+	BetType            BetType `json:"bet_type"`
+	BetNumber          int     `json:"bet_number,omitempty"`
+	StopOnLoss         float64 `json:"stop_on_loss,omitempty"`
+	StopOnWin          float64 `json:"stop_on_win,omitempty"`
+	StopOnBalanceBelow float64 `json:"stop_on_balance_below,omitempty"`
+}
+
+// PlayRequest builds the bet placed on every autoplay round
+func (r AutoplayRequest) PlayRequest() PlayRequest {
+	return PlayRequest{
+		ClientID:  r.ClientID,
+		BetAmount: r.BetAmount,
+		BetType:   r.BetType,
+		BetNumber: r.BetNumber,
+	}
+}
+
+// AutoplayRoundResponse streams the result of a single autoplay round
+type AutoplayRoundResponse struct {
+	Round int `json:"round"`
+	PlayResponse
+}
+
+// AutoplayEndResponse summarizes a finished autoplay series
+type AutoplayEndResponse struct {
+	ClientID     int                `json:"client_id"`
+	RoundsPlayed int                `json:"rounds_played"`
+	NetResult    float64            `json:"net_result"`
+	Reason       AutoplayStopReason `json:"reason"`
+	Message      string             `json:"message"`
+}
+
+// StopAutoplayRequest asks the server to stop the running autoplay series
+type StopAutoplayRequest struct {
+	ClientID int `json:"client_id"`
+}
+
+// TableJoinRequest subscribes the player to the rounds of a table
+type TableJoinRequest struct {
+	ClientID int `json:"client_id"`
+	TableID  int `json:"table_id"`
+}
+
+// TableLeaveRequest unsubscribes the player from a table
+type TableLeaveRequest struct {
+	ClientID int `json:"client_id"`
+	TableID  int `json:"table_id"`
+}
+
+// TableLeaveResponse confirms the player left the table
+type TableLeaveResponse struct {
+	ClientID int `json:"client_id"`
+	TableID  int `json:"table_id"`
+}
+
+// TableBetRequest places a bet on the current round of a table
+type TableBetRequest struct {
+	ClientID  int     `json:"client_id"`
+	TableID   int     `json:"table_id"`
+	BetAmount float64 `json:"bet_amount"`
+	BetType   BetType `json:"bet_type"`
+	BetNumber int     `json:"bet_number,omitempty"`
+}
+
+// PlayRequest builds the equivalent single player bet, used to share the bet validation rules
+func (r TableBetRequest) PlayRequest() PlayRequest {
+	return PlayRequest{
+		ClientID:  r.ClientID,
+		BetAmount: r.BetAmount,
+		BetType:   r.BetType,
+		BetNumber: r.BetNumber,
+	}
+}
+
+// TableBetResponse confirms a bet was accepted and its stake debited
+type TableBetResponse struct {
+	TableID   int     `json:"table_id"`
+	RoundID   int     `json:"round_id"`
+	BetAmount float64 `json:"bet_amount"`
+	Balance   float64 `json:"balance"`
+}
+
+// TableBet is a bet placed by a player on a table round
+type TableBet struct {
+	BetID     int     `json:"bet_id"`
+	RoundID   int     `json:"round_id"`
+	PlayerID  int     `json:"player_id"`
+	BetAmount float64 `json:"bet_amount"`
+	BetType   BetType `json:"bet_type"`
+	BetNumber int     `json:"bet_number,omitempty"`
+}
+
+// TableBetResult is the settled outcome of a table bet
+type TableBetResult struct {
+	TableBet
+	Won     bool    `json:"won"`
+	Payout  float64 `json:"payout"`
+	Balance float64 `json:"balance"`
+}
+
+// TableRound is the shared round of a table, every bet is settled against the same dice result
+type TableRound struct {
+	RoundID    int             `json:"round_id"`
+	TableID    int             `json:"table_id"`
+	State      TableRoundState `json:"state"`
+	DiceResult int             `json:"dice_result,omitempty"`
+	ClosesAt   time.Time       `json:"closes_at"`
+	Bets       []TableBet      `json:"-"`
+}
+
+// TableRoundResponse is broadcast to the table on every state change of its round
+type TableRoundResponse struct {
+	TableID    int             `json:"table_id"`
+	RoundID    int             `json:"round_id"`
+	State      TableRoundState `json:"state"`
+	DiceResult int             `json:"dice_result,omitempty"`
+	ClosesAt   time.Time       `json:"closes_at"`
+	BetsCount  int             `json:"bets_count"`
+}
+
+// TableSettlementResponse is broadcast to the table once all bets of a round are settled
+// The same shape reports a player their own results in table_bet_settled messages
+type TableSettlementResponse struct {
+	TableID    int              `json:"table_id"`
+	RoundID    int              `json:"round_id"`
+	DiceResult int              `json:"dice_result"`
+	Results    []TableBetResult `json:"results"`
+	Message    string           `json:"message,omitempty"`
+
+	// Messages are the table_bet_settled messages stored for each player with the settlement
+	Messages []SettlementMessage `json:"-"`
+}
+
+// PlayerResults splits the settlement into the results of each player, in the order their first bet was placed
+func (s TableSettlementResponse) PlayerResults() []TableSettlementResponse {
+	var split []TableSettlementResponse
+	index := make(map[int]int)
+	for _, result := range s.Results {
+		i, ok := index[result.PlayerID]
+		if !ok {
+			i = len(split)
+			index[result.PlayerID] = i
+			split = append(split, TableSettlementResponse{TableID: s.TableID, RoundID: s.RoundID, DiceResult: s.DiceResult})
+		}
+		split[i].Results = append(split[i].Results, result)
+	}
+	return split
+}
+
+// PromoKind defines the reward granted by a promo code
+type PromoKind string
+
+// Promo codes grant free bets or bonus funds
+const (
+	PromoFreeBet PromoKind = "free_bet"
+	PromoBonus   PromoKind = "bonus"
+)
+
+// FreeBetStatus is the lifecycle state of a free bet
+type FreeBetStatus string
+
+// Free bets are available until played or past their expiry
+const (
+	FreeBetAvailable FreeBetStatus = "available"
+	FreeBetUsed      FreeBetStatus = "used"
+)
+
+// FreeBet is a bet with a fixed stake and bet funded by a promotion
+// Only the winnings are credited, the stake is never returned
+type FreeBet struct {
+	FreeBetID int           `json:"free_bet_id"`
+	PlayerID  int           `json:"player_id"`
+	Code      string        `json:"code"`
+	BetAmount float64       `json:"bet_amount"`
+	BetType   BetType       `json:"bet_type"`
+	BetNumber int           `json:"bet_number,omitempty"`
+	Status    FreeBetStatus `json:"status"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+// PromoCode holds the terms of a promo code created by an operator. A free bet code grants FreeBetCount free bets
+// with its stake and bet, a bonus code grants BonusAmount in the bonus wallet. MaxRedemptions is unlimited when 0
+type PromoCode struct {
+	Code                  string    `json:"code"`
+	Kind                  PromoKind `json:"kind"`
+	BetAmount             float64   `json:"bet_amount,omitempty"`
+	BetType               BetType   `json:"bet_type,omitempty"`
+	BetNumber             int       `json:"bet_number,omitempty"`
+	FreeBetCount          int       `json:"free_bet_count,omitempty"`
+	BonusAmount           float64   `json:"bonus_amount,omitempty"`
+	WageringMultiplier    float64   `json:"wagering_multiplier,omitempty"`
+	RewardValidForSeconds int64     `json:"reward_valid_for_seconds"`
+	MaxRedemptions        int       `json:"max_redemptions,omitempty"`
+	MaxPerPlayer          int       `json:"max_per_player"`
+	Redemptions           int       `json:"redemptions"`
+	ExpiresAt             time.Time `json:"expires_at"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// RedeemPromoRequest redeems a promo code for the player
+type RedeemPromoRequest struct {
+	ClientID int    `json:"client_id"`
+	Code     string `json:"code"`
+}
+
+// RedeemPromoResponse lists the free bets or the bonus granted by a redeemed promo code
+type RedeemPromoResponse struct {
+	ClientID int          `json:"client_id"`
+	Code     string       `json:"code"`
+	Kind     PromoKind    `json:"kind"`
+	FreeBets []FreeBet    `json:"free_bets,omitempty"`
+	Bonus    *PlayerBonus `json:"bonus,omitempty"`
+}
+
+// FreeBetsRequest asks for the free bets a player can still play
+type FreeBetsRequest struct {
+	ClientID int `json:"client_id"`
+}
+
+// FreeBetsResponse lists the available free bets of a player
+type FreeBetsResponse struct {
+	ClientID int       `json:"client_id"`
+	FreeBets []FreeBet `json:"free_bets"`
+}
+
+// GambleStatus is the lifecycle state of a double-or-nothing gamble
+type GambleStatus string
+
+// A gamble stays open while the player keeps doubling and ends collected or lost
+const (
+	GambleOpen      GambleStatus = "open"
+	GambleCollected GambleStatus = "collected"
+	GambleLost      GambleStatus = "lost"
+)
+
+// Gamble is a double-or-nothing ladder started from the payout of a winning round
+// Stake is the payout taken from the balance when the gamble started, Amount is what the player would collect now
+// and Step counts the rolls played so far
+type Gamble struct {
+	GambleID  int          `json:"gamble_id"`
+	SessionID int          `json:"session_id"`
+	RoundID   int          `json:"round_id"`
+	PlayerID  int          `json:"player_id"`
+	Stake     float64      `json:"stake"`
+	Amount    float64      `json:"amount"`
+	Step      int          `json:"step"`
+	Status    GambleStatus `json:"status"`
+	StartedAt time.Time    `json:"started_at"`
+}
+
+// GambleRequest rolls the open gamble of the player at double-or-nothing on an even or odd bet
+// RoundID names the winning round to gamble when no gamble is open yet
+type GambleRequest struct {
+	ClientID int     `json:"client_id"`
+	RoundID  int     `json:"round_id,omitempty"`
+	BetType  BetType `json:"bet_type"`
+}
+
+// GambleResponse reports the roll of a gamble step, or the collection of the gamble
+type GambleResponse struct {
+	ClientID   int     `json:"client_id"`
+	Gamble     Gamble  `json:"gamble"`
+	DiceResult int     `json:"dice_result,omitempty"`
+	Won        bool    `json:"won"`
+	Balance    float64 `json:"balance"`
+	MaxSteps   int     `json:"max_steps"`
+
+	// Seq is the sequence number of the settlement message stored with the gamble step
+	Seq int64 `json:"-"`
+}
+
+// GambleCollectRequest ends the open gamble of the player, crediting its amount
+type GambleCollectRequest struct {
+	ClientID int `json:"client_id"`
+}
+
+// AchievementID identifies an achievement definition
+type AchievementID string
+
+// Achievements players can unlock from their settled rounds
+const (
+	AchievementWinStreak     AchievementID = "win_streak_5"
+	AchievementRoundsPlayed  AchievementID = "rounds_played_100"
+	AchievementFirstExactHit AchievementID = "first_exact_hit"
+)
+
+// Achievement describes an achievement and when the player unlocked it, nil while still locked
+type Achievement struct {
+	ID          AchievementID `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	UnlockedAt  *time.Time    `json:"unlocked_at,omitempty"`
+}
+
+// PlayerStats summarizes the settled rounds of a player that achievements are checked against
+// Voided rounds are left out and WinStreak counts the wins since the last lost round
+type PlayerStats struct {
+	RoundsPlayed int
+	WinStreak    int
+	ExactHits    int
+}
+
+// AchievementsRequest asks for every achievement and the ones the player unlocked
+type AchievementsRequest struct {
+	ClientID int `json:"client_id"`
+}
+
+// AchievementsResponse lists every achievement with the unlock time of those the player earned
+type AchievementsResponse struct {
+	ClientID     int           `json:"client_id"`
+	Achievements []Achievement `json:"achievements"`
+}
+
+// AchievementUnlockedResponse is pushed to the player when a round unlocks an achievement
+type AchievementUnlockedResponse struct {
+	ClientID    int         `json:"client_id"`
+	Achievement Achievement `json:"achievement"`
+}
+
+// HelloRequest declares the protocol version a client speaks, a zero version keeps the negotiated one
+// Locale selects the language of error and notification messages, an empty locale keeps the negotiated one
+// Features opts in to the optional behaviours the server advertises, e.g. settlement_redelivery
+type HelloRequest struct {
+	ClientID int      `json:"client_id,omitempty"`
+	Version  int      `json:"version"`
+	Locale   string   `json:"locale,omitempty"`
+	Features []string `json:"features,omitempty"`
+}
+
+// HelloResponse confirms the protocol version of the connection and lists what the server offers
+type HelloResponse struct {
+	Version           int      `json:"version"`
+	Codec             string   `json:"codec"`
+	SupportedVersions []int    `json:"supported_versions"`
+	Features          []string `json:"features"`
+	Locale            string   `json:"locale"`
+}
+
+// SettlementMessage is a message carrying the outcome of a bet, kept until the player acknowledges it
+type SettlementMessage struct {
+	PlayerID  int
+	Seq       int64
+	Type      MessageType
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// AckRequest acknowledges every settlement message of the player up to and including the sequence number
+type AckRequest struct {
+	ClientID int   `json:"client_id"`
+	Seq      int64 `json:"seq"`
+}
+
+// AckResponse confirms the acknowledgement
+type AckResponse struct {
+	ClientID int   `json:"client_id"`
+	Seq      int64 `json:"seq"`
+}
+
+// TournamentRanking defines how tournament entries are ranked
+type TournamentRanking string
+
+// Tournaments rank players by their final chip stack or by the profit made over the starting stack
+const (
+	RankByStack  TournamentRanking = "stack"
+	RankByProfit TournamentRanking = "profit"
+)
+
+// LedgerEntryKind classifies the money movements recorded in the ledger
+type LedgerEntryKind string
+
+// Ledger entries recorded for tournaments, bonus conversions, voided rounds and gambles
+const (
+	LedgerTournamentBuyIn LedgerEntryKind = "tournament_buy_in"
+	LedgerTournamentPrize LedgerEntryKind = "tournament_prize"
+	LedgerBonusConversion LedgerEntryKind = "bonus_conversion"
+	LedgerRoundVoid       LedgerEntryKind = "round_void"
+	LedgerGambleStake     LedgerEntryKind = "gamble_stake"
+	LedgerGambleCollect   LedgerEntryKind = "gamble_collect"
+)
+
+// Tournament is a time-boxed competition played with a tournament-only chip stack
+// PrizeShares holds the fraction of the prize pool paid to each rank, starting from the first
+// PrizeLeftover is the part of the prize pool no rank was paid, recorded when the tournament closes
+type Tournament struct {
+	TournamentID  int               `json:"tournament_id"`
+	Name          string            `json:"name"`
+	BuyIn         float64           `json:"buy_in"`
+	StartingStack float64           `json:"starting_stack"`
+	Ranking       TournamentRanking `json:"ranking"`
+	PrizePool     float64           `json:"prize_pool"`
+	PrizeShares   []float64         `json:"prize_shares"`
+	PrizeLeftover float64           `json:"prize_leftover,omitempty"`
+	StartsAt      time.Time         `json:"starts_at"`
+	EndsAt        time.Time         `json:"ends_at"`
+	Closed        bool              `json:"closed"`
+}
+
+// TournamentEntry holds the separate chip stack of a registered player
+type TournamentEntry struct {
+	TournamentID int       `json:"tournament_id"`
+	PlayerID     int       `json:"player_id"`
+	Stack        float64   `json:"stack"`
+	RoundsPlayed int       `json:"rounds_played"`
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// TournamentPrize is a prize paid to a player when a tournament closes
+type TournamentPrize struct {
+	PlayerID int     `json:"player_id"`
+	Rank     int     `json:"rank"`
+	Amount   float64 `json:"amount"`
+}
+
+// TournamentsResponse lists the tournaments open for registration or play
+type TournamentsResponse struct {
+	Tournaments []Tournament `json:"tournaments"`
+}
+
+// TournamentRegisterRequest registers the player and pays the buy-in from the wallet
+type TournamentRegisterRequest struct {
+	ClientID     int `json:"client_id"`
+	TournamentID int `json:"tournament_id"`
+}
+
+// TournamentRegisterResponse confirms the registration with the starting stack and remaining wallet balance
+type TournamentRegisterResponse struct {
+	ClientID     int     `json:"client_id"`
+	TournamentID int     `json:"tournament_id"`
+	BuyIn        float64 `json:"buy_in"`
+	Stack        float64 `json:"stack"`
+	Balance      float64 `json:"balance"`
+}
+
+// TournamentPlayRequest places a regular dice bet using the tournament stack
+type TournamentPlayRequest struct {
+	ClientID     int     `json:"client_id"`
+	TournamentID int     `json:"tournament_id"`
+	BetAmount    float64 `json:"bet_amount"`
+	BetType      BetType `json:"bet_type"`
+	BetNumber    int     `json:"bet_number,omitempty"`
+}
+
+// PlayRequest builds the equivalent single player bet, used to share the bet validation rules
+func (r TournamentPlayRequest) PlayRequest() PlayRequest {
+	return PlayRequest{
+		ClientID:  r.ClientID,
+		BetAmount: r.BetAmount,
+		BetType:   r.BetType,
+		BetNumber: r.BetNumber,
+	}
+}
+
+// TournamentPlayResponse contains the round result and the updated tournament stack
+type TournamentPlayResponse struct {
+	TournamentID int     `json:"tournament_id"`
+	DiceResult   int     `json:"dice_result"`
+	Won          bool    `json:"won"`
+	BetAmount    float64 `json:"bet_amount"`
+	Payout       float64 `json:"payout"`
+	Stack        float64 `json:"stack"`
+
+	// Seq is the sequence number of the settlement message stored with the round
+	Seq int64 `json:"-"`
+}
+
+// LeaderboardRequest asks for the ranking of a tournament and subscribes to its live updates
+type LeaderboardRequest struct {
+	ClientID     int `json:"client_id"`
+	TournamentID int `json:"tournament_id"`
+}
+
+// LeaderboardEntry is the position of a player in a tournament
+type LeaderboardEntry struct {
+	Rank         int     `json:"rank"`
+	PlayerID     int     `json:"player_id"`
+	Stack        float64 `json:"stack"`
+	Score        float64 `json:"score"`
+	RoundsPlayed int     `json:"rounds_played"`
+	Prize        float64 `json:"prize,omitempty"`
+}
+
+// LeaderboardResponse carries the live or final ranking of a tournament
+type LeaderboardResponse struct {
+	TournamentID int                `json:"tournament_id"`
+	Ranking      TournamentRanking  `json:"ranking"`
+	Closed       bool               `json:"closed"`
+	Entries      []LeaderboardEntry `json:"entries"`
+	Message      string             `json:"message,omitempty"`
+}
+
+// RealityCheckResponse reminds the player of the time spent and net result since play started
+type RealityCheckResponse struct {
+	ElapsedSeconds int64   `json:"elapsed_seconds"`
+	NetResult      float64 `json:"net_result"`
+	RoundsPlayed   int     `json:"rounds_played"`
+	Message        string  `json:"message"`
+}
+
+// RealityCheckAckRequest confirms the player has seen the latest reality check
+type RealityCheckAckRequest struct {
+	ClientID int `json:"client_id"`
+}
+
+// RealityCheckAckResponse confirms that play can be resumed
+type RealityCheckAckResponse struct {
+	ClientID int `json:"client_id"`
+}
+
 // GameSession represents the state and metadata of an active or completed game
+// A session is a container of rounds, opened by the first play and closed by endplay
 type GameSession struct {
 	SessionID    int        `json:"session_id"`
 	PlayerID     int        `json:"player_id"`
-	BetAmount    float64    `json:"bet_amount"`
-	DiceResult   int        `json:"dice_result"`
-	Won          bool       `json:"won"`
 	Active       bool       `json:"active"`
 	SessionStart time.Time  `json:"session_start"`
 	SessionEnd   *time.Time `json:"session_end,omitempty"`
 }
 
-// GameSessionRequest contains the required data to initialize a new game session
-type GameSessionRequest struct {
-	PlayerID     int       `json:"player_id"`
-	BetAmount    float64   `json:"bet_amount"`
-	DiceResult   int       `json:"dice_result"`
-	Won          bool      `json:"won"`
-	Active       bool      `json:"active"`
-	SessionStart time.Time `json:"session_start"`
+// Round is a single bet and dice roll played within a game session
+// NetResult is the effect of the round on the player's funds, including bonus funds and jackpot wins,
+// while BalanceChange is only its effect on the cash balance, the amount reversed when the round is voided
+type Round struct {
+	RoundID       int       `json:"round_id"`
+	SessionID     int       `json:"session_id"`
+	PlayerID      int       `json:"player_id"`
+	BetAmount     float64   `json:"bet_amount"`
+	BetType       BetType   `json:"bet_type"`
+	BetNumber     int       `json:"bet_number,omitempty"`
+	DiceResult    int       `json:"dice_result"`
+	Won           bool      `json:"won"`
+	Payout        float64   `json:"payout"`
+	NetResult     float64   `json:"net_result"`
+	BalanceChange float64   `json:"balance_change"`
+	FreeBetID     int       `json:"free_bet_id,omitempty"`
+	Voided        bool      `json:"voided,omitempty"`
+	PlayedAt      time.Time `json:"played_at"`
+
+	// What the round took from and paid into the jackpot pool and the bonus wallet, reversed when it is voided
+	JackpotContribution float64 `json:"-"`
+	JackpotAward        float64 `json:"-"`
+	BonusID             int     `json:"-"`
+	BonusChange         float64 `json:"-"`
+	BonusConverted      float64 `json:"-"`
+}
+
+// HistoryResponse lists the latest rounds of a player, newest first
+type HistoryResponse struct {
+	ClientID int     `json:"client_id"`
+	Rounds   []Round `json:"rounds"`
+}
+
+// VoidRoundRequest asks to void a settled round, VoidedBy identifies the operator for the audit trail
+type VoidRoundRequest struct {
+	RoundID  int    `json:"round_id"`
+	Reason   string `json:"reason"`
+	VoidedBy string `json:"voided_by"`
+}
+
+// RoundVoid is the audit record of a voided round, also pushed to the player with the corrected balance
+// AlreadyVoided is set when the round had been voided before, in which case nothing was reversed again
+type RoundVoid struct {
+	RoundID        int       `json:"round_id"`
+	PlayerID       int       `json:"player_id"`
+	Reason         string    `json:"reason"`
+	VoidedBy       string    `json:"voided_by"`
+	ReversedAmount float64   `json:"reversed_amount"`
+	Balance        float64   `json:"balance"`
+	VoidedAt       time.Time `json:"voided_at"`
+	AlreadyVoided  bool      `json:"already_voided,omitempty"`
+
+	// JackpotReversed is returned to the jackpot pool, BonusRestored to the bonus wallet
+	// and FreeBetRestored reports whether the free bet funding the round can be played again
+	JackpotReversed float64 `json:"jackpot_reversed,omitempty"`
+	BonusRestored   float64 `json:"bonus_restored,omitempty"`
+	FreeBetRestored bool    `json:"free_bet_restored,omitempty"`
+
+	// Seq is the sequence number of the round_voided message stored with the void, 0 when already voided
+	Seq int64 `json:"-"`
+}
+
+// PlayerPresence reports whether a player is connected and how long ago their last message or heartbeat arrived
+type PlayerPresence struct {
+	PlayerID    int       `json:"player_id"`
+	Online      bool      `json:"online"`
+	Connections int       `json:"connections"`
+	LastSeen    time.Time `json:"last_seen"`
+	IdleSeconds int64     `json:"idle_seconds"`
+}
+
+// PlayerToken is a token issued by operators authenticating a player on the player facing endpoints until it expires
+type PlayerToken struct {
+	PlayerID  int       `json:"player_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PresenceResponse lists the connected players for operators
+type PresenceResponse struct {
+	Players []PlayerPresence `json:"players"`
+}
+
+// SessionSummary totals the rounds of a closed game session
+// Stakes of free bets are funded by their promotion and are not counted as staked
+type SessionSummary struct {
+	SessionID       int       `json:"session_id"`
+	RoundsPlayed    int       `json:"rounds_played"`
+	TotalStaked     float64   `json:"total_staked"`
+	TotalPayout     float64   `json:"total_payout"`
+	NetResult       float64   `json:"net_result"`
+	SessionStart    time.Time `json:"session_start"`
+	SessionEnd      time.Time `json:"session_end"`
+	DurationSeconds int64     `json:"duration_seconds"`
 }
 
-// PlayTransaction combines the player's bet with the game outcome
+// PlayTransaction combines the player's bet with the game outcome and its settled balance change
+// The jackpot contribution is added to the pool, which is awarded to the player on a jackpot hit
 type PlayTransaction struct {
-	Message    PlayRequest
-	DiceResult int
-	Won        bool
+	Message             PlayRequest
+	DiceResult          int
+	Won                 bool
+	Payout              float64
+	ChangeAmount        float64
+	JackpotContribution float64
+	JackpotHit          bool
+	JackpotSeed         float64
+
+	// BonusID is the active bonus whose balance and wagering progress the play updates, 0 when none
+	// ChangeAmount is then split with BonusChangeAmount following BonusStakeOrder from the balances locked by the settlement
+	BonusID           int
+	BonusStakeOrder   BonusStakeOrder
+	BonusChangeAmount float64
+
+	// FreeBetID is the free bet funding the stake, consumed by the play, 0 when the stake is paid by the player
+	FreeBetID int
+	// NetResult is what the play won or lost before any jackpot award
+	NetResult float64
+}
+
+// Response returns the reply to the play of a settled transaction
+func (t PlayTransaction) Response(s PlaySettlement) PlayResponse {
+	return PlayResponse{
+		SessionID:      s.Session.SessionID,
+		RoundID:        s.Round.RoundID,
+		DiceResult:     t.DiceResult,
+		Won:            t.Won,
+		Balance:        s.Balance,
+		BetAmount:      t.Message.BetAmount,
+		Payout:         t.Payout,
+		NetResult:      t.NetResult + s.JackpotAward,
+		JackpotWon:     s.JackpotAward,
+		BonusBalance:   s.BonusBalance,
+		BonusConverted: s.BonusConverted,
+		FreeBetID:      t.FreeBetID,
+		Seq:            s.Seq,
+	}
+}
+
+// SettlementMessage returns the type and payload of the message reporting a settled play to its player,
+// plays of an autoplay series are reported as autoplay rounds
+func (t PlayTransaction) SettlementMessage(s PlaySettlement) (MessageType, interface{}) {
+	if t.Message.AutoplayRound > 0 {
+		return MessageTypeAutoplayRound, AutoplayRoundResponse{Round: t.Message.AutoplayRound, PlayResponse: t.Response(s)}
+	}
+	return MessageTypePlay, t.Response(s)
+}
+
+// PlaySettlement holds the persisted outcome of a play transaction
+type PlaySettlement struct {
+	Session      GameSession
+	Round        Round
+	Balance      float64
+	JackpotAward float64
+	JackpotPool  float64
+
+	BonusBalance   float64
+	BonusConverted float64
+
+	// Seq is the sequence number of the settlement message stored in the same transaction
+	Seq int64
 }
 
 // BalanceUpdate represents a modification to a player's account balance
@@ -0,0 +1,229 @@
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// locale is a supported language, currencyFormat places the currency symbol around the formatted number
+// separated by a non-breaking space where the locale separates them
+type locale struct {
+	tag            language.Tag
+	currencyFormat string
+}
+
+// locales lists the supported languages, the first one is used when the default locale is not supported
+var locales = []locale{
+	{tag: language.English, currencyFormat: "%[1]s%[2]s"},
+	{tag: language.German, currencyFormat: "%[2]s\u00a0%[1]s"},
+	{tag: language.Spanish, currencyFormat: "%[2]s\u00a0%[1]s"},
+	{tag: language.BrazilianPortuguese, currencyFormat: "%[1]s\u00a0%[2]s"},
+}
+
+// Catalog negotiates the locale of each client and hands out the localizer formatting its messages
+type Catalog struct {
+	matcher       language.Matcher
+	localizers    []*Localizer
+	defaultLocale *Localizer
+}
+
+// NewCatalog creates the catalog of the supported locales, unknown default locales and currencies are logged
+// and replaced by the first supported locale and euros
+func NewCatalog(conf config.LocaleConfig) *Catalog {
+	unit, err := currency.ParseISO(conf.Currency)
+	if err != nil {
+		log.Printf("could not parse currency %q, using EUR: %v", conf.Currency, err)
+		unit = currency.EUR
+	}
+
+	tags := make([]language.Tag, 0, len(locales))
+	c := &Catalog{}
+	for _, l := range locales {
+		tags = append(tags, l.tag)
+		c.localizers = append(c.localizers, &Localizer{locale: l, currency: unit, printer: message.NewPrinter(l.tag)})
+	}
+	c.matcher = language.NewMatcher(tags)
+	c.defaultLocale = c.localizers[0]
+	if localizer, ok := c.match(conf.Default); ok {
+		c.defaultLocale = localizer
+	} else if conf.Default != "" {
+		log.Printf("unsupported default locale %q, using %s", conf.Default, c.defaultLocale.Locale())
+	}
+	return c
+}
+
+// Negotiate returns the localizer of the first preference matching a supported locale, falling back to the default one
+// Each preference is either a locale such as pt-BR or an Accept-Language header value
+func (c *Catalog) Negotiate(preferences ...string) *Localizer {
+	for _, preference := range preferences {
+		if localizer, ok := c.match(preference); ok {
+			return localizer
+		}
+	}
+	return c.defaultLocale
+}
+
+// match finds the supported locale closest to a preference
+func (c *Catalog) match(preference string) (*Localizer, bool) {
+	tags, _, err := language.ParseAcceptLanguage(preference)
+	if err != nil || len(tags) == 0 {
+		return nil, false
+	}
+	_, index, confidence := c.matcher.Match(tags...)
+	if confidence == language.No {
+		return nil, false
+	}
+	return c.localizers[index], true
+}
+
+// Localizer translates the messages sent to the clients of one locale, it is safe for concurrent use
+type Localizer struct {
+	locale   locale
+	currency currency.Unit
+	printer  *message.Printer
+}
+
+// Locale returns the BCP 47 tag of the locale, e.g. pt-BR
+func (l *Localizer) Locale() string {
+	return l.locale.tag.String()
+}
+
+// Amount formats money with the grouping, decimal separator and currency symbol placement of the locale
+func (l *Localizer) Amount(amount float64) string {
+	symbol := l.printer.Sprint(currency.Symbol(l.currency))
+	formatted := fmt.Sprintf(l.locale.currencyFormat, symbol, l.printer.Sprint(number.Decimal(math.Abs(amount), number.Scale(2))))
+	if amount < 0 {
+		return "-" + formatted
+	}
+	return formatted
+}
+
+// Sprintf fills the template of key in the locale, formatting numbers and domain.Amount arguments for it
+// Keys missing from the locale fall back to English, and to the key itself when English lacks them too
+func (l *Localizer) Sprintf(key string, args ...interface{}) string {
+	template, ok := l.lookup(key)
+	if !ok {
+		template = key
+	}
+	return l.format(template, args...)
+}
+
+// Error returns a copy of the error with its message, and details when they have a template, in the locale
+func (l *Localizer) Error(err *appErrors.GameError) *appErrors.GameError {
+	localized := *err
+	if template, ok := l.lookup(errorKey(err.Code)); ok {
+		localized.Message = template
+	}
+	if err.DetailKey != "" {
+		localized.Details = l.Sprintf(err.DetailKey, err.DetailArgs...)
+	}
+	return &localized
+}
+
+// RealityCheck returns the reminder with its message in the locale
+func (l *Localizer) RealityCheck(check domain.RealityCheckResponse) domain.RealityCheckResponse {
+	check.Message = l.Sprintf(NotificationRealityCheck, check.ElapsedSeconds/60, check.RoundsPlayed, domain.Amount(check.NetResult))
+	return check
+}
+
+// AutoplayEnd returns the summary of a finished series with its message in the locale
+func (l *Localizer) AutoplayEnd(end domain.AutoplayEndResponse) domain.AutoplayEndResponse {
+	reason := l.Sprintf(autoplayEndKey(end.Reason))
+	end.Message = l.Sprintf(NotificationAutoplayEnd, reason, end.RoundsPlayed, domain.Amount(end.NetResult))
+	return end
+}
+
+// TableSettlement returns the settlement broadcast to a table with its message in the locale
+func (l *Localizer) TableSettlement(settlement domain.TableSettlementResponse) domain.TableSettlementResponse {
+	settlement.Message = l.Sprintf(NotificationTableRolled, settlement.TableID, settlement.DiceResult)
+	return settlement
+}
+
+// TableBetSettled returns the results of one player at a table with the net result of their bets in the locale
+func (l *Localizer) TableBetSettled(settlement domain.TableSettlementResponse) domain.TableSettlementResponse {
+	var netResult float64
+	for _, result := range settlement.Results {
+		netResult += result.Payout - result.BetAmount
+	}
+	if netResult > 0 {
+		settlement.Message = l.Sprintf(NotificationTableBetWon, settlement.TableID, settlement.DiceResult, domain.Amount(netResult))
+	} else {
+		settlement.Message = l.Sprintf(NotificationTableBetLost, settlement.TableID, settlement.DiceResult, domain.Amount(-netResult))
+	}
+	return settlement
+}
+
+// Leaderboard returns the ranking of a tournament, once closed with a message in the locale telling the player their result
+func (l *Localizer) Leaderboard(leaderboard domain.LeaderboardResponse, playerID int) domain.LeaderboardResponse {
+	if !leaderboard.Closed {
+		return leaderboard
+	}
+	leaderboard.Message = l.Sprintf(NotificationTournamentEnded, leaderboard.TournamentID)
+	for _, entry := range leaderboard.Entries {
+		if entry.PlayerID != playerID {
+			continue
+		}
+		if entry.Prize > 0 {
+			leaderboard.Message = l.Sprintf(NotificationTournamentPrize, leaderboard.TournamentID, entry.Rank, domain.Amount(entry.Prize))
+		} else {
+			leaderboard.Message = l.Sprintf(NotificationTournamentRank, leaderboard.TournamentID, entry.Rank)
+		}
+	}
+	return leaderboard
+}
+
+// Jackpot returns the jackpot pool with its message in the locale, announcing the win when there is one
+func (l *Localizer) Jackpot(jackpot domain.JackpotResponse) domain.JackpotResponse {
+	if jackpot.LastWinAmount > 0 {
+		jackpot.Message = l.Sprintf(NotificationJackpotWon, domain.Amount(jackpot.LastWinAmount), domain.Amount(jackpot.Amount))
+	} else {
+		jackpot.Message = l.Sprintf(NotificationJackpot, domain.Amount(jackpot.Amount))
+	}
+	return jackpot
+}
+
+// Achievement returns the achievement with its name and description in the locale, untranslated ones are kept as is
+func (l *Localizer) Achievement(achievement domain.Achievement) domain.Achievement {
+	if name, ok := l.lookup(achievementKey(achievement.ID, "name")); ok {
+		achievement.Name = name
+	}
+	if description, ok := l.lookup(achievementKey(achievement.ID, "description")); ok {
+		achievement.Description = description
+	}
+	return achievement
+}
+
+// lookup returns the template of key in the locale or in English
+func (l *Localizer) lookup(key string) (string, bool) {
+	if template, ok := translations[l.locale.tag][key]; ok {
+		return template, true
+	}
+	template, ok := translations[language.English][key]
+	return template, ok
+}
+
+// format fills a template, domain.Amount arguments are formatted as money before the printer localizes the numbers
+func (l *Localizer) format(template string, args ...interface{}) string {
+	if len(args) == 0 && !strings.Contains(template, "%") {
+		return template
+	}
+	formatted := make([]interface{}, len(args))
+	for i, arg := range args {
+		if amount, ok := arg.(domain.Amount); ok {
+			formatted[i] = l.Amount(float64(amount))
+			continue
+		}
+		formatted[i] = arg
+	}
+	return l.printer.Sprintf(template, formatted...)
+}
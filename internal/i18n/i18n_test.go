@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	catalog := NewCatalog(config.LocaleConfig{Default: "es", Currency: "EUR"})
+
+	tests := []struct {
+		name           string
+		preferences    []string
+		expectedLocale string
+	}{
+		{name: "no_preference", expectedLocale: "es"},
+		{name: "exact_locale", preferences: []string{"de"}, expectedLocale: "de"},
+		{name: "regional_variant", preferences: []string{"pt-PT"}, expectedLocale: "pt-BR"},
+		{name: "accept_language_weights", preferences: []string{"fr-FR,fr;q=0.9,en;q=0.5"}, expectedLocale: "en"},
+		{name: "unsupported_falls_through", preferences: []string{"ja", "de-AT"}, expectedLocale: "de"},
+		{name: "malformed", preferences: []string{";;;"}, expectedLocale: "es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedLocale, catalog.Negotiate(tt.preferences...).Locale())
+		})
+	}
+}
+
+func TestLocalizer_Amount(t *testing.T) {
+	tests := []struct {
+		locale   string
+		currency string
+		amount   float64
+		expected string
+	}{
+		{locale: "en", currency: "EUR", amount: 1234.5, expected: "€1,234.50"},
+		{locale: "de", currency: "EUR", amount: 1234.5, expected: "1.234,50\u00a0€"},
+		{locale: "es", currency: "EUR", amount: -12, expected: "-12,00\u00a0€"},
+		{locale: "pt-BR", currency: "BRL", amount: 1234.5, expected: "R$\u00a01.234,50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			localizer := NewCatalog(config.LocaleConfig{Currency: tt.currency}).Negotiate(tt.locale)
+			assert.Equal(t, tt.expected, localizer.Amount(tt.amount))
+		})
+	}
+}
+
+func TestLocalizer_Error(t *testing.T) {
+	catalog := NewCatalog(config.LocaleConfig{Currency: "EUR"})
+	err := appErrors.NewInvalidBetAmountError("minimum bet amount is 10.00").
+		WithField("bet_amount", appErrors.ConstraintMin, "10.00").
+		WithDetail(appErrors.DetailBetBelowMin, domain.Amount(10))
+
+	german := catalog.Negotiate("de").Error(err)
+	assert.Equal(t, "Ungültiger Einsatz", german.Message)
+	assert.Equal(t, "Der Mindesteinsatz beträgt 10,00\u00a0€", german.Details)
+	assert.Equal(t, err.Fields, german.Fields)
+	assert.Equal(t, "minimum bet amount is 10.00", err.Details)
+
+	buyIn := catalog.Negotiate("de").Error(appErrors.NewTournamentError("buy-in exceeds balance").WithDetail(appErrors.DetailTournamentBuyIn, domain.Amount(50), domain.Amount(20)))
+	assert.Equal(t, "das Startgeld von 50,00\u00a0€ übersteigt das verfügbare Guthaben von 20,00\u00a0€", buyIn.Details)
+
+	english := catalog.Negotiate("en").Error(appErrors.NewUserNotFoundError("Client ID 7 not found"))
+	assert.Equal(t, appErrors.Definition(appErrors.UserNotFoundErrorCode).Message, english.Message)
+	assert.Equal(t, "Client ID 7 not found", english.Details)
+}
+
+func TestTranslations_CoverEveryErrorCode(t *testing.T) {
+	for tag, templates := range translations {
+		for _, definition := range appErrors.Definitions() {
+			assert.NotEmpty(t, templates[errorKey(definition.Code)], "%s lacks error %s", tag, definition.Type)
+		}
+	}
+}
+
+func TestTranslations_CoverEveryDetail(t *testing.T) {
+	for tag, templates := range translations {
+		for _, key := range appErrors.DetailKeys() {
+			assert.NotEmpty(t, templates[key], "%s lacks detail %s", tag, key)
+		}
+	}
+}
+
+func TestLocalizer_Notifications(t *testing.T) {
+	localizer := NewCatalog(config.LocaleConfig{Currency: "EUR"}).Negotiate("de")
+
+	check := localizer.RealityCheck(domain.RealityCheckResponse{ElapsedSeconds: 1800, NetResult: -1250.5, RoundsPlayed: 1200})
+	assert.Equal(t, "Spielzeit: 30 Min., Runden: 1.200, Nettoergebnis: -1.250,50\u00a0€", check.Message)
+
+	end := localizer.AutoplayEnd(domain.AutoplayEndResponse{RoundsPlayed: 3, NetResult: 9.6, Reason: domain.AutoplayLossLimit})
+	assert.Equal(t, "Autoplay am Verlustlimit gestoppt. Runden: 3, Nettoergebnis: 9,60\u00a0€", end.Message)
+
+	achievement := localizer.Achievement(domain.Achievement{ID: domain.AchievementWinStreak, Name: "On Fire"})
+	assert.Equal(t, "Glückssträhne", achievement.Name)
+}
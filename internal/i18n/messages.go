@@ -0,0 +1,518 @@
+package i18n
+
+import (
+	"fmt"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"golang.org/x/text/language"
+)
+
+// Keys of the templates translating notifications
+const (
+	NotificationRealityCheck    = "notification.reality_check"
+	NotificationAutoplayEnd     = "notification.autoplay_end"
+	NotificationTableRolled     = "notification.table_rolled"
+	NotificationTableBetWon     = "notification.table_bet_won"
+	NotificationTableBetLost    = "notification.table_bet_lost"
+	NotificationTournamentEnded = "notification.tournament_ended"
+	NotificationTournamentRank  = "notification.tournament_rank"
+	NotificationTournamentPrize = "notification.tournament_prize"
+	NotificationJackpot         = "notification.jackpot"
+	NotificationJackpotWon      = "notification.jackpot_won"
+)
+
+// errorKey names the template translating the message of an error code
+func errorKey(code int) string {
+	return fmt.Sprintf("error.%d", code)
+}
+
+// autoplayEndKey names the template describing why an autoplay series ended
+func autoplayEndKey(reason domain.AutoplayStopReason) string {
+	return fmt.Sprintf("autoplay_end.%s", reason)
+}
+
+// achievementKey names the template translating a text of an achievement
+func achievementKey(id domain.AchievementID, text string) string {
+	return fmt.Sprintf("achievement.%s.%s", id, text)
+}
+
+// translations holds the templates of every supported locale, keyed by template name
+// English error messages come from the error registry so both always agree
+var translations = map[language.Tag]map[string]string{
+	language.English: {
+		appErrors.DetailInsufficientFunds:       "bet amount %s exceeds available balance %s",
+		appErrors.DetailBetNegative:             "bet amount cannot be negative: %s",
+		appErrors.DetailBetZero:                 "bet amount cannot be zero",
+		appErrors.DetailBetBelowMin:             "minimum bet amount is %s",
+		appErrors.DetailBetAboveMax:             "maximum bet amount is %s",
+		appErrors.DetailAutoplayRounds:          "autoplay rounds must be between 1 and %d",
+		appErrors.DetailBetType:                 "unsupported bet type: %s",
+		appErrors.DetailBetNumber:               "bet number must be between 1 and %d",
+		appErrors.DetailStopConditions:          "autoplay stop conditions cannot be negative",
+		appErrors.DetailAutoplayRunning:         "client ID %d has an autoplay in progress",
+		appErrors.DetailAutoplayIdle:            "client ID %d does not have an autoplay in progress",
+		appErrors.DetailUserNotFound:            "no player found with ID %d",
+		appErrors.DetailNotConnected:            "client ID %d has not connected since the server started",
+		appErrors.DetailForbiddenPlayer:         "connection is authenticated as client ID %d, not %d",
+		appErrors.DetailNoActiveSession:         "client ID %d does not have an active session",
+		appErrors.DetailRealityCheck:            "acknowledge the reality check before playing",
+		appErrors.DetailInvalidSeq:              "invalid sequence number: %d",
+		appErrors.DetailUnknownRound:            "unknown round: %d",
+		appErrors.DetailBonusAmount:             "bonus amount must be positive: %s",
+		appErrors.DetailBonusWagering:           "wagering multiplier cannot be negative: %g",
+		appErrors.DetailBonusValidity:           "bonus must be valid for a positive duration",
+		appErrors.DetailBonusInactive:           "bonus %d is no longer active",
+		appErrors.DetailBonusActive:             "player %d already has an active bonus",
+		appErrors.DetailPromoCodeRequired:       "promo code cannot be empty",
+		appErrors.DetailPromoUnknown:            "unknown promo code: %s",
+		appErrors.DetailPromoExpired:            "promo code %s has expired",
+		appErrors.DetailPromoExhausted:          "promo code %s has been fully redeemed",
+		appErrors.DetailPromoRedeemed:           "promo code %s was already redeemed",
+		appErrors.DetailPromoCodeLength:         "promo code cannot exceed %d characters",
+		appErrors.DetailPromoKind:               "promo code kind must be free_bet or bonus: %s",
+		appErrors.DetailPromoBetAmount:          "free bet amount must be positive: %s",
+		appErrors.DetailPromoFreeBetCount:       "free bet count must be positive: %d",
+		appErrors.DetailPromoValidity:           "promo rewards must be valid for a positive duration",
+		appErrors.DetailPromoLimits:             "redemption limits cannot be negative",
+		appErrors.DetailPromoExpiry:             "promo code must expire in the future",
+		appErrors.DetailPromoExists:             "promo code %s already exists",
+		appErrors.DetailFreeBetUnknown:          "unknown free bet: %d",
+		appErrors.DetailFreeBetUnavailable:      "free bet %d is no longer available",
+		appErrors.DetailVoidRoundID:             "invalid round id: %d",
+		appErrors.DetailVoidReason:              "void reason cannot be empty",
+		appErrors.DetailVoidOperator:            "voiding operator cannot be empty",
+		appErrors.DetailVoidBalance:             "balance of player %d cannot cover the reversal of round %d",
+		appErrors.DetailVoidBonusClosed:         "bonus %d funding round %d was closed since and cannot be restored",
+		appErrors.DetailVoidBonusBalance:        "bonus %d cannot cover the reversal of round %d",
+		appErrors.DetailVoidBonusActive:         "player %d holds another active bonus, bonus %d cannot be reopened",
+		appErrors.DetailVoidGambled:             "round %d was gambled and cannot be voided",
+		appErrors.DetailGambleDisabled:          "gambling is disabled",
+		appErrors.DetailGambleBetType:           "gamble bet type must be even or odd: %s",
+		appErrors.DetailGambleNotOpen:           "client ID %d has no open gamble",
+		appErrors.DetailGambleRoundRequired:     "client ID %d has no open gamble, a winning round must be named",
+		appErrors.DetailGamblePending:           "the gamble on round %d must be collected or lost first",
+		appErrors.DetailGambleOpen:              "client ID %d must collect or finish the gamble on round %d first",
+		appErrors.DetailGambleOtherSession:      "round %d does not belong to the active session",
+		appErrors.DetailGambleNoWinnings:        "round %d has no winnings to gamble",
+		appErrors.DetailGambleNotCash:           "round %d was not settled entirely in cash and cannot be gambled",
+		appErrors.DetailGambleNotLast:           "only the last round of the session can be gambled, round %d was followed by others",
+		appErrors.DetailGambleRepeated:          "round %d was already gambled",
+		appErrors.DetailGambleAlreadyOpen:       "player %d already has an open gamble",
+		appErrors.DetailGambleBalance:           "balance of player %d no longer covers the winnings of round %d",
+		appErrors.DetailGambleSettled:           "gamble %d was already settled",
+		appErrors.DetailTableUnknown:            "unknown table: %d",
+		appErrors.DetailTableRoundRunning:       "round %d of table %d is still %s",
+		appErrors.DetailTableBettingClosed:      "betting is closed on table %d",
+		appErrors.DetailTableNotRolled:          "table %d has no rolled round to settle",
+		appErrors.DetailTableNoRound:            "table %d has no round in state %s",
+		appErrors.DetailTableRoundOver:          "round %d is no longer being played",
+		appErrors.DetailTableNotSeated:          "client ID %d is not seated at table %d",
+		appErrors.DetailTableJoinFirst:          "client ID %d must join table %d before betting",
+		appErrors.DetailTournamentUnknown:       "unknown tournament: %d",
+		appErrors.DetailTournamentEnded:         "tournament %d has ended",
+		appErrors.DetailTournamentNotRunning:    "tournament %d is not running",
+		appErrors.DetailTournamentBuyIn:         "buy-in %s exceeds available balance %s",
+		appErrors.DetailTournamentRegistered:    "player %d is already registered to tournament %d",
+		appErrors.DetailTournamentNotRegistered: "player %d is not registered to tournament %d",
+		appErrors.DetailTournamentName:          "tournament name must be between 1 and %d characters",
+		appErrors.DetailTournamentBuyInAmount:   "tournament buy-in cannot be negative: %s",
+		appErrors.DetailTournamentStack:         "starting stack must be positive: %s",
+		appErrors.DetailTournamentRanking:       "tournament ranking must be stack or profit: %s",
+		appErrors.DetailTournamentShares:        "prize shares must be positive and add up to at most 1",
+		appErrors.DetailTournamentSchedule:      "tournament must end after it starts and in the future",
+		NotificationRealityCheck:                "Time played: %d min, rounds: %d, net result: %s",
+		NotificationAutoplayEnd:                 "%s. Rounds: %d, net result: %s",
+		NotificationTableRolled:                 "Table %d rolled a %d",
+		NotificationTableBetWon:                 "Table %d rolled a %d, you won %s",
+		NotificationTableBetLost:                "Table %d rolled a %d, you lost %s",
+		NotificationTournamentEnded:             "Tournament %d has ended",
+		NotificationTournamentRank:              "Tournament %d has ended, you finished in place %d",
+		NotificationTournamentPrize:             "Tournament %d has ended, you finished in place %d and won %s",
+		NotificationJackpot:                     "Jackpot: %s",
+		NotificationJackpotWon:                  "Jackpot of %s won! New jackpot: %s",
+
+		autoplayEndKey(domain.AutoplayCompleted):    "Autoplay completed",
+		autoplayEndKey(domain.AutoplayStopped):      "Autoplay stopped",
+		autoplayEndKey(domain.AutoplayLossLimit):    "Autoplay stopped at the loss limit",
+		autoplayEndKey(domain.AutoplayWinLimit):     "Autoplay stopped at the win limit",
+		autoplayEndKey(domain.AutoplayBalanceLimit): "Autoplay stopped at the balance limit",
+		autoplayEndKey(domain.AutoplayRealityCheck): "Autoplay stopped for a reality check",
+		autoplayEndKey(domain.AutoplayError):        "Autoplay stopped after an error",
+	},
+	language.German: {
+		errorKey(appErrors.InternalErrorCode):            "Interner Serverfehler",
+		errorKey(appErrors.InvalidInputErrorCode):        "Ungültige Eingabe",
+		errorKey(appErrors.InsufficientFundsErrorCode):   "Der Einsatz übersteigt das verfügbare Guthaben",
+		errorKey(appErrors.InvalidBetAmountErrorCode):    "Ungültiger Einsatz",
+		errorKey(appErrors.UserNotFoundErrorCode):        "Benutzer nicht gefunden",
+		errorKey(appErrors.ActiveSessionErrorCode):       "Fehler in der aktiven Sitzung",
+		errorKey(appErrors.DiceRollErrorCode):            "Fehler beim Würfeln",
+		errorKey(appErrors.RealityCheckPendingErrorCode): "Der Realitätscheck muss bestätigt werden",
+		errorKey(appErrors.TableRoundErrorCode):          "Fehler in der Tischrunde",
+		errorKey(appErrors.TournamentErrorCode):          "Turnierfehler",
+		errorKey(appErrors.BonusErrorCode):               "Bonusfehler",
+		errorKey(appErrors.PromoErrorCode):               "Aktionsfehler",
+		errorKey(appErrors.VoidErrorCode):                "Fehler beim Stornieren der Runde",
+		errorKey(appErrors.GambleErrorCode):              "Fehler beim Risikospiel",
+		errorKey(appErrors.ForbiddenErrorCode):           "Zugriff verweigert",
+
+		appErrors.DetailInsufficientFunds:       "Der Einsatz von %s übersteigt das verfügbare Guthaben von %s",
+		appErrors.DetailBetNegative:             "Der Einsatz darf nicht negativ sein: %s",
+		appErrors.DetailBetZero:                 "Der Einsatz darf nicht null sein",
+		appErrors.DetailBetBelowMin:             "Der Mindesteinsatz beträgt %s",
+		appErrors.DetailBetAboveMax:             "Der Höchsteinsatz beträgt %s",
+		appErrors.DetailAutoplayRounds:          "Die Anzahl der Autoplay-Runden muss zwischen 1 und %d liegen",
+		appErrors.DetailBetType:                 "nicht unterstützte Wettart: %s",
+		appErrors.DetailBetNumber:               "die gesetzte Zahl muss zwischen 1 und %d liegen",
+		appErrors.DetailStopConditions:          "die Stoppbedingungen des Autoplays dürfen nicht negativ sein",
+		appErrors.DetailAutoplayRunning:         "für Kunden-ID %d läuft bereits ein Autoplay",
+		appErrors.DetailAutoplayIdle:            "für Kunden-ID %d läuft kein Autoplay",
+		appErrors.DetailUserNotFound:            "kein Spieler mit der ID %d gefunden",
+		appErrors.DetailNotConnected:            "Kunden-ID %d hat sich seit dem Serverstart nicht verbunden",
+		appErrors.DetailForbiddenPlayer:         "die Verbindung ist als Kunden-ID %d angemeldet, nicht als %d",
+		appErrors.DetailNoActiveSession:         "Kunden-ID %d hat keine aktive Sitzung",
+		appErrors.DetailRealityCheck:            "bestätige den Realitätscheck, bevor du weiterspielst",
+		appErrors.DetailInvalidSeq:              "ungültige Sequenznummer: %d",
+		appErrors.DetailUnknownRound:            "unbekannte Runde: %d",
+		appErrors.DetailBonusAmount:             "der Bonusbetrag muss positiv sein: %s",
+		appErrors.DetailBonusWagering:           "der Umsatzmultiplikator darf nicht negativ sein: %g",
+		appErrors.DetailBonusValidity:           "der Bonus muss für eine positive Dauer gültig sein",
+		appErrors.DetailBonusInactive:           "Bonus %d ist nicht mehr aktiv",
+		appErrors.DetailBonusActive:             "Spieler %d hat bereits einen aktiven Bonus",
+		appErrors.DetailPromoCodeRequired:       "der Aktionscode darf nicht leer sein",
+		appErrors.DetailPromoUnknown:            "unbekannter Aktionscode: %s",
+		appErrors.DetailPromoExpired:            "Aktionscode %s ist abgelaufen",
+		appErrors.DetailPromoExhausted:          "Aktionscode %s wurde bereits vollständig eingelöst",
+		appErrors.DetailPromoRedeemed:           "Aktionscode %s wurde bereits eingelöst",
+		appErrors.DetailPromoCodeLength:         "Aktionscode darf höchstens %d Zeichen lang sein",
+		appErrors.DetailPromoKind:               "Art des Aktionscodes muss free_bet oder bonus sein: %s",
+		appErrors.DetailPromoBetAmount:          "Einsatz der Gratiswette muss positiv sein: %s",
+		appErrors.DetailPromoFreeBetCount:       "Anzahl der Gratiswetten muss positiv sein: %d",
+		appErrors.DetailPromoValidity:           "Aktionsprämien müssen für eine positive Dauer gültig sein",
+		appErrors.DetailPromoLimits:             "Einlösegrenzen dürfen nicht negativ sein",
+		appErrors.DetailPromoExpiry:             "Aktionscode muss in der Zukunft ablaufen",
+		appErrors.DetailPromoExists:             "Aktionscode %s existiert bereits",
+		appErrors.DetailFreeBetUnknown:          "unbekannte Gratiswette: %d",
+		appErrors.DetailFreeBetUnavailable:      "Gratiswette %d ist nicht mehr verfügbar",
+		appErrors.DetailVoidRoundID:             "ungültige Runden-ID: %d",
+		appErrors.DetailVoidReason:              "der Stornogrund darf nicht leer sein",
+		appErrors.DetailVoidOperator:            "der stornierende Operator darf nicht leer sein",
+		appErrors.DetailVoidBalance:             "das Guthaben von Spieler %d deckt die Rückbuchung von Runde %d nicht",
+		appErrors.DetailVoidBonusClosed:         "Bonus %d, mit dem Runde %d gespielt wurde, wurde inzwischen geschlossen und kann nicht wiederhergestellt werden",
+		appErrors.DetailVoidBonusBalance:        "Bonus %d deckt die Rückbuchung von Runde %d nicht",
+		appErrors.DetailVoidBonusActive:         "Spieler %d hat einen anderen aktiven Bonus, Bonus %d kann nicht wieder geöffnet werden",
+		appErrors.DetailVoidGambled:             "Runde %d wurde verdoppelt und kann nicht storniert werden",
+		appErrors.DetailGambleDisabled:          "das Risikospiel ist deaktiviert",
+		appErrors.DetailGambleBetType:           "die Wettart des Risikospiels muss gerade oder ungerade sein: %s",
+		appErrors.DetailGambleNotOpen:           "Kunden-ID %d hat kein offenes Risikospiel",
+		appErrors.DetailGambleRoundRequired:     "Kunden-ID %d hat kein offenes Risikospiel, eine Gewinnrunde muss angegeben werden",
+		appErrors.DetailGamblePending:           "das Risikospiel auf Runde %d muss zuerst eingesammelt oder verloren werden",
+		appErrors.DetailGambleOpen:              "Kunden-ID %d muss zuerst das Risikospiel auf Runde %d einsammeln oder beenden",
+		appErrors.DetailGambleOtherSession:      "Runde %d gehört nicht zur aktiven Sitzung",
+		appErrors.DetailGambleNoWinnings:        "Runde %d hat keinen Gewinn für ein Risikospiel",
+		appErrors.DetailGambleNotCash:           "Runde %d wurde nicht vollständig in Echtgeld abgerechnet und kann nicht riskiert werden",
+		appErrors.DetailGambleNotLast:           "nur die letzte Runde der Sitzung kann riskiert werden, auf Runde %d folgten weitere",
+		appErrors.DetailGambleRepeated:          "Runde %d wurde bereits riskiert",
+		appErrors.DetailGambleAlreadyOpen:       "Spieler %d hat bereits ein offenes Risikospiel",
+		appErrors.DetailGambleBalance:           "das Guthaben von Spieler %d deckt den Gewinn von Runde %d nicht mehr",
+		appErrors.DetailGambleSettled:           "Risikospiel %d wurde bereits abgerechnet",
+		appErrors.DetailTableUnknown:            "unbekannter Tisch: %d",
+		appErrors.DetailTableRoundRunning:       "Runde %d an Tisch %d ist noch im Zustand %s",
+		appErrors.DetailTableBettingClosed:      "an Tisch %d sind keine Einsätze mehr möglich",
+		appErrors.DetailTableNotRolled:          "Tisch %d hat keine gewürfelte Runde zum Abrechnen",
+		appErrors.DetailTableNoRound:            "Tisch %d hat keine Runde im Zustand %s",
+		appErrors.DetailTableRoundOver:          "Runde %d wird nicht mehr gespielt",
+		appErrors.DetailTableNotSeated:          "Kunden-ID %d sitzt nicht an Tisch %d",
+		appErrors.DetailTableJoinFirst:          "Kunden-ID %d muss sich vor dem Setzen an Tisch %d setzen",
+		appErrors.DetailTournamentUnknown:       "unbekanntes Turnier: %d",
+		appErrors.DetailTournamentEnded:         "Turnier %d ist beendet",
+		appErrors.DetailTournamentNotRunning:    "Turnier %d läuft nicht",
+		appErrors.DetailTournamentBuyIn:         "das Startgeld von %s übersteigt das verfügbare Guthaben von %s",
+		appErrors.DetailTournamentRegistered:    "Spieler %d ist bereits für Turnier %d angemeldet",
+		appErrors.DetailTournamentNotRegistered: "Spieler %d ist nicht für Turnier %d angemeldet",
+		appErrors.DetailTournamentName:          "Turniername muss zwischen 1 und %d Zeichen lang sein",
+		appErrors.DetailTournamentBuyInAmount:   "Turnier-Buy-in darf nicht negativ sein: %s",
+		appErrors.DetailTournamentStack:         "Startstapel muss positiv sein: %s",
+		appErrors.DetailTournamentRanking:       "Turnierwertung muss stack oder profit sein: %s",
+		appErrors.DetailTournamentShares:        "Preisanteile müssen positiv sein und dürfen zusammen höchstens 1 ergeben",
+		appErrors.DetailTournamentSchedule:      "Turnier muss nach seinem Beginn und in der Zukunft enden",
+		NotificationRealityCheck:                "Spielzeit: %d Min., Runden: %d, Nettoergebnis: %s",
+		NotificationAutoplayEnd:                 "%s. Runden: %d, Nettoergebnis: %s",
+		NotificationTableRolled:                 "Tisch %d hat eine %d gewürfelt",
+		NotificationTableBetWon:                 "Tisch %d hat eine %d gewürfelt, du hast %s gewonnen",
+		NotificationTableBetLost:                "Tisch %d hat eine %d gewürfelt, du hast %s verloren",
+		NotificationTournamentEnded:             "Turnier %d ist beendet",
+		NotificationTournamentRank:              "Turnier %d ist beendet, du bist auf Platz %d",
+		NotificationTournamentPrize:             "Turnier %d ist beendet, du bist auf Platz %d und hast %s gewonnen",
+		NotificationJackpot:                     "Jackpot: %s",
+		NotificationJackpotWon:                  "Jackpot von %s geknackt! Neuer Jackpot: %s",
+
+		autoplayEndKey(domain.AutoplayCompleted):    "Autoplay abgeschlossen",
+		autoplayEndKey(domain.AutoplayStopped):      "Autoplay gestoppt",
+		autoplayEndKey(domain.AutoplayLossLimit):    "Autoplay am Verlustlimit gestoppt",
+		autoplayEndKey(domain.AutoplayWinLimit):     "Autoplay am Gewinnlimit gestoppt",
+		autoplayEndKey(domain.AutoplayBalanceLimit): "Autoplay am Guthabenlimit gestoppt",
+		autoplayEndKey(domain.AutoplayRealityCheck): "Autoplay für einen Realitätscheck gestoppt",
+		autoplayEndKey(domain.AutoplayError):        "Autoplay nach einem Fehler gestoppt",
+
+		achievementKey(domain.AchievementWinStreak, "name"):            "Glückssträhne",
+		achievementKey(domain.AchievementWinStreak, "description"):     "Gewinne fünf Runden in Folge",
+		achievementKey(domain.AchievementRoundsPlayed, "name"):         "Stammgast",
+		achievementKey(domain.AchievementRoundsPlayed, "description"):  "Spiele 100 Runden",
+		achievementKey(domain.AchievementFirstExactHit, "name"):        "Volltreffer",
+		achievementKey(domain.AchievementFirstExactHit, "description"): "Gewinne eine Wette auf eine genaue Zahl",
+	},
+	language.Spanish: {
+		errorKey(appErrors.InternalErrorCode):            "Error interno del servidor",
+		errorKey(appErrors.InvalidInputErrorCode):        "Datos de entrada no válidos",
+		errorKey(appErrors.InsufficientFundsErrorCode):   "La apuesta supera el saldo disponible",
+		errorKey(appErrors.InvalidBetAmountErrorCode):    "Importe de apuesta no válido",
+		errorKey(appErrors.UserNotFoundErrorCode):        "Usuario no encontrado",
+		errorKey(appErrors.ActiveSessionErrorCode):       "Error de sesión activa",
+		errorKey(appErrors.DiceRollErrorCode):            "Error al lanzar el dado",
+		errorKey(appErrors.RealityCheckPendingErrorCode): "Debe confirmar el control de realidad",
+		errorKey(appErrors.TableRoundErrorCode):          "Error en la ronda de mesa",
+		errorKey(appErrors.TournamentErrorCode):          "Error del torneo",
+		errorKey(appErrors.BonusErrorCode):               "Error del bono",
+		errorKey(appErrors.PromoErrorCode):               "Error de la promoción",
+		errorKey(appErrors.VoidErrorCode):                "Error al anular la ronda",
+		errorKey(appErrors.GambleErrorCode):              "Error de la apuesta doble",
+		errorKey(appErrors.ForbiddenErrorCode):           "Acceso denegado",
+
+		appErrors.DetailInsufficientFunds:       "la apuesta de %s supera el saldo disponible de %s",
+		appErrors.DetailBetNegative:             "el importe de la apuesta no puede ser negativo: %s",
+		appErrors.DetailBetZero:                 "el importe de la apuesta no puede ser cero",
+		appErrors.DetailBetBelowMin:             "la apuesta mínima es de %s",
+		appErrors.DetailBetAboveMax:             "la apuesta máxima es de %s",
+		appErrors.DetailAutoplayRounds:          "las rondas del juego automático deben estar entre 1 y %d",
+		appErrors.DetailBetType:                 "tipo de apuesta no admitido: %s",
+		appErrors.DetailBetNumber:               "el número apostado debe estar entre 1 y %d",
+		appErrors.DetailStopConditions:          "las condiciones de parada del juego automático no pueden ser negativas",
+		appErrors.DetailAutoplayRunning:         "el cliente %d tiene un juego automático en curso",
+		appErrors.DetailAutoplayIdle:            "el cliente %d no tiene un juego automático en curso",
+		appErrors.DetailUserNotFound:            "no se encontró ningún jugador con el ID %d",
+		appErrors.DetailNotConnected:            "el cliente %d no se ha conectado desde que se inició el servidor",
+		appErrors.DetailForbiddenPlayer:         "la conexión está autenticada como el cliente %d, no %d",
+		appErrors.DetailNoActiveSession:         "el cliente %d no tiene una sesión activa",
+		appErrors.DetailRealityCheck:            "confirma el control de realidad antes de jugar",
+		appErrors.DetailInvalidSeq:              "número de secuencia no válido: %d",
+		appErrors.DetailUnknownRound:            "ronda desconocida: %d",
+		appErrors.DetailBonusAmount:             "el importe del bono debe ser positivo: %s",
+		appErrors.DetailBonusWagering:           "el multiplicador de apuesta no puede ser negativo: %g",
+		appErrors.DetailBonusValidity:           "el bono debe ser válido durante un tiempo positivo",
+		appErrors.DetailBonusInactive:           "el bono %d ya no está activo",
+		appErrors.DetailBonusActive:             "el jugador %d ya tiene un bono activo",
+		appErrors.DetailPromoCodeRequired:       "el código promocional no puede estar vacío",
+		appErrors.DetailPromoUnknown:            "código promocional desconocido: %s",
+		appErrors.DetailPromoExpired:            "el código promocional %s ha caducado",
+		appErrors.DetailPromoExhausted:          "el código promocional %s se ha canjeado por completo",
+		appErrors.DetailPromoRedeemed:           "el código promocional %s ya fue canjeado",
+		appErrors.DetailPromoCodeLength:         "el código promocional no puede superar %d caracteres",
+		appErrors.DetailPromoKind:               "el tipo de código promocional debe ser free_bet o bonus: %s",
+		appErrors.DetailPromoBetAmount:          "el importe de la apuesta gratuita debe ser positivo: %s",
+		appErrors.DetailPromoFreeBetCount:       "el número de apuestas gratuitas debe ser positivo: %d",
+		appErrors.DetailPromoValidity:           "las recompensas promocionales deben ser válidas durante un periodo positivo",
+		appErrors.DetailPromoLimits:             "los límites de canje no pueden ser negativos",
+		appErrors.DetailPromoExpiry:             "el código promocional debe caducar en el futuro",
+		appErrors.DetailPromoExists:             "el código promocional %s ya existe",
+		appErrors.DetailFreeBetUnknown:          "apuesta gratuita desconocida: %d",
+		appErrors.DetailFreeBetUnavailable:      "la apuesta gratuita %d ya no está disponible",
+		appErrors.DetailVoidRoundID:             "ID de ronda no válido: %d",
+		appErrors.DetailVoidReason:              "el motivo de la anulación no puede estar vacío",
+		appErrors.DetailVoidOperator:            "el operador que anula no puede estar vacío",
+		appErrors.DetailVoidBalance:             "el saldo del jugador %d no cubre la reversión de la ronda %d",
+		appErrors.DetailVoidBonusClosed:         "el bono %d que financió la ronda %d se cerró después y no se puede restaurar",
+		appErrors.DetailVoidBonusBalance:        "el bono %d no cubre la reversión de la ronda %d",
+		appErrors.DetailVoidBonusActive:         "el jugador %d tiene otro bono activo, el bono %d no se puede reabrir",
+		appErrors.DetailVoidGambled:             "la ronda %d fue apostada al doble o nada y no se puede anular",
+		appErrors.DetailGambleDisabled:          "la apuesta doble está desactivada",
+		appErrors.DetailGambleBetType:           "el tipo de la apuesta doble debe ser par o impar: %s",
+		appErrors.DetailGambleNotOpen:           "el cliente %d no tiene una apuesta doble abierta",
+		appErrors.DetailGambleRoundRequired:     "el cliente %d no tiene una apuesta doble abierta, hay que indicar una ronda ganadora",
+		appErrors.DetailGamblePending:           "primero hay que cobrar o perder la apuesta doble de la ronda %d",
+		appErrors.DetailGambleOpen:              "el cliente %d debe cobrar o terminar primero la apuesta doble de la ronda %d",
+		appErrors.DetailGambleOtherSession:      "la ronda %d no pertenece a la sesión activa",
+		appErrors.DetailGambleNoWinnings:        "la ronda %d no tiene ganancias para doblar",
+		appErrors.DetailGambleNotCash:           "la ronda %d no se liquidó totalmente en efectivo y no se puede doblar",
+		appErrors.DetailGambleNotLast:           "solo se puede doblar la última ronda de la sesión, a la ronda %d le siguieron otras",
+		appErrors.DetailGambleRepeated:          "la ronda %d ya se dobló",
+		appErrors.DetailGambleAlreadyOpen:       "el jugador %d ya tiene una apuesta doble abierta",
+		appErrors.DetailGambleBalance:           "el saldo del jugador %d ya no cubre las ganancias de la ronda %d",
+		appErrors.DetailGambleSettled:           "la apuesta doble %d ya se liquidó",
+		appErrors.DetailTableUnknown:            "mesa desconocida: %d",
+		appErrors.DetailTableRoundRunning:       "la ronda %d de la mesa %d sigue en estado %s",
+		appErrors.DetailTableBettingClosed:      "las apuestas están cerradas en la mesa %d",
+		appErrors.DetailTableNotRolled:          "la mesa %d no tiene una ronda lanzada que liquidar",
+		appErrors.DetailTableNoRound:            "la mesa %d no tiene ninguna ronda en estado %s",
+		appErrors.DetailTableRoundOver:          "la ronda %d ya no se está jugando",
+		appErrors.DetailTableNotSeated:          "el cliente %d no está sentado en la mesa %d",
+		appErrors.DetailTableJoinFirst:          "el cliente %d debe unirse a la mesa %d antes de apostar",
+		appErrors.DetailTournamentUnknown:       "torneo desconocido: %d",
+		appErrors.DetailTournamentEnded:         "el torneo %d ha terminado",
+		appErrors.DetailTournamentNotRunning:    "el torneo %d no está en curso",
+		appErrors.DetailTournamentBuyIn:         "la inscripción de %s supera el saldo disponible de %s",
+		appErrors.DetailTournamentRegistered:    "el jugador %d ya está inscrito en el torneo %d",
+		appErrors.DetailTournamentNotRegistered: "el jugador %d no está inscrito en el torneo %d",
+		appErrors.DetailTournamentName:          "el nombre del torneo debe tener entre 1 y %d caracteres",
+		appErrors.DetailTournamentBuyInAmount:   "la inscripción del torneo no puede ser negativa: %s",
+		appErrors.DetailTournamentStack:         "el stack inicial debe ser positivo: %s",
+		appErrors.DetailTournamentRanking:       "la clasificación del torneo debe ser stack o profit: %s",
+		appErrors.DetailTournamentShares:        "las partes del premio deben ser positivas y sumar como máximo 1",
+		appErrors.DetailTournamentSchedule:      "el torneo debe terminar después de empezar y en el futuro",
+		NotificationRealityCheck:                "Tiempo de juego: %d min, rondas: %d, resultado neto: %s",
+		NotificationAutoplayEnd:                 "%s. Rondas: %d, resultado neto: %s",
+		NotificationTableRolled:                 "La mesa %d sacó un %d",
+		NotificationTableBetWon:                 "La mesa %d sacó un %d, has ganado %s",
+		NotificationTableBetLost:                "La mesa %d sacó un %d, has perdido %s",
+		NotificationTournamentEnded:             "El torneo %d ha terminado",
+		NotificationTournamentRank:              "El torneo %d ha terminado, has quedado en el puesto %d",
+		NotificationTournamentPrize:             "El torneo %d ha terminado, has quedado en el puesto %d y has ganado %s",
+		NotificationJackpot:                     "Bote: %s",
+		NotificationJackpotWon:                  "¡Bote de %s ganado! Nuevo bote: %s",
+
+		autoplayEndKey(domain.AutoplayCompleted):    "Juego automático completado",
+		autoplayEndKey(domain.AutoplayStopped):      "Juego automático detenido",
+		autoplayEndKey(domain.AutoplayLossLimit):    "Juego automático detenido en el límite de pérdidas",
+		autoplayEndKey(domain.AutoplayWinLimit):     "Juego automático detenido en el límite de ganancias",
+		autoplayEndKey(domain.AutoplayBalanceLimit): "Juego automático detenido en el límite de saldo",
+		autoplayEndKey(domain.AutoplayRealityCheck): "Juego automático detenido por un control de realidad",
+		autoplayEndKey(domain.AutoplayError):        "Juego automático detenido tras un error",
+
+		achievementKey(domain.AchievementWinStreak, "name"):            "En racha",
+		achievementKey(domain.AchievementWinStreak, "description"):     "Gana cinco rondas seguidas",
+		achievementKey(domain.AchievementRoundsPlayed, "name"):         "Habitual",
+		achievementKey(domain.AchievementRoundsPlayed, "description"):  "Juega 100 rondas",
+		achievementKey(domain.AchievementFirstExactHit, "name"):        "En el blanco",
+		achievementKey(domain.AchievementFirstExactHit, "description"): "Gana una apuesta a número exacto",
+	},
+	language.BrazilianPortuguese: {
+		errorKey(appErrors.InternalErrorCode):            "Erro interno do servidor",
+		errorKey(appErrors.InvalidInputErrorCode):        "Dados de entrada inválidos",
+		errorKey(appErrors.InsufficientFundsErrorCode):   "A aposta excede o saldo disponível",
+		errorKey(appErrors.InvalidBetAmountErrorCode):    "Valor de aposta inválido",
+		errorKey(appErrors.UserNotFoundErrorCode):        "Usuário não encontrado",
+		errorKey(appErrors.ActiveSessionErrorCode):       "Erro de sessão ativa",
+		errorKey(appErrors.DiceRollErrorCode):            "Erro ao rolar o dado",
+		errorKey(appErrors.RealityCheckPendingErrorCode): "A verificação de realidade precisa ser confirmada",
+		errorKey(appErrors.TableRoundErrorCode):          "Erro na rodada da mesa",
+		errorKey(appErrors.TournamentErrorCode):          "Erro do torneio",
+		errorKey(appErrors.BonusErrorCode):               "Erro do bônus",
+		errorKey(appErrors.PromoErrorCode):               "Erro da promoção",
+		errorKey(appErrors.VoidErrorCode):                "Erro ao anular a rodada",
+		errorKey(appErrors.GambleErrorCode):              "Erro da aposta dobrada",
+		errorKey(appErrors.ForbiddenErrorCode):           "Acesso negado",
+
+		appErrors.DetailInsufficientFunds:       "a aposta de %s excede o saldo disponível de %s",
+		appErrors.DetailBetNegative:             "o valor da aposta não pode ser negativo: %s",
+		appErrors.DetailBetZero:                 "o valor da aposta não pode ser zero",
+		appErrors.DetailBetBelowMin:             "a aposta mínima é de %s",
+		appErrors.DetailBetAboveMax:             "a aposta máxima é de %s",
+		appErrors.DetailAutoplayRounds:          "as rodadas do jogo automático devem estar entre 1 e %d",
+		appErrors.DetailBetType:                 "tipo de aposta não suportado: %s",
+		appErrors.DetailBetNumber:               "o número apostado deve estar entre 1 e %d",
+		appErrors.DetailStopConditions:          "as condições de parada do jogo automático não podem ser negativas",
+		appErrors.DetailAutoplayRunning:         "o cliente %d tem um jogo automático em andamento",
+		appErrors.DetailAutoplayIdle:            "o cliente %d não tem um jogo automático em andamento",
+		appErrors.DetailUserNotFound:            "nenhum jogador encontrado com o ID %d",
+		appErrors.DetailNotConnected:            "o cliente %d não se conectou desde o início do servidor",
+		appErrors.DetailForbiddenPlayer:         "a conexão está autenticada como o cliente %d, não %d",
+		appErrors.DetailNoActiveSession:         "o cliente %d não tem uma sessão ativa",
+		appErrors.DetailRealityCheck:            "confirme a verificação de realidade antes de jogar",
+		appErrors.DetailInvalidSeq:              "número de sequência inválido: %d",
+		appErrors.DetailUnknownRound:            "rodada desconhecida: %d",
+		appErrors.DetailBonusAmount:             "o valor do bônus deve ser positivo: %s",
+		appErrors.DetailBonusWagering:           "o multiplicador de apostas não pode ser negativo: %g",
+		appErrors.DetailBonusValidity:           "o bônus deve ser válido por um período positivo",
+		appErrors.DetailBonusInactive:           "o bônus %d não está mais ativo",
+		appErrors.DetailBonusActive:             "o jogador %d já tem um bônus ativo",
+		appErrors.DetailPromoCodeRequired:       "o código promocional não pode estar vazio",
+		appErrors.DetailPromoUnknown:            "código promocional desconhecido: %s",
+		appErrors.DetailPromoExpired:            "o código promocional %s expirou",
+		appErrors.DetailPromoExhausted:          "o código promocional %s foi totalmente resgatado",
+		appErrors.DetailPromoRedeemed:           "o código promocional %s já foi resgatado",
+		appErrors.DetailPromoCodeLength:         "o código promocional não pode exceder %d caracteres",
+		appErrors.DetailPromoKind:               "o tipo de código promocional deve ser free_bet ou bonus: %s",
+		appErrors.DetailPromoBetAmount:          "o valor da aposta grátis deve ser positivo: %s",
+		appErrors.DetailPromoFreeBetCount:       "o número de apostas grátis deve ser positivo: %d",
+		appErrors.DetailPromoValidity:           "as recompensas promocionais devem ser válidas por um período positivo",
+		appErrors.DetailPromoLimits:             "os limites de resgate não podem ser negativos",
+		appErrors.DetailPromoExpiry:             "o código promocional deve expirar no futuro",
+		appErrors.DetailPromoExists:             "o código promocional %s já existe",
+		appErrors.DetailFreeBetUnknown:          "aposta grátis desconhecida: %d",
+		appErrors.DetailFreeBetUnavailable:      "a aposta grátis %d não está mais disponível",
+		appErrors.DetailVoidRoundID:             "ID de rodada inválido: %d",
+		appErrors.DetailVoidReason:              "o motivo da anulação não pode estar vazio",
+		appErrors.DetailVoidOperator:            "o operador da anulação não pode estar vazio",
+		appErrors.DetailVoidBalance:             "o saldo do jogador %d não cobre o estorno da rodada %d",
+		appErrors.DetailVoidBonusClosed:         "o bônus %d que financiou a rodada %d foi encerrado depois e não pode ser restaurado",
+		appErrors.DetailVoidBonusBalance:        "o bônus %d não cobre o estorno da rodada %d",
+		appErrors.DetailVoidBonusActive:         "o jogador %d tem outro bônus ativo, o bônus %d não pode ser reaberto",
+		appErrors.DetailVoidGambled:             "a rodada %d foi apostada no dobro ou nada e não pode ser anulada",
+		appErrors.DetailGambleDisabled:          "a aposta dobrada está desativada",
+		appErrors.DetailGambleBetType:           "o tipo da aposta dobrada deve ser par ou ímpar: %s",
+		appErrors.DetailGambleNotOpen:           "o cliente %d não tem uma aposta dobrada aberta",
+		appErrors.DetailGambleRoundRequired:     "o cliente %d não tem uma aposta dobrada aberta, uma rodada vencedora deve ser informada",
+		appErrors.DetailGamblePending:           "a aposta dobrada da rodada %d deve ser recolhida ou perdida primeiro",
+		appErrors.DetailGambleOpen:              "o cliente %d deve recolher ou terminar primeiro a aposta dobrada da rodada %d",
+		appErrors.DetailGambleOtherSession:      "a rodada %d não pertence à sessão ativa",
+		appErrors.DetailGambleNoWinnings:        "a rodada %d não tem ganhos para dobrar",
+		appErrors.DetailGambleNotCash:           "a rodada %d não foi liquidada totalmente em dinheiro e não pode ser dobrada",
+		appErrors.DetailGambleNotLast:           "só a última rodada da sessão pode ser dobrada, a rodada %d foi seguida por outras",
+		appErrors.DetailGambleRepeated:          "a rodada %d já foi dobrada",
+		appErrors.DetailGambleAlreadyOpen:       "o jogador %d já tem uma aposta dobrada aberta",
+		appErrors.DetailGambleBalance:           "o saldo do jogador %d não cobre mais os ganhos da rodada %d",
+		appErrors.DetailGambleSettled:           "a aposta dobrada %d já foi liquidada",
+		appErrors.DetailTableUnknown:            "mesa desconhecida: %d",
+		appErrors.DetailTableRoundRunning:       "a rodada %d da mesa %d ainda está no estado %s",
+		appErrors.DetailTableBettingClosed:      "as apostas estão encerradas na mesa %d",
+		appErrors.DetailTableNotRolled:          "a mesa %d não tem uma rodada rolada para liquidar",
+		appErrors.DetailTableNoRound:            "a mesa %d não tem nenhuma rodada no estado %s",
+		appErrors.DetailTableRoundOver:          "a rodada %d não está mais sendo jogada",
+		appErrors.DetailTableNotSeated:          "o cliente %d não está sentado na mesa %d",
+		appErrors.DetailTableJoinFirst:          "o cliente %d deve entrar na mesa %d antes de apostar",
+		appErrors.DetailTournamentUnknown:       "torneio desconhecido: %d",
+		appErrors.DetailTournamentEnded:         "o torneio %d terminou",
+		appErrors.DetailTournamentNotRunning:    "o torneio %d não está em andamento",
+		appErrors.DetailTournamentBuyIn:         "a inscrição de %s excede o saldo disponível de %s",
+		appErrors.DetailTournamentRegistered:    "o jogador %d já está inscrito no torneio %d",
+		appErrors.DetailTournamentNotRegistered: "o jogador %d não está inscrito no torneio %d",
+		appErrors.DetailTournamentName:          "o nome do torneio deve ter entre 1 e %d caracteres",
+		appErrors.DetailTournamentBuyInAmount:   "a inscrição do torneio não pode ser negativa: %s",
+		appErrors.DetailTournamentStack:         "a pilha inicial deve ser positiva: %s",
+		appErrors.DetailTournamentRanking:       "a classificação do torneio deve ser stack ou profit: %s",
+		appErrors.DetailTournamentShares:        "as partes do prêmio devem ser positivas e somar no máximo 1",
+		appErrors.DetailTournamentSchedule:      "o torneio deve terminar depois de começar e no futuro",
+		NotificationRealityCheck:                "Tempo de jogo: %d min, rodadas: %d, resultado líquido: %s",
+		NotificationAutoplayEnd:                 "%s. Rodadas: %d, resultado líquido: %s",
+		NotificationTableRolled:                 "A mesa %d tirou um %d",
+		NotificationTableBetWon:                 "A mesa %d tirou um %d, você ganhou %s",
+		NotificationTableBetLost:                "A mesa %d tirou um %d, você perdeu %s",
+		NotificationTournamentEnded:             "O torneio %d terminou",
+		NotificationTournamentRank:              "O torneio %d terminou, você ficou na posição %d",
+		NotificationTournamentPrize:             "O torneio %d terminou, você ficou na posição %d e ganhou %s",
+		NotificationJackpot:                     "Jackpot: %s",
+		NotificationJackpotWon:                  "Jackpot de %s ganho! Novo jackpot: %s",
+
+		autoplayEndKey(domain.AutoplayCompleted):    "Jogo automático concluído",
+		autoplayEndKey(domain.AutoplayStopped):      "Jogo automático interrompido",
+		autoplayEndKey(domain.AutoplayLossLimit):    "Jogo automático interrompido no limite de perdas",
+		autoplayEndKey(domain.AutoplayWinLimit):     "Jogo automático interrompido no limite de ganhos",
+		autoplayEndKey(domain.AutoplayBalanceLimit): "Jogo automático interrompido no limite de saldo",
+		autoplayEndKey(domain.AutoplayRealityCheck): "Jogo automático interrompido por uma verificação de realidade",
+		autoplayEndKey(domain.AutoplayError):        "Jogo automático interrompido após um erro",
+
+		achievementKey(domain.AchievementWinStreak, "name"):            "Em chamas",
+		achievementKey(domain.AchievementWinStreak, "description"):     "Vença cinco rodadas seguidas",
+		achievementKey(domain.AchievementRoundsPlayed, "name"):         "Frequentador",
+		achievementKey(domain.AchievementRoundsPlayed, "description"):  "Jogue 100 rodadas",
+		achievementKey(domain.AchievementFirstExactHit, "name"):        "Na mosca",
+		achievementKey(domain.AchievementFirstExactHit, "description"): "Vença uma aposta em número exato",
+	},
+}
+
+func init() {
+	for _, definition := range appErrors.Definitions() {
+		translations[language.English][errorKey(definition.Code)] = definition.Message
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// AchievementRepository reads the settled rounds achievements are checked against and keeps the unlocked ones
+type AchievementRepository interface {
+	GetPlayerStats(playerID int) (domain.PlayerStats, error)
+	GetUnlockedAchievements(playerID int) (map[domain.AchievementID]time.Time, error)
+	UnlockAchievement(playerID int, achievementID domain.AchievementID) (time.Time, bool, error)
+}
+
+// GetPlayerStats totals the rounds of a player over all of their sessions, leaving voided rounds out
+func (gr *GameRepository) GetPlayerStats(playerID int) (domain.PlayerStats, error) {
+	var stats domain.PlayerStats
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE bet_type = $2 AND won),
+			COUNT(*) FILTER (WHERE round_id > COALESCE(
+				(SELECT MAX(round_id) FROM round WHERE player_id = $1 AND NOT won AND NOT voided), 0))
+		FROM round
+		WHERE player_id = $1 AND NOT voided
+	;`
+	err := gr.db.QueryRow(query, playerID, domain.Exact).Scan(&stats.RoundsPlayed, &stats.ExactHits, &stats.WinStreak)
+	if err != nil {
+		return domain.PlayerStats{}, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return stats, nil
+}
+
+// GetUnlockedAchievements returns when the player unlocked each of their achievements
+func (gr *GameRepository) GetUnlockedAchievements(playerID int) (map[domain.AchievementID]time.Time, error) {
+	rows, err := gr.db.Query(`SELECT achievement_id, unlocked_at FROM player_achievement WHERE player_id = $1`, playerID)
+	if err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	defer rows.Close()
+
+	unlocked := make(map[domain.AchievementID]time.Time)
+	for rows.Next() {
+		var id domain.AchievementID
+		var unlockedAt time.Time
+		if err := rows.Scan(&id, &unlockedAt); err != nil {
+			return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+		}
+		unlocked[id] = unlockedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return unlocked, nil
+}
+
+// UnlockAchievement records an achievement of a player, reporting whether this call unlocked it
+// An achievement already unlocked, e.g. by a concurrent play of the player, keeps its first unlock time and reports false
+func (gr *GameRepository) UnlockAchievement(playerID int, achievementID domain.AchievementID) (time.Time, bool, error) {
+	var unlockedAt time.Time
+	query := `
+		INSERT INTO player_achievement (player_id, achievement_id)
+		VALUES ($1, $2)
+		ON CONFLICT (player_id, achievement_id) DO NOTHING
+		RETURNING unlocked_at
+	;`
+	err := gr.db.QueryRow(query, playerID, achievementID).Scan(&unlockedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to unlock achievement %s for player id %d: %w", achievementID, playerID, err)
+	}
+	return unlockedAt, true, nil
+}
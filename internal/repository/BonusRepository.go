@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/lib/pq"
+)
+
+// BonusRepository persists the bonus wallet of players next to their cash balance
+type BonusRepository interface {
+	GetActiveBonus(playerID int) (*domain.PlayerBonus, error)
+	GrantBonus(bonus domain.PlayerBonus) (domain.PlayerBonus, error)
+	ForfeitExpiredBonuses() (int64, error)
+}
+
+// activeBonusIndex is the unique index allowing a single active bonus per player
+const activeBonusIndex = "player_bonus_active_idx"
+
+const bonusColumns = `bonus_id, player_id, amount, granted_amount, wagering_requirement, wagered, status, granted_at, expires_at`
+
+// GetActiveBonus returns the active bonus of a player that has not expired yet, or nil when there is none
+func (gr *GameRepository) GetActiveBonus(playerID int) (*domain.PlayerBonus, error) {
+	query := `
+		SELECT ` + bonusColumns + ` FROM player_bonus
+		WHERE player_id = $1 AND status = $2 AND expires_at > NOW()
+	;`
+	bonus, err := scanBonus(gr.db.QueryRow(query, playerID, domain.BonusActive))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return &bonus, nil
+}
+
+// GrantBonus credits new bonus funds, a player can only hold one active bonus at a time
+func (gr *GameRepository) GrantBonus(bonus domain.PlayerBonus) (domain.PlayerBonus, error) {
+	return gr.insertBonus(gr.db, bonus)
+}
+
+// ForfeitExpiredBonuses forfeits the remaining funds of every active bonus past its expiry
+func (gr *GameRepository) ForfeitExpiredBonuses() (int64, error) {
+	query := `
+		UPDATE player_bonus
+		SET status = $1, closed_at = NOW()
+		WHERE status = $2 AND expires_at <= NOW()
+	;`
+	result, err := gr.db.Exec(query, domain.BonusForfeited, domain.BonusActive)
+	if err != nil {
+		return 0, fmt.Errorf("failed to forfeit expired bonuses: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// updateBonus splits a play between the locked cash balance and the locked bonus and applies it to the bonus,
+// returning the converted amount so it can be credited to the cash balance in the same transaction
+func (gr *GameRepository) updateBonus(tx *sql.Tx, t *domain.PlayTransaction, cash float64, playedAt time.Time) (balance float64, converted float64, err error) {
+	lockQuery := `
+		SELECT ` + bonusColumns + ` FROM player_bonus
+		WHERE bonus_id = $1 AND player_id = $2
+		FOR UPDATE
+	;`
+	bonus, err := scanBonus(tx.QueryRow(lockQuery, t.BonusID, t.Message.ClientID))
+	if err != nil {
+		return 0, 0, fmt.Errorf("error locking bonus id %d: %w", t.BonusID, err)
+	}
+	splitBonusPlay(t, cash, bonus.Amount)
+	converted, err = applyBonusPlay(&bonus, *t, playedAt)
+	if err != nil {
+		return 0, 0, err
+	}
+	if converted > 0 {
+		if err := gr.addLedgerEntry(tx, t.Message.ClientID, converted, domain.LedgerBonusConversion, "bonus:"+strconv.Itoa(bonus.BonusID)); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	updateQuery := `
+		UPDATE player_bonus
+		SET amount = $1, wagered = $2, status = $3,
+			closed_at = CASE WHEN $3 = 'active' THEN NULL ELSE NOW() END
+		WHERE bonus_id = $4
+	;`
+	if _, err := tx.Exec(updateQuery, bonus.Amount, bonus.Wagered, bonus.Status, bonus.BonusID); err != nil {
+		return 0, 0, fmt.Errorf("failed to update bonus id %d: %w", bonus.BonusID, err)
+	}
+	return bonus.Amount, converted, nil
+}
+
+// splitBonusPlay divides the change of a play between the cash and the bonus wallet from their locked balances
+// Stakes are consumed following the stake order and the payout is credited back to each wallet
+// in proportion to the share of the stake it covered
+func splitBonusPlay(t *domain.PlayTransaction, cash, bonus float64) {
+	betAmount := t.Message.BetAmount
+	var bonusStake float64
+	if t.BonusStakeOrder == domain.BonusStakeBonusFirst {
+		bonusStake = math.Min(betAmount, bonus)
+	} else {
+		bonusStake = math.Max(betAmount-cash, 0)
+	}
+	cashStake := betAmount - bonusStake
+
+	bonusPayout := math.Round(t.Payout*bonusStake/betAmount*100) / 100
+	t.ChangeAmount = (t.Payout - bonusPayout) - cashStake
+	t.BonusChangeAmount = bonusPayout - bonusStake
+}
+
+// applyBonusPlay applies the bonus share of a play to the bonus balance and counts the whole stake towards
+// the wagering requirement. Once the requirement is met the remaining bonus converts to cash and is returned.
+// A depleted bonus is forfeited
+func applyBonusPlay(bonus *domain.PlayerBonus, t domain.PlayTransaction, at time.Time) (converted float64, err error) {
+	if bonus.Status != domain.BonusActive || !at.Before(bonus.ExpiresAt) {
+		return 0, appErrors.NewBonusError(fmt.Sprintf("bonus %d is no longer active", bonus.BonusID)).WithDetail(appErrors.DetailBonusInactive, bonus.BonusID)
+	}
+
+	amount := bonus.Amount + t.BonusChangeAmount
+	if err := validateBalance(amount); err != nil {
+		return 0, err
+	}
+	bonus.Amount = amount
+	bonus.Wagered += t.Message.BetAmount
+	switch {
+	case bonus.Wagered >= bonus.WageringRequirement:
+		converted = bonus.Amount
+		bonus.Amount = 0
+		bonus.Status = domain.BonusCompleted
+	case bonus.Amount == 0:
+		bonus.Status = domain.BonusForfeited
+	}
+	return converted, nil
+}
+
+// insertBonus creates an active bonus, either on its own or as part of a larger transaction
+func (gr *GameRepository) insertBonus(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}, bonus domain.PlayerBonus) (domain.PlayerBonus, error) {
+	query := `
+		INSERT INTO player_bonus (player_id, amount, granted_amount, wagering_requirement, status, expires_at)
+		VALUES ($1, $2, $2, $3, $4, $5)
+		RETURNING ` + bonusColumns + `
+	;`
+	granted, err := scanBonus(q.QueryRow(query, bonus.PlayerID, bonus.GrantedAmount, bonus.WageringRequirement, domain.BonusActive, bonus.ExpiresAt))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == activeBonusIndex {
+			return domain.PlayerBonus{}, appErrors.NewBonusError(fmt.Sprintf("player %d already has an active bonus", bonus.PlayerID)).WithDetail(appErrors.DetailBonusActive, bonus.PlayerID)
+		}
+		return domain.PlayerBonus{}, fmt.Errorf("error granting bonus to player id %d: %w", bonus.PlayerID, err)
+	}
+	return granted, nil
+}
+
+// scanBonus reads a row selected with bonusColumns
+func scanBonus(row interface{ Scan(...any) error }) (domain.PlayerBonus, error) {
+	var bonus domain.PlayerBonus
+	err := row.Scan(
+		&bonus.BonusID,
+		&bonus.PlayerID,
+		&bonus.Amount,
+		&bonus.GrantedAmount,
+		&bonus.WageringRequirement,
+		&bonus.Wagered,
+		&bonus.Status,
+		&bonus.GrantedAt,
+		&bonus.ExpiresAt,
+	)
+	return bonus, err
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// DeliveryRepository keeps the settlement messages of each player until they are acknowledged
+type DeliveryRepository interface {
+	AckSettlementMessages(playerID int, seq int64) error
+	ListUnackedMessages(playerID int) ([]domain.SettlementMessage, error)
+	PurgeSettlementMessages(before time.Time) (int64, error)
+}
+
+// storeSettlementMessageQuery keeps a settlement message under the next sequence number of the player
+// The counter lives on the player row so concurrent settlements of a player are numbered one after the other
+const storeSettlementMessageQuery = `
+	WITH next AS (
+		UPDATE player SET message_seq = message_seq + 1 WHERE id = $1 RETURNING id, message_seq
+	)
+	INSERT INTO settlement_message (player_id, seq, message_type, payload)
+	SELECT id, message_seq, $2, $3::jsonb FROM next
+	RETURNING seq, created_at
+;`
+
+// storeSettlementMessage keeps the message reporting an outcome in the transaction settling it,
+// so the outcome and its message are committed together
+func (gr *GameRepository) storeSettlementMessage(tx *sql.Tx, playerID int, msgType domain.MessageType, data interface{}) (domain.SettlementMessage, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.SettlementMessage{}, fmt.Errorf("failed to marshal %s settlement message: %w", msgType, err)
+	}
+	message := domain.SettlementMessage{PlayerID: playerID, Type: msgType, Payload: payload}
+	if err := tx.QueryRow(storeSettlementMessageQuery, playerID, msgType, string(payload)).Scan(&message.Seq, &message.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.SettlementMessage{}, appErrors.NewUserNotFoundError(fmt.Sprintf("No player found with ID: %d", playerID)).WithDetail(appErrors.DetailUserNotFound, playerID)
+		}
+		return domain.SettlementMessage{}, fmt.Errorf("failed to store %s settlement message: %w", msgType, err)
+	}
+	return message, nil
+}
+
+// AckSettlementMessages drops every settlement message of the player up to and including seq
+func (gr *GameRepository) AckSettlementMessages(playerID int, seq int64) error {
+	if _, err := gr.db.Exec(`DELETE FROM settlement_message WHERE player_id = $1 AND seq <= $2`, playerID, seq); err != nil {
+		return appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return nil
+}
+
+// ListUnackedMessages returns the settlement messages the player has not acknowledged yet, oldest first
+func (gr *GameRepository) ListUnackedMessages(playerID int) ([]domain.SettlementMessage, error) {
+	query := `
+		SELECT player_id, seq, message_type, payload, created_at FROM settlement_message
+		WHERE player_id = $1
+		ORDER BY seq
+	;`
+	rows, err := gr.db.Query(query, playerID)
+	if err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	defer rows.Close()
+
+	var messages []domain.SettlementMessage
+	for rows.Next() {
+		var message domain.SettlementMessage
+		if err := rows.Scan(&message.PlayerID, &message.Seq, &message.Type, &message.Payload, &message.CreatedAt); err != nil {
+			return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return messages, nil
+}
+
+// PurgeSettlementMessages drops the settlement messages stored before the given time, acknowledged or not
+func (gr *GameRepository) PurgeSettlementMessages(before time.Time) (int64, error) {
+	result, err := gr.db.Exec(`DELETE FROM settlement_message WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge settlement messages: %w", err)
+	}
+	return result.RowsAffected()
+}
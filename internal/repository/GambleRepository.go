@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/lib/pq"
+)
+
+// GambleRepository persists the double-or-nothing gambles played on the winnings of a round
+type GambleRepository interface {
+	GetOpenGamble(playerID int) (*domain.Gamble, error)
+	StartGamble(roundID, playerID int) (domain.Gamble, error)
+	SettleGamble(res domain.GambleResponse, prevStep int, msgType domain.MessageType) (domain.GambleResponse, error)
+}
+
+// Constraints keeping a round from being gambled twice and a player from holding two open gambles
+const (
+	gambleRoundConstraint = "gamble_round_id_key"
+	openGambleIndex       = "gamble_open_idx"
+)
+
+const gambleColumns = `gamble_id, session_id, round_id, player_id, stake, amount, step, status, started_at`
+
+// GetOpenGamble returns the open gamble of a player, or nil when there is none
+func (gr *GameRepository) GetOpenGamble(playerID int) (*domain.Gamble, error) {
+	query := `SELECT ` + gambleColumns + ` FROM gamble WHERE player_id = $1 AND status = $2`
+	gamble, err := scanGamble(gr.db.QueryRow(query, playerID, domain.GambleOpen))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return &gamble, nil
+}
+
+// StartGamble opens a gamble on the payout of the last round of the active session, taking the payout
+// from the balance until the gamble is collected. Only rounds staked and paid entirely in cash can be gambled,
+// rounds funded by a free bet or touching the bonus wallet or the jackpot are refused
+func (gr *GameRepository) StartGamble(roundID, playerID int) (domain.Gamble, error) {
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.Gamble{}, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	session, err := gr.getActiveSession(tx, playerID)
+	if err != nil {
+		return domain.Gamble{}, err
+	}
+	if session == nil {
+		return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("player %d has no active session", playerID)).WithDetail(appErrors.DetailNoActiveSession, playerID)
+	}
+
+	var sessionID int
+	var payout float64
+	var won, voided, cashOnly bool
+	lockQuery := `
+		SELECT session_id, payout, won, voided,
+			balance_change = payout - bet_amount AND NOT EXISTS (SELECT 1 FROM free_bet WHERE free_bet.round_id = round.round_id)
+		FROM round
+		WHERE round_id = $1 AND player_id = $2
+		FOR UPDATE OF round
+	;`
+	err = tx.QueryRow(lockQuery, roundID, playerID).Scan(&sessionID, &payout, &won, &voided, &cashOnly)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("unknown round: %d", roundID)).WithDetail(appErrors.DetailUnknownRound, roundID)
+		}
+		return domain.Gamble{}, fmt.Errorf("error locking round id %d: %w", roundID, err)
+	}
+	if sessionID != session.SessionID {
+		return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("round %d does not belong to the active session", roundID)).WithDetail(appErrors.DetailGambleOtherSession, roundID)
+	}
+	if !won || voided || payout <= 0 {
+		return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("round %d has no winnings to gamble", roundID)).WithDetail(appErrors.DetailGambleNoWinnings, roundID)
+	}
+	if !cashOnly {
+		return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("round %d was not settled entirely in cash and cannot be gambled", roundID)).WithDetail(appErrors.DetailGambleNotCash, roundID)
+	}
+
+	var lastRoundID int
+	if err := tx.QueryRow(`SELECT MAX(round_id) FROM round WHERE session_id = $1`, sessionID).Scan(&lastRoundID); err != nil {
+		return domain.Gamble{}, fmt.Errorf("error reading last round of session id %d: %w", sessionID, err)
+	}
+	if lastRoundID != roundID {
+		return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("only the last round of the session can be gambled, round %d was followed by others", roundID)).WithDetail(appErrors.DetailGambleNotLast, roundID)
+	}
+
+	insertQuery := `
+		INSERT INTO gamble (session_id, round_id, player_id, stake, amount, status)
+		VALUES ($1, $2, $3, $4, $4, $5)
+		RETURNING ` + gambleColumns + `
+	;`
+	gamble, err := scanGamble(tx.QueryRow(insertQuery, sessionID, roundID, playerID, payout, domain.GambleOpen))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Constraint {
+			case gambleRoundConstraint:
+				return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("round %d was already gambled", roundID)).WithDetail(appErrors.DetailGambleRepeated, roundID)
+			case openGambleIndex:
+				return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("player %d already has an open gamble", playerID)).WithDetail(appErrors.DetailGambleAlreadyOpen, playerID)
+			}
+		}
+		return domain.Gamble{}, fmt.Errorf("error starting gamble on round id %d: %w", roundID, err)
+	}
+
+	if _, err := gr.updateBalance(tx, domain.BalanceUpdate{PlayerID: playerID, ChangeAmount: -payout}); err != nil {
+		if errors.Is(err, ErrNegativeBalance) {
+			return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("balance of player %d no longer covers the winnings of round %d", playerID, roundID)).WithDetail(appErrors.DetailGambleBalance, playerID, roundID)
+		}
+		return domain.Gamble{}, err
+	}
+	if err := gr.addLedgerEntry(tx, playerID, -payout, domain.LedgerGambleStake, gambleReference(gamble.GambleID)); err != nil {
+		return domain.Gamble{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Gamble{}, fmt.Errorf("failed to commit start gamble transaction: %w", err)
+	}
+	return gamble, nil
+}
+
+// SettleGamble stores the outcome of a gamble step, crediting the amount once the gamble is collected,
+// and keeps the message reporting it in the same transaction. prevStep guards against two steps of the same gamble
+// being settled concurrently
+func (gr *GameRepository) SettleGamble(res domain.GambleResponse, prevStep int, msgType domain.MessageType) (domain.GambleResponse, error) {
+	next := res.Gamble
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.GambleResponse{}, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	updateQuery := `
+		UPDATE gamble
+		SET amount = $1, step = $2, status = $3,
+			closed_at = CASE WHEN $3 = 'open' THEN NULL ELSE NOW() END
+		WHERE gamble_id = $4 AND status = $5 AND step = $6
+	;`
+	result, err := tx.Exec(updateQuery, next.Amount, next.Step, next.Status, next.GambleID, domain.GambleOpen, prevStep)
+	if err != nil {
+		return domain.GambleResponse{}, fmt.Errorf("failed to update gamble id %d: %w", next.GambleID, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return domain.GambleResponse{}, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domain.GambleResponse{}, appErrors.NewGambleError(fmt.Sprintf("gamble %d was already settled", next.GambleID)).WithDetail(appErrors.DetailGambleSettled, next.GambleID)
+	}
+
+	if next.Status == domain.GambleCollected && next.Amount > 0 {
+		res.Balance, err = gr.updateBalance(tx, domain.BalanceUpdate{PlayerID: next.PlayerID, ChangeAmount: next.Amount})
+		if err != nil {
+			return domain.GambleResponse{}, err
+		}
+		if err := gr.addLedgerEntry(tx, next.PlayerID, next.Amount, domain.LedgerGambleCollect, gambleReference(next.GambleID)); err != nil {
+			return domain.GambleResponse{}, err
+		}
+	} else if err := tx.QueryRow(`SELECT balance FROM player WHERE id = $1`, next.PlayerID).Scan(&res.Balance); err != nil {
+		return domain.GambleResponse{}, fmt.Errorf("error reading balance of player id %d: %w", next.PlayerID, err)
+	}
+
+	message, err := gr.storeSettlementMessage(tx, next.PlayerID, msgType, res)
+	if err != nil {
+		return domain.GambleResponse{}, err
+	}
+	res.Seq = message.Seq
+
+	if err := tx.Commit(); err != nil {
+		return domain.GambleResponse{}, fmt.Errorf("failed to commit gamble transaction: %w", err)
+	}
+	return res, nil
+}
+
+// gambleReference identifies a gamble in the ledger
+func gambleReference(gambleID int) string {
+	return "gamble:" + strconv.Itoa(gambleID)
+}
+
+// scanGamble reads a row selected with gambleColumns
+func scanGamble(row interface{ Scan(...any) error }) (domain.Gamble, error) {
+	var gamble domain.Gamble
+	err := row.Scan(
+		&gamble.GambleID,
+		&gamble.SessionID,
+		&gamble.RoundID,
+		&gamble.PlayerID,
+		&gamble.Stake,
+		&gamble.Amount,
+		&gamble.Step,
+		&gamble.Status,
+		&gamble.StartedAt,
+	)
+	return gamble, err
+}
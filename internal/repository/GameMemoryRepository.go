@@ -0,0 +1,404 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// MemoryRepository keeps players and game sessions in memory
+// Useful to run the game rules in process without a database, e.g. in simulations
+type MemoryRepository struct {
+	mu             sync.Mutex
+	balances       map[int]float64
+	tiers          map[int]domain.TierLimits
+	activeSessions map[int]domain.GameSession
+	nextSessionID  int
+	summaries      map[int]*domain.SessionSummary
+	nextRoundID    int
+	stats          map[int]*domain.PlayerStats
+	achievements   map[int]map[domain.AchievementID]time.Time
+	jackpotPool    float64
+	bonuses        map[int]*domain.PlayerBonus
+	nextBonusID    int
+	messages       map[int][]domain.SettlementMessage
+	messageSeqs    map[int]int64
+}
+
+// maxMemoryMessages is the number of unacknowledged settlement messages kept per player, the oldest are dropped
+// first so players that never acknowledge, such as simulated ones, do not grow the repository without bound
+const maxMemoryMessages = 100
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		balances:       make(map[int]float64),
+		tiers:          make(map[int]domain.TierLimits),
+		activeSessions: make(map[int]domain.GameSession),
+		nextSessionID:  1,
+		summaries:      make(map[int]*domain.SessionSummary),
+		nextRoundID:    1,
+		stats:          make(map[int]*domain.PlayerStats),
+		achievements:   make(map[int]map[domain.AchievementID]time.Time),
+		bonuses:        make(map[int]*domain.PlayerBonus),
+		nextBonusID:    1,
+		messages:       make(map[int][]domain.SettlementMessage),
+		messageSeqs:    make(map[int]int64),
+	}
+}
+
+// AddPlayer registers a standard tier player with the given balance
+func (m *MemoryRepository) AddPlayer(playerID int, balance float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balances[playerID] = balance
+	m.tiers[playerID] = domain.TierLimits{Tier: domain.TierStandard}
+}
+
+func (m *MemoryRepository) GetBalance(playerID int) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	balance, ok := m.balances[playerID]
+	if !ok {
+		return 0, appErrors.NewUserNotFoundError(fmt.Sprintf("No player found with ID: %d", playerID)).WithDetail(appErrors.DetailUserNotFound, playerID)
+	}
+	return balance, nil
+}
+
+func (m *MemoryRepository) GetTierLimits(playerID int) (domain.TierLimits, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	limits, ok := m.tiers[playerID]
+	if !ok {
+		return domain.TierLimits{}, appErrors.NewUserNotFoundError(fmt.Sprintf("No player found with ID: %d", playerID)).WithDetail(appErrors.DetailUserNotFound, playerID)
+	}
+	return limits, nil
+}
+
+func (m *MemoryRepository) GetActiveSession(playerID int) (*domain.GameSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.activeSessions[playerID]
+	if !ok {
+		return nil, nil
+	}
+	return &session, nil
+}
+
+// CloseCurrentGameSession closes the active session of a player, rounds are not stored so the summary
+// comes from the totals kept while the session was open
+func (m *MemoryRepository) CloseCurrentGameSession(clientID int) (domain.SessionSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.activeSessions[clientID]; !ok {
+		return domain.SessionSummary{}, fmt.Errorf("no active session found for player id %d", clientID)
+	}
+	summary := *m.summaries[clientID]
+	summary.SessionEnd = time.Now()
+	summary.DurationSeconds = int64(summary.SessionEnd.Sub(summary.SessionStart).Seconds())
+	delete(m.activeSessions, clientID)
+	delete(m.summaries, clientID)
+	return summary, nil
+}
+
+func (m *MemoryRepository) ProcessPlay(t domain.PlayTransaction) (domain.PlaySettlement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	playerID := t.Message.ClientID
+	if t.FreeBetID != 0 {
+		return domain.PlaySettlement{}, appErrors.NewPromoError(fmt.Sprintf("free bet %d is no longer available", t.FreeBetID)).WithDetail(appErrors.DetailFreeBetUnavailable, t.FreeBetID)
+	}
+	balance, ok := m.balances[playerID]
+	if !ok {
+		return domain.PlaySettlement{}, appErrors.NewUserNotFoundError(fmt.Sprintf("No player found with ID: %d", playerID)).WithDetail(appErrors.DetailUserNotFound, playerID)
+	}
+
+	var award float64
+	pool := m.jackpotPool + t.JackpotContribution
+	if t.JackpotHit {
+		award = pool
+		pool = t.JackpotSeed
+	}
+
+	now := time.Now()
+	var bonus domain.PlayerBonus
+	var converted float64
+	if t.BonusID != 0 {
+		active, ok := m.bonuses[playerID]
+		if !ok || active.BonusID != t.BonusID {
+			return domain.PlaySettlement{}, appErrors.NewBonusError(fmt.Sprintf("bonus %d is no longer active", t.BonusID)).WithDetail(appErrors.DetailBonusInactive, t.BonusID)
+		}
+		bonus = *active
+		splitBonusPlay(&t, balance, bonus.Amount)
+		var err error
+		if converted, err = applyBonusPlay(&bonus, t, now); err != nil {
+			return domain.PlaySettlement{}, err
+		}
+	}
+
+	newBalance := balance + t.ChangeAmount + award + converted
+	if err := validateBalance(newBalance); err != nil {
+		return domain.PlaySettlement{}, err
+	}
+
+	session, ok := m.activeSessions[playerID]
+	if !ok {
+		session = domain.GameSession{
+			SessionID:    m.nextSessionID,
+			PlayerID:     playerID,
+			Active:       true,
+			SessionStart: now,
+		}
+		m.nextSessionID++
+		m.activeSessions[playerID] = session
+		m.summaries[playerID] = &domain.SessionSummary{SessionID: session.SessionID, SessionStart: now}
+	}
+	round := domain.Round{
+		RoundID:       m.nextRoundID,
+		SessionID:     session.SessionID,
+		PlayerID:      playerID,
+		BetAmount:     t.Message.BetAmount,
+		BetType:       t.Message.BetType,
+		BetNumber:     t.Message.BetNumber,
+		DiceResult:    t.DiceResult,
+		Won:           t.Won,
+		Payout:        t.Payout,
+		NetResult:     t.ChangeAmount + t.BonusChangeAmount + award,
+		BalanceChange: t.ChangeAmount + award + converted,
+		PlayedAt:      now,
+	}
+	m.nextRoundID++
+	summary := m.summaries[playerID]
+	summary.RoundsPlayed++
+	summary.TotalStaked += round.BetAmount
+	summary.TotalPayout += round.Payout
+	summary.NetResult += round.NetResult
+	m.recordStats(round)
+
+	settlement := domain.PlaySettlement{
+		Session:        session,
+		Round:          round,
+		Balance:        newBalance,
+		JackpotAward:   award,
+		JackpotPool:    pool,
+		BonusBalance:   bonus.Amount,
+		BonusConverted: converted,
+	}
+	msgType, data := t.SettlementMessage(settlement)
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.PlaySettlement{}, fmt.Errorf("failed to marshal %s settlement message: %w", msgType, err)
+	}
+
+	m.balances[playerID] = newBalance
+	m.jackpotPool = pool
+	if t.BonusID != 0 {
+		m.setBonus(bonus)
+	}
+	settlement.Seq = m.storeSettlementMessage(playerID, msgType, payload)
+	return settlement, nil
+}
+
+// ListRounds always returns an empty history, rounds are only totaled in memory
+func (m *MemoryRepository) ListRounds(playerID, limit int) ([]domain.Round, error) {
+	return []domain.Round{}, nil
+}
+
+func (m *MemoryRepository) GetJackpotPool() (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.jackpotPool, nil
+}
+
+func (m *MemoryRepository) GetActiveBonus(playerID int) (*domain.PlayerBonus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bonus, ok := m.bonuses[playerID]
+	if !ok || !time.Now().Before(bonus.ExpiresAt) {
+		return nil, nil
+	}
+	active := *bonus
+	return &active, nil
+}
+
+func (m *MemoryRepository) GrantBonus(bonus domain.PlayerBonus) (domain.PlayerBonus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.balances[bonus.PlayerID]; !ok {
+		return domain.PlayerBonus{}, appErrors.NewUserNotFoundError(fmt.Sprintf("No player found with ID: %d", bonus.PlayerID)).WithDetail(appErrors.DetailUserNotFound, bonus.PlayerID)
+	}
+	if _, ok := m.bonuses[bonus.PlayerID]; ok {
+		return domain.PlayerBonus{}, appErrors.NewBonusError(fmt.Sprintf("player %d already has an active bonus", bonus.PlayerID)).WithDetail(appErrors.DetailBonusActive, bonus.PlayerID)
+	}
+	bonus.BonusID = m.nextBonusID
+	bonus.Amount = bonus.GrantedAmount
+	bonus.Status = domain.BonusActive
+	bonus.GrantedAt = time.Now()
+	m.nextBonusID++
+	m.bonuses[bonus.PlayerID] = &bonus
+	return bonus, nil
+}
+
+func (m *MemoryRepository) ForfeitExpiredBonuses() (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var forfeited int64
+	now := time.Now()
+	for playerID, bonus := range m.bonuses {
+		if !now.Before(bonus.ExpiresAt) {
+			delete(m.bonuses, playerID)
+			forfeited++
+		}
+	}
+	return forfeited, nil
+}
+
+// setBonus keeps only active bonuses, like the active bonus index of the database
+func (m *MemoryRepository) setBonus(bonus domain.PlayerBonus) {
+	if bonus.Status != domain.BonusActive {
+		delete(m.bonuses, bonus.PlayerID)
+		return
+	}
+	m.bonuses[bonus.PlayerID] = &bonus
+}
+
+// CreatePromoCode always fails, promo codes are only kept in the database
+func (m *MemoryRepository) CreatePromoCode(promo domain.PromoCode) (domain.PromoCode, error) {
+	return domain.PromoCode{}, fmt.Errorf("promo code %s cannot be created, promo codes are only kept in the database", promo.Code)
+}
+
+// RedeemPromoCode always fails, promo codes are only kept in the database
+func (m *MemoryRepository) RedeemPromoCode(code string, playerID int) (domain.RedeemPromoResponse, error) {
+	return domain.RedeemPromoResponse{}, appErrors.NewPromoError(fmt.Sprintf("unknown promo code: %s", code)).WithDetail(appErrors.DetailPromoUnknown, code)
+}
+
+func (m *MemoryRepository) GetFreeBet(freeBetID, playerID int) (domain.FreeBet, error) {
+	return domain.FreeBet{}, appErrors.NewPromoError(fmt.Sprintf("unknown free bet: %d", freeBetID)).WithDetail(appErrors.DetailFreeBetUnknown, freeBetID)
+}
+
+func (m *MemoryRepository) ListFreeBets(playerID int) ([]domain.FreeBet, error) {
+	return []domain.FreeBet{}, nil
+}
+
+// VoidRound always fails, rounds are only totaled in memory and cannot be reversed
+func (m *MemoryRepository) VoidRound(void domain.RoundVoid) (domain.RoundVoid, error) {
+	return domain.RoundVoid{}, appErrors.NewVoidError(fmt.Sprintf("unknown round: %d", void.RoundID)).WithDetail(appErrors.DetailUnknownRound, void.RoundID)
+}
+
+// GetOpenGamble never finds a gamble, gambles are only kept in the database
+func (m *MemoryRepository) GetOpenGamble(playerID int) (*domain.Gamble, error) {
+	return nil, nil
+}
+
+func (m *MemoryRepository) StartGamble(roundID, playerID int) (domain.Gamble, error) {
+	return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("unknown round: %d", roundID)).WithDetail(appErrors.DetailUnknownRound, roundID)
+}
+
+func (m *MemoryRepository) SettleGamble(res domain.GambleResponse, prevStep int, msgType domain.MessageType) (domain.GambleResponse, error) {
+	return domain.GambleResponse{}, appErrors.NewGambleError(fmt.Sprintf("gamble %d was already settled", res.Gamble.GambleID)).WithDetail(appErrors.DetailGambleSettled, res.Gamble.GambleID)
+}
+
+// recordStats keeps the totals GetPlayerStats would compute from the stored rounds
+func (m *MemoryRepository) recordStats(round domain.Round) {
+	stats, ok := m.stats[round.PlayerID]
+	if !ok {
+		stats = &domain.PlayerStats{}
+		m.stats[round.PlayerID] = stats
+	}
+	stats.RoundsPlayed++
+	if !round.Won {
+		stats.WinStreak = 0
+		return
+	}
+	stats.WinStreak++
+	if round.BetType == domain.Exact {
+		stats.ExactHits++
+	}
+}
+
+func (m *MemoryRepository) GetPlayerStats(playerID int) (domain.PlayerStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stats, ok := m.stats[playerID]; ok {
+		return *stats, nil
+	}
+	return domain.PlayerStats{}, nil
+}
+
+func (m *MemoryRepository) GetUnlockedAchievements(playerID int) (map[domain.AchievementID]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	unlocked := make(map[domain.AchievementID]time.Time, len(m.achievements[playerID]))
+	for id, unlockedAt := range m.achievements[playerID] {
+		unlocked[id] = unlockedAt
+	}
+	return unlocked, nil
+}
+
+func (m *MemoryRepository) UnlockAchievement(playerID int, achievementID domain.AchievementID) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.achievements[playerID] == nil {
+		m.achievements[playerID] = make(map[domain.AchievementID]time.Time)
+	}
+	if _, ok := m.achievements[playerID][achievementID]; ok {
+		return time.Time{}, false, nil
+	}
+	unlockedAt := time.Now()
+	m.achievements[playerID][achievementID] = unlockedAt
+	return unlockedAt, true, nil
+}
+
+// storeSettlementMessage appends a message under the next sequence number of the player, keeping at most
+// maxMemoryMessages of them. Callers must hold the lock
+func (m *MemoryRepository) storeSettlementMessage(playerID int, msgType domain.MessageType, payload []byte) int64 {
+	m.messageSeqs[playerID]++
+	message := domain.SettlementMessage{PlayerID: playerID, Seq: m.messageSeqs[playerID], Type: msgType, Payload: payload, CreatedAt: time.Now()}
+	messages := append(m.messages[playerID], message)
+	if len(messages) > maxMemoryMessages {
+		messages = append([]domain.SettlementMessage(nil), messages[len(messages)-maxMemoryMessages:]...)
+	}
+	m.messages[playerID] = messages
+	return message.Seq
+}
+
+func (m *MemoryRepository) AckSettlementMessages(playerID int, seq int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pending := m.messages[playerID][:0]
+	for _, message := range m.messages[playerID] {
+		if message.Seq > seq {
+			pending = append(pending, message)
+		}
+	}
+	m.messages[playerID] = pending
+	return nil
+}
+
+func (m *MemoryRepository) ListUnackedMessages(playerID int) ([]domain.SettlementMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]domain.SettlementMessage(nil), m.messages[playerID]...), nil
+}
+
+func (m *MemoryRepository) PurgeSettlementMessages(before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var purged int64
+	for playerID, messages := range m.messages {
+		kept := messages[:0]
+		for _, message := range messages {
+			if message.CreatedAt.Before(before) {
+				purged++
+				continue
+			}
+			kept = append(kept, message)
+		}
+		m.messages[playerID] = kept
+	}
+	return purged, nil
+}
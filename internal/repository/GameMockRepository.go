@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/Desgue/SpicyDice/internal/domain"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,6 +16,11 @@ func (m *MockRepository) GetBalance(playerID int) (float64, error) {
 	return args.Get(0).(float64), args.Error(1)
 }
 
+func (m *MockRepository) GetTierLimits(playerID int) (domain.TierLimits, error) {
+	args := m.Called(playerID)
+	return args.Get(0).(domain.TierLimits), args.Error(1)
+}
+
 func (m *MockRepository) GetActiveSession(playerID int) (*domain.GameSession, error) {
 	args := m.Called(playerID)
 	if session, ok := args.Get(0).(*domain.GameSession); ok {
@@ -22,12 +29,212 @@ func (m *MockRepository) GetActiveSession(playerID int) (*domain.GameSession, er
 	return nil, args.Error(1)
 }
 
-func (m *MockRepository) CloseCurrentGameSession(clientID int) error {
+func (m *MockRepository) CloseCurrentGameSession(clientID int) (domain.SessionSummary, error) {
 	args := m.Called(clientID)
-	return args.Error(0)
+	return args.Get(0).(domain.SessionSummary), args.Error(1)
 }
 
-func (m *MockRepository) ProcessPlay(transaction domain.PlayTransaction) (domain.GameSession, float64, error) {
+func (m *MockRepository) ListRounds(playerID, limit int) ([]domain.Round, error) {
+	args := m.Called(playerID, limit)
+	if rounds, ok := args.Get(0).([]domain.Round); ok {
+		return rounds, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) ProcessPlay(transaction domain.PlayTransaction) (domain.PlaySettlement, error) {
 	args := m.Called(transaction)
-	return args.Get(0).(domain.GameSession), args.Get(1).(float64), args.Error(2)
+	return args.Get(0).(domain.PlaySettlement), args.Error(1)
+}
+
+func (m *MockRepository) GetJackpotPool() (float64, error) {
+	args := m.Called()
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockRepository) CreateTableRound(tableID int, closesAt time.Time) (domain.TableRound, error) {
+	args := m.Called(tableID, closesAt)
+	return args.Get(0).(domain.TableRound), args.Error(1)
+}
+
+func (m *MockRepository) UpdateTableRound(round domain.TableRound) error {
+	args := m.Called(round)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListUnsettledTableRounds() ([]domain.TableRound, error) {
+	args := m.Called()
+	if rounds, ok := args.Get(0).([]domain.TableRound); ok {
+		return rounds, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) PlaceTableBet(bet domain.TableBet) (domain.TableBet, float64, error) {
+	args := m.Called(bet)
+	return args.Get(0).(domain.TableBet), args.Get(1).(float64), args.Error(2)
+}
+
+func (m *MockRepository) SettleTableRound(round domain.TableRound, results []domain.TableBetResult) (domain.TableSettlementResponse, error) {
+	args := m.Called(round, results)
+	return args.Get(0).(domain.TableSettlementResponse), args.Error(1)
+}
+
+func (m *MockRepository) VoidTableRound(roundID int) error {
+	args := m.Called(roundID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateTournament(tournament domain.Tournament) (domain.Tournament, error) {
+	args := m.Called(tournament)
+	return args.Get(0).(domain.Tournament), args.Error(1)
+}
+
+func (m *MockRepository) ListTournaments() ([]domain.Tournament, error) {
+	args := m.Called()
+	if tournaments, ok := args.Get(0).([]domain.Tournament); ok {
+		return tournaments, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetTournament(tournamentID int) (domain.Tournament, error) {
+	args := m.Called(tournamentID)
+	return args.Get(0).(domain.Tournament), args.Error(1)
+}
+
+func (m *MockRepository) RegisterTournamentEntry(tournament domain.Tournament, playerID int) (domain.TournamentEntry, float64, error) {
+	args := m.Called(tournament, playerID)
+	return args.Get(0).(domain.TournamentEntry), args.Get(1).(float64), args.Error(2)
+}
+
+func (m *MockRepository) GetTournamentEntry(tournamentID, playerID int) (domain.TournamentEntry, error) {
+	args := m.Called(tournamentID, playerID)
+	return args.Get(0).(domain.TournamentEntry), args.Error(1)
+}
+
+func (m *MockRepository) GetTournamentEntries(tournamentID int) ([]domain.TournamentEntry, error) {
+	args := m.Called(tournamentID)
+	if entries, ok := args.Get(0).([]domain.TournamentEntry); ok {
+		return entries, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) UpdateTournamentStack(playerID int, changeAmount float64, res domain.TournamentPlayResponse) (domain.TournamentPlayResponse, error) {
+	args := m.Called(playerID, changeAmount, res)
+	return args.Get(0).(domain.TournamentPlayResponse), args.Error(1)
+}
+
+func (m *MockRepository) DueTournaments() ([]domain.Tournament, error) {
+	args := m.Called()
+	if tournaments, ok := args.Get(0).([]domain.Tournament); ok {
+		return tournaments, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) CloseTournament(tournamentID int, prizes []domain.TournamentPrize, leftover float64) (bool, error) {
+	args := m.Called(tournamentID, prizes, leftover)
+	return args.Get(0).(bool), args.Error(1)
+}
+
+func (m *MockRepository) GetActiveBonus(playerID int) (*domain.PlayerBonus, error) {
+	args := m.Called(playerID)
+	if bonus, ok := args.Get(0).(*domain.PlayerBonus); ok {
+		return bonus, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GrantBonus(bonus domain.PlayerBonus) (domain.PlayerBonus, error) {
+	args := m.Called(bonus)
+	return args.Get(0).(domain.PlayerBonus), args.Error(1)
+}
+
+func (m *MockRepository) ForfeitExpiredBonuses() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) CreatePromoCode(promo domain.PromoCode) (domain.PromoCode, error) {
+	args := m.Called(promo)
+	return args.Get(0).(domain.PromoCode), args.Error(1)
+}
+
+func (m *MockRepository) RedeemPromoCode(code string, playerID int) (domain.RedeemPromoResponse, error) {
+	args := m.Called(code, playerID)
+	return args.Get(0).(domain.RedeemPromoResponse), args.Error(1)
+}
+
+func (m *MockRepository) GetFreeBet(freeBetID, playerID int) (domain.FreeBet, error) {
+	args := m.Called(freeBetID, playerID)
+	return args.Get(0).(domain.FreeBet), args.Error(1)
+}
+
+func (m *MockRepository) ListFreeBets(playerID int) ([]domain.FreeBet, error) {
+	args := m.Called(playerID)
+	if freeBets, ok := args.Get(0).([]domain.FreeBet); ok {
+		return freeBets, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) VoidRound(void domain.RoundVoid) (domain.RoundVoid, error) {
+	args := m.Called(void)
+	return args.Get(0).(domain.RoundVoid), args.Error(1)
+}
+
+func (m *MockRepository) GetOpenGamble(playerID int) (*domain.Gamble, error) {
+	args := m.Called(playerID)
+	if gamble, ok := args.Get(0).(*domain.Gamble); ok {
+		return gamble, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) StartGamble(roundID, playerID int) (domain.Gamble, error) {
+	args := m.Called(roundID, playerID)
+	return args.Get(0).(domain.Gamble), args.Error(1)
+}
+
+func (m *MockRepository) SettleGamble(res domain.GambleResponse, prevStep int, msgType domain.MessageType) (domain.GambleResponse, error) {
+	args := m.Called(res, prevStep, msgType)
+	return args.Get(0).(domain.GambleResponse), args.Error(1)
+}
+
+func (m *MockRepository) GetPlayerStats(playerID int) (domain.PlayerStats, error) {
+	args := m.Called(playerID)
+	return args.Get(0).(domain.PlayerStats), args.Error(1)
+}
+
+func (m *MockRepository) GetUnlockedAchievements(playerID int) (map[domain.AchievementID]time.Time, error) {
+	args := m.Called(playerID)
+	if unlocked, ok := args.Get(0).(map[domain.AchievementID]time.Time); ok {
+		return unlocked, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) UnlockAchievement(playerID int, achievementID domain.AchievementID) (time.Time, bool, error) {
+	args := m.Called(playerID, achievementID)
+	return args.Get(0).(time.Time), args.Bool(1), args.Error(2)
+}
+
+func (m *MockRepository) AckSettlementMessages(playerID int, seq int64) error {
+	args := m.Called(playerID, seq)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListUnackedMessages(playerID int) ([]domain.SettlementMessage, error) {
+	args := m.Called(playerID)
+	if messages, ok := args.Get(0).([]domain.SettlementMessage); ok {
+		return messages, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) PurgeSettlementMessages(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
 }
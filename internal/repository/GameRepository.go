@@ -12,10 +12,23 @@ import (
 
 type Repository interface {
 	GetBalance(playerID int) (float64, error)
+	GetTierLimits(playerID int) (domain.TierLimits, error)
 	GetActiveSession(playerID int) (*domain.GameSession, error)
-	CloseCurrentGameSession(clientID int) error
-	ProcessPlay(t domain.PlayTransaction) (domain.GameSession, float64, error)
+	CloseCurrentGameSession(clientID int) (domain.SessionSummary, error)
+	ProcessPlay(t domain.PlayTransaction) (domain.PlaySettlement, error)
+	ListRounds(playerID, limit int) ([]domain.Round, error)
+	GetJackpotPool() (float64, error)
+	BonusRepository
+	PromoRepository
+	VoidRepository
+	GambleRepository
+	AchievementRepository
+	DeliveryRepository
 }
+
+// jackpotPoolID identifies the single shared jackpot pool row
+const jackpotPoolID = 1
+
 type GameRepository struct {
 	db *sql.DB
 }
@@ -31,13 +44,36 @@ func (gr *GameRepository) GetBalance(playerID int) (float64, error) {
 	query := `SELECT balance FROM player WHERE id = $1`
 	if err := gr.db.QueryRow(query, playerID).Scan(&balance); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return 0, appErrors.NewUserNotFoundError(fmt.Sprintf("No player found with ID: %d", playerID))
+			return 0, appErrors.NewUserNotFoundError(fmt.Sprintf("No player found with ID: %d", playerID)).WithDetail(appErrors.DetailUserNotFound, playerID)
 		}
 		return 0, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
 	}
 	return balance, nil
 }
 
+func (gr *GameRepository) GetTierLimits(playerID int) (domain.TierLimits, error) {
+	var limits domain.TierLimits
+	var minBet, maxBet sql.NullFloat64
+	query := `
+		SELECT p.tier, t.min_bet, t.max_bet FROM player p
+		JOIN bet_tier t ON t.name = p.tier
+		WHERE p.id = $1
+	;`
+	if err := gr.db.QueryRow(query, playerID).Scan(&limits.Tier, &minBet, &maxBet); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.TierLimits{}, appErrors.NewUserNotFoundError(fmt.Sprintf("No player found with ID: %d", playerID)).WithDetail(appErrors.DetailUserNotFound, playerID)
+		}
+		return domain.TierLimits{}, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	if minBet.Valid {
+		limits.MinBetAmount = &minBet.Float64
+	}
+	if maxBet.Valid {
+		limits.MaxBetAmount = &maxBet.Float64
+	}
+	return limits, nil
+}
+
 func (gr *GameRepository) GetActiveSession(playerID int) (*domain.GameSession, error) {
 	tx, err := gr.db.Begin()
 	if err != nil {
@@ -57,7 +93,7 @@ func (gr *GameRepository) GetActiveSession(playerID int) (*domain.GameSession, e
 func (gr *GameRepository) getActiveSession(tx *sql.Tx, playerID int) (*domain.GameSession, error) {
 	var session domain.GameSession
 	query := `
-		SELECT session_id, player_id, bet_amount, dice_result, won, active, session_start, session_end FROM game_session 
+		SELECT session_id, player_id, active, session_start, session_end FROM game_session 
 		WHERE player_id = $1
 		AND active = true
 	;`
@@ -69,9 +105,6 @@ func (gr *GameRepository) getActiveSession(tx *sql.Tx, playerID int) (*domain.Ga
 		Scan(
 			&session.SessionID,
 			&session.PlayerID,
-			&session.BetAmount,
-			&session.DiceResult,
-			&session.Won,
 			&session.Active,
 			&session.SessionStart,
 			&session.SessionEnd,
@@ -86,91 +119,248 @@ func (gr *GameRepository) getActiveSession(tx *sql.Tx, playerID int) (*domain.Ga
 	return &session, nil
 }
 
-func (gr *GameRepository) CloseCurrentGameSession(clientID int) error {
-	query := `
+// CloseCurrentGameSession closes the active session of a player and totals its rounds
+// The net result also includes what the player won or lost gambling the winnings of those rounds
+func (gr *GameRepository) CloseCurrentGameSession(clientID int) (domain.SessionSummary, error) {
+	var summary domain.SessionSummary
+
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.SessionSummary{}, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	closeQuery := `
 		UPDATE game_session
 		SET active = false, session_end = NOW()
 		WHERE player_id = $1 AND active = true
+		RETURNING session_id, session_start, session_end
 		;`
-
-	result, err := gr.db.Exec(query, clientID)
+	err = tx.QueryRow(closeQuery, clientID).Scan(&summary.SessionID, &summary.SessionStart, &summary.SessionEnd)
 	if err != nil {
-		return fmt.Errorf("error updating game session: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.SessionSummary{}, fmt.Errorf("no active session found for player id %d", clientID)
+		}
+		return domain.SessionSummary{}, fmt.Errorf("error updating game session: %v", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	totalsQuery := `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(bet_amount) FILTER (WHERE free_bet_id IS NULL), 0),
+			COALESCE(SUM(payout), 0),
+			COALESCE(SUM(net_result), 0)
+		FROM round
+		WHERE session_id = $1 AND NOT voided
+		;`
+	err = tx.QueryRow(totalsQuery, summary.SessionID).Scan(&summary.RoundsPlayed, &summary.TotalStaked, &summary.TotalPayout, &summary.NetResult)
 	if err != nil {
-		return fmt.Errorf("error checking affected rows: %v", err)
+		return domain.SessionSummary{}, fmt.Errorf("error totaling rounds of session id %d: %v", summary.SessionID, err)
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("no active session found for player id %d", clientID)
+	var gambleResult float64
+	gambleQuery := `
+		SELECT COALESCE(SUM(g.amount - g.stake), 0) FROM gamble g
+		JOIN round r ON r.round_id = g.round_id
+		WHERE g.session_id = $1 AND g.status <> $2 AND NOT r.voided
+		;`
+	if err := tx.QueryRow(gambleQuery, summary.SessionID, domain.GambleOpen).Scan(&gambleResult); err != nil {
+		return domain.SessionSummary{}, fmt.Errorf("error totaling gambles of session id %d: %v", summary.SessionID, err)
 	}
+	summary.NetResult += gambleResult
+	summary.DurationSeconds = int64(summary.SessionEnd.Sub(summary.SessionStart).Seconds())
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return domain.SessionSummary{}, fmt.Errorf("failed to commit close session transaction: %w", err)
+	}
+	return summary, nil
 }
 
-func (gr *GameRepository) ProcessPlay(t domain.PlayTransaction) (domain.GameSession, float64, error) {
-	var session domain.GameSession
-	var multiplier = 2.0
-	var changeAmount float64
+// ProcessPlay appends a round to the active session of the player, opening one when there is none,
+// and settles the balance, bonus, free bet and jackpot updates of the round in a single transaction
+// The settlement message reporting the round to the player is stored in the same transaction
+func (gr *GameRepository) ProcessPlay(t domain.PlayTransaction) (domain.PlaySettlement, error) {
+	var settlement domain.PlaySettlement
 
 	tx, err := gr.db.Begin()
 	if err != nil {
-		return domain.GameSession{}, 0, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+		return domain.PlaySettlement{}, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
 	}
 	defer tx.Rollback()
 
-	activeSession, err := gr.getActiveSession(tx, t.Message.ClientID)
+	settlement.Session, err = gr.openGameSession(tx, t.Message.ClientID)
 	if err != nil {
-		return domain.GameSession{}, 0, appErrors.NewInternalError(err.Error())
+		return domain.PlaySettlement{}, err
 	}
-	if activeSession != nil {
-		return *activeSession, 0, appErrors.NewActiveSessionError("Player already has an active session")
+
+	if t.JackpotContribution > 0 || t.JackpotHit {
+		settlement.JackpotAward, settlement.JackpotPool, err = gr.updateJackpot(tx, settlement.Session, t)
+		if err != nil {
+			return domain.PlaySettlement{}, err
+		}
 	}
 
-	session, err = gr.createGameSession(tx, domain.GameSessionRequest{
-		PlayerID:     t.Message.ClientID,
-		BetAmount:    t.Message.BetAmount,
-		DiceResult:   t.DiceResult,
-		Won:          t.Won,
-		Active:       true,
-		SessionStart: time.Now(),
+	playedAt := time.Now()
+	if t.BonusID != 0 {
+		// The cash balance is locked before the bonus, the order VoidRound takes both locks in
+		cash, err := gr.lockBalance(tx, t.Message.ClientID)
+		if err != nil {
+			return domain.PlaySettlement{}, err
+		}
+		settlement.BonusBalance, settlement.BonusConverted, err = gr.updateBonus(tx, &t, cash, playedAt)
+		if err != nil {
+			return domain.PlaySettlement{}, err
+		}
+	}
+
+	settlement.Round, err = gr.createRound(tx, domain.Round{
+		SessionID:     settlement.Session.SessionID,
+		PlayerID:      t.Message.ClientID,
+		BetAmount:     t.Message.BetAmount,
+		BetType:       t.Message.BetType,
+		BetNumber:     t.Message.BetNumber,
+		DiceResult:    t.DiceResult,
+		Won:           t.Won,
+		Payout:        t.Payout,
+		NetResult:     t.ChangeAmount + t.BonusChangeAmount + settlement.JackpotAward,
+		BalanceChange: t.ChangeAmount + settlement.JackpotAward + settlement.BonusConverted,
+		FreeBetID:     t.FreeBetID,
+		PlayedAt:      playedAt,
+
+		JackpotContribution: t.JackpotContribution,
+		JackpotAward:        settlement.JackpotAward,
+		BonusID:             t.BonusID,
+		BonusChange:         t.BonusChangeAmount,
+		BonusConverted:      settlement.BonusConverted,
 	})
 	if err != nil {
-		return domain.GameSession{}, 0, err
+		return domain.PlaySettlement{}, err
 	}
 
-	if t.Won {
-		changeAmount = (t.Message.BetAmount * multiplier) - t.Message.BetAmount
-	} else {
-		changeAmount = -t.Message.BetAmount
+	if t.FreeBetID != 0 {
+		if err := gr.useFreeBet(tx, settlement.Round, t.FreeBetID); err != nil {
+			return domain.PlaySettlement{}, err
+		}
 	}
 
-	newBalance, err := gr.updateBalance(tx, domain.BalanceUpdate{
+	settlement.Balance, err = gr.updateBalance(tx, domain.BalanceUpdate{
 		PlayerID:     t.Message.ClientID,
-		ChangeAmount: changeAmount,
+		ChangeAmount: settlement.Round.BalanceChange,
 	})
 	if err != nil {
-		return domain.GameSession{}, 0, err
+		return domain.PlaySettlement{}, err
 	}
 
+	msgType, data := t.SettlementMessage(settlement)
+	message, err := gr.storeSettlementMessage(tx, t.Message.ClientID, msgType, data)
+	if err != nil {
+		return domain.PlaySettlement{}, err
+	}
+	settlement.Seq = message.Seq
+
 	if err = tx.Commit(); err != nil {
-		return domain.GameSession{}, 0, fmt.Errorf("failed to commit play transaction: %w", err)
+		return domain.PlaySettlement{}, fmt.Errorf("failed to commit play transaction: %w", err)
 	}
 
-	return session, newBalance, nil
+	return settlement, nil
 }
 
-func (gr *GameRepository) updateBalance(tx *sql.Tx, update domain.BalanceUpdate) (float64, error) {
-	var currBalance float64
-	balanceLockQuery := `
-		SELECT balance FROM player
+// ListRounds returns the latest rounds of a player across all of their sessions, newest first
+func (gr *GameRepository) ListRounds(playerID, limit int) ([]domain.Round, error) {
+	query := `
+		SELECT round_id, session_id, player_id, bet_amount, bet_type, COALESCE(bet_number, 0), dice_result, won,
+			payout, net_result, balance_change, COALESCE(free_bet_id, 0), voided, played_at
+		FROM round
+		WHERE player_id = $1
+		ORDER BY round_id DESC
+		LIMIT $2
+	;`
+	rows, err := gr.db.Query(query, playerID, limit)
+	if err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	defer rows.Close()
+
+	rounds := []domain.Round{}
+	for rows.Next() {
+		var round domain.Round
+		err := rows.Scan(
+			&round.RoundID,
+			&round.SessionID,
+			&round.PlayerID,
+			&round.BetAmount,
+			&round.BetType,
+			&round.BetNumber,
+			&round.DiceResult,
+			&round.Won,
+			&round.Payout,
+			&round.NetResult,
+			&round.BalanceChange,
+			&round.FreeBetID,
+			&round.Voided,
+			&round.PlayedAt,
+		)
+		if err != nil {
+			return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+		}
+		rounds = append(rounds, round)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return rounds, nil
+}
+
+func (gr *GameRepository) GetJackpotPool() (float64, error) {
+	var amount float64
+	query := `SELECT amount FROM jackpot_pool WHERE id = $1`
+	if err := gr.db.QueryRow(query, jackpotPoolID).Scan(&amount); err != nil {
+		return 0, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return amount, nil
+}
+
+// updateJackpot adds the stake contribution to the shared pool and, on a hit, awards the whole pool
+// to the player and resets it to the seed amount. Runs inside the play transaction
+func (gr *GameRepository) updateJackpot(tx *sql.Tx, session domain.GameSession, t domain.PlayTransaction) (float64, float64, error) {
+	var pool, award float64
+	lockQuery := `
+		SELECT amount FROM jackpot_pool
 		WHERE id = $1
 		FOR UPDATE
 		;`
-	if err := tx.QueryRow(balanceLockQuery, update.PlayerID).Scan(&currBalance); err != nil {
-		return 0.0, fmt.Errorf("error locking row: %w", err)
+	if err := tx.QueryRow(lockQuery, jackpotPoolID).Scan(&pool); err != nil {
+		return 0, 0, fmt.Errorf("error locking jackpot pool: %w", err)
+	}
+
+	pool += t.JackpotContribution
+	if t.JackpotHit {
+		award = pool
+		pool = t.JackpotSeed
+
+		awardQuery := `
+			INSERT INTO jackpot_award (player_id, session_id, amount)
+			VALUES ($1, $2, $3)
+		;`
+		if _, err := tx.Exec(awardQuery, session.PlayerID, session.SessionID, award); err != nil {
+			return 0, 0, fmt.Errorf("failed to record jackpot award: %w", err)
+		}
+	}
+
+	updateQuery := `
+		UPDATE jackpot_pool
+		SET amount = $1, updated_at = NOW()
+		WHERE id = $2
+	;`
+	if _, err := tx.Exec(updateQuery, pool, jackpotPoolID); err != nil {
+		return 0, 0, fmt.Errorf("failed to update jackpot pool: %w", err)
+	}
+	return award, pool, nil
+}
+
+func (gr *GameRepository) updateBalance(tx *sql.Tx, update domain.BalanceUpdate) (float64, error) {
+	currBalance, err := gr.lockBalance(tx, update.PlayerID)
+	if err != nil {
+		return 0.0, err
 	}
 
 	newBalance := currBalance + update.ChangeAmount
@@ -201,40 +391,96 @@ func (gr *GameRepository) updateBalance(tx *sql.Tx, update domain.BalanceUpdate)
 
 }
 
-func (gr *GameRepository) createGameSession(tx *sql.Tx, sess domain.GameSessionRequest) (domain.GameSession, error) {
+// lockBalance returns the cash balance of the player, locking it until the transaction ends
+func (gr *GameRepository) lockBalance(tx *sql.Tx, playerID int) (float64, error) {
+	var balance float64
+	balanceLockQuery := `
+		SELECT balance FROM player
+		WHERE id = $1
+		FOR UPDATE
+		;`
+	if err := tx.QueryRow(balanceLockQuery, playerID).Scan(&balance); err != nil {
+		return 0.0, fmt.Errorf("error locking row: %w", err)
+	}
+	return balance, nil
+}
+
+// openGameSession returns the locked active session of the player, opening a new one when there is none
+func (gr *GameRepository) openGameSession(tx *sql.Tx, playerID int) (domain.GameSession, error) {
 	var session domain.GameSession
 	query := `
-		INSERT INTO game_session (player_id, bet_amount, dice_result, won, active, session_start)
-		VALUES
-		($1, $2, $3, $4, $5, $6)
-		RETURNING session_id, player_id, bet_amount, dice_result, won, active, session_start, session_end
+		INSERT INTO game_session (player_id, active, session_start)
+		VALUES ($1, true, NOW())
+		ON CONFLICT (player_id) WHERE active = true DO NOTHING
+		RETURNING session_id, player_id, active, session_start, session_end
 	;`
 
-	err := tx.QueryRow(
-		query,
-		sess.PlayerID,
-		sess.BetAmount,
-		sess.DiceResult,
-		sess.Won,
-		sess.Active,
-		sess.SessionStart,
-	).
+	err := tx.QueryRow(query, playerID).
 		Scan(
 			&session.SessionID,
 			&session.PlayerID,
-			&session.BetAmount,
-			&session.DiceResult,
-			&session.Won,
 			&session.Active,
 			&session.SessionStart,
 			&session.SessionEnd,
 		)
-	if err != nil {
-		return domain.GameSession{}, fmt.Errorf("error creating game session for player id %d", sess.PlayerID)
+	if err == nil {
+		return session, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return domain.GameSession{}, fmt.Errorf("error opening game session for player id %d: %w", playerID, err)
 	}
 
+	lockQuery := `
+		SELECT session_id, player_id, active, session_start, session_end FROM game_session
+		WHERE player_id = $1 AND active = true
+		FOR UPDATE
+	;`
+	err = tx.QueryRow(lockQuery, playerID).
+		Scan(
+			&session.SessionID,
+			&session.PlayerID,
+			&session.Active,
+			&session.SessionStart,
+			&session.SessionEnd,
+		)
+	if err != nil {
+		return domain.GameSession{}, fmt.Errorf("error locking active session for player id %d: %w", playerID, err)
+	}
 	return session, nil
+}
 
+// createRound records the bet and roll of a round in its session
+func (gr *GameRepository) createRound(tx *sql.Tx, round domain.Round) (domain.Round, error) {
+	query := `
+		INSERT INTO round (session_id, player_id, bet_amount, bet_type, bet_number, dice_result, won, payout, net_result, balance_change, free_bet_id, played_at,
+			jackpot_contribution, jackpot_award, bonus_id, bonus_change, bonus_converted)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, $7, $8, $9, $10, NULLIF($11, 0), $12, $13, $14, NULLIF($15, 0), $16, $17)
+		RETURNING round_id
+	;`
+	err := tx.QueryRow(
+		query,
+		round.SessionID,
+		round.PlayerID,
+		round.BetAmount,
+		round.BetType,
+		round.BetNumber,
+		round.DiceResult,
+		round.Won,
+		round.Payout,
+		round.NetResult,
+		round.BalanceChange,
+		round.FreeBetID,
+		round.PlayedAt,
+		round.JackpotContribution,
+		round.JackpotAward,
+		round.BonusID,
+		round.BonusChange,
+		round.BonusConverted,
+	).Scan(&round.RoundID)
+	if err != nil {
+		return domain.Round{}, fmt.Errorf("error creating round for session id %d: %w", round.SessionID, err)
+	}
+	return round, nil
 }
 
 func validateBalance(balance float64) error {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/Desgue/SpicyDice/internal/appErrors"
 	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/postgres/migrations"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
@@ -37,42 +38,10 @@ func NewTestConfig(ctx context.Context, t *testing.T, db *sql.DB) *testDBConfig
 	return cfg
 }
 
-// createTables makes our test database schema with indexes
-// We need this to guarantee db consistency during the tests
+// createTables migrates the test database with the same migrations as production
+// so the schema under test never drifts from the real one
 func (cfg *testDBConfig) createTables(tx *sql.Tx) error {
-	playerTable := `
-	CREATE TABLE IF NOT EXISTS player (
-		id SERIAL PRIMARY KEY,
-		balance decimal(10,2)
-	  );`
-	gameSessionTable := `
-	  CREATE TABLE IF NOT EXISTS game_session (
-		session_id  SERIAL PRIMARY KEY,
-		player_id int,
-		bet_amount decimal(10,2),
-		dice_result int,
-		won boolean,
-		active boolean,
-		session_start timestamptz,
-		session_end timestamptz DEFAULT NULL,
-		FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
-	  );`
-	createUniqueIndex := `
-	  CREATE UNIQUE INDEX unique_active_player_session ON game_session (player_id)
-	  WHERE active = true;
-	   
-	  `
-
-	if _, err := tx.ExecContext(cfg.ctx, playerTable); err != nil {
-		return err
-	}
-	if _, err := tx.ExecContext(cfg.ctx, gameSessionTable); err != nil {
-		return err
-	}
-	if _, err := tx.ExecContext(cfg.ctx, createUniqueIndex); err != nil {
-		return err
-	}
-	return nil
+	return Migrate(cfg.db, migrations.Files)
 }
 
 // WithPlayer adds a new player to the operations queue
@@ -92,21 +61,18 @@ func (cfg *testDBConfig) WithPlayer(id int, balance float64) *testDBConfig {
 }
 
 // WithActiveSession adds a game session to the operations queue
-// Good to test cases where player already has active session
+// Good to test cases where rounds are appended to an open session
 func (cfg *testDBConfig) WithActiveSession(session domain.GameSession) *testDBConfig {
 	insertSession := func(tx *sql.Tx) error {
 		query := `
-		INSERT INTO game_session (session_id, player_id, bet_amount, dice_result, won, active, session_start)
+		INSERT INTO game_session (session_id, player_id, active, session_start)
 		VALUES
-		($1, $2, $3, $4, $5, $6, $7);`
+		($1, $2, $3, $4);`
 		if _, err := tx.ExecContext(
 			cfg.ctx,
 			query,
 			session.SessionID,
 			session.PlayerID,
-			session.BetAmount,
-			session.DiceResult,
-			session.Won,
 			session.Active,
 			session.SessionStart); err != nil {
 
@@ -166,10 +132,9 @@ func (cfg *testDBConfig) Cleanup() error {
 	return nil
 }
 
-// TestProcessPlay checks if game rules are working right in database
-// Here we use docker to create a postgres container to ensure a clean state every test
-func TestProcessPlay(t *testing.T) {
-	ctx := context.Background()
+// startTestDatabase runs a postgres container for the test and returns a connection to it
+// The container is terminated once the test ends
+func startTestDatabase(ctx context.Context, t *testing.T) *sql.DB {
 	dbName := "postgres"
 	dbUser := "postgres"
 	dbPassword := "postgres"
@@ -187,50 +152,53 @@ func TestProcessPlay(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to start container: %s", err)
 	}
-
-	defer func() {
+	t.Cleanup(func() {
 		if err := postgresContainer.Terminate(ctx); err != nil {
 			t.Fatalf("failed to terminate container: %s", err)
 		}
-	}()
+	})
 
 	connStr, _ := postgresContainer.ConnectionString(ctx, "sslmode=disable")
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		t.Fatalf("error open database: %s", err.Error())
 	}
-	defer db.Close()
+	t.Cleanup(func() { db.Close() })
 	if err := db.Ping(); err != nil {
 		t.Fatalf("could not reach database: %s", err)
 	}
+	return db
+}
+
+// TestProcessPlay checks if game rules are working right in database
+// Here we use docker to create a postgres container to ensure a clean state every test
+func TestProcessPlay(t *testing.T) {
+	ctx := context.Background()
+	db := startTestDatabase(ctx, t)
 	testDB := NewTestConfig(ctx, t, db)
 	repo := NewGameRepository(db)
 
 	testCases := []struct {
-		name                    string
-		expectError             bool
-		expectedErrorCode       int
-		expectedBalance         float64
-		expectedSessionResponse bool
-		setupFunc               func(cfg *testDBConfig) *testDBConfig
-		transaction             domain.PlayTransaction
+		name              string
+		expectError       bool
+		expectedErrorCode int
+		expectedBalance   float64
+		expectedSessionID int
+		setupFunc         func(cfg *testDBConfig) *testDBConfig
+		transaction       domain.PlayTransaction
 	}{
-		// Validates that a player cannot start a new game session while having an active one
+		// Validates that a round is appended to the session the player already has open
 		{
-			name:                    "active_session_exists",
-			expectError:             true,
-			expectedErrorCode:       appErrors.ActiveSessionErrorCode,
-			expectedBalance:         0.0,
-			expectedSessionResponse: true,
+			name:              "active_session_exists",
+			expectError:       false,
+			expectedBalance:   1100,
+			expectedSessionID: 7,
 			setupFunc: func(cfg *testDBConfig) *testDBConfig {
 				// Simulates an existing active session for the player
-				playerId, sessionId := 1, 1
+				playerId, sessionId := 1, 7
 				config := cfg.WithPlayer(playerId, 1000).WithActiveSession(domain.GameSession{
 					SessionID:    sessionId,
 					PlayerID:     playerId,
-					BetAmount:    100,
-					DiceResult:   1,
-					Won:          true,
 					Active:       true,
 					SessionStart: time.Now(),
 				})
@@ -243,34 +211,61 @@ func TestProcessPlay(t *testing.T) {
 					BetAmount: 100,
 					BetType:   domain.Odd,
 				},
-				DiceResult: 1,
-				Won:        true,
+				DiceResult:   1,
+				Won:          true,
+				ChangeAmount: 100,
+				Payout:       200,
+			},
+		},
+		// Validates that the first round of a player opens a new session
+		{
+			name:            "no_active_session",
+			expectError:     false,
+			expectedBalance: 900,
+			setupFunc: func(cfg *testDBConfig) *testDBConfig {
+				return cfg.WithPlayer(1, 1000)
+			},
+			transaction: domain.PlayTransaction{
+				Message: domain.PlayRequest{
+					ClientID:  1,
+					BetAmount: 100,
+					BetType:   domain.Even,
+				},
+				DiceResult:   1,
+				Won:          false,
+				ChangeAmount: -100,
 			},
 		},
 	}
 
 	for _, tc := range testCases {
-		// Simulates an existing active session for the player
 		cfg := tc.setupFunc(testDB)
 		if err := cfg.Setup(); err != nil {
 			t.Fatalf("error configuring test database: %s", err)
 		}
 
-		session, balance, err := repo.ProcessPlay(tc.transaction)
+		settlement, err := repo.ProcessPlay(tc.transaction)
 
-		assert.Equal(t, tc.expectedBalance, balance)
+		assert.Equal(t, tc.expectedBalance, settlement.Balance, tc.name)
 
-		if tc.expectedSessionResponse {
-			assert.NotEmpty(t, session)
-		} else {
-			assert.Empty(t, session)
-		}
 		if tc.expectError {
-			assert.Equal(t, tc.expectedErrorCode, err.(*appErrors.GameError).Code)
-			assert.Zero(t, balance)
-
+			assert.Equal(t, tc.expectedErrorCode, err.(*appErrors.GameError).Code, tc.name)
+			assert.Empty(t, settlement.Session, tc.name)
 		} else {
-			assert.Nil(t, err)
+			assert.Nil(t, err, tc.name)
+			assert.NotZero(t, settlement.Round.RoundID, tc.name)
+			assert.Equal(t, settlement.Session.SessionID, settlement.Round.SessionID, tc.name)
+			if tc.expectedSessionID != 0 {
+				assert.Equal(t, tc.expectedSessionID, settlement.Session.SessionID, tc.name)
+			}
+
+			// The settlement message is committed along with the play
+			messages, err := repo.ListUnackedMessages(tc.transaction.Message.ClientID)
+			assert.Nil(t, err, tc.name)
+			if assert.Len(t, messages, 1, tc.name) {
+				assert.Equal(t, settlement.Seq, messages[0].Seq, tc.name)
+				assert.Equal(t, domain.MessageTypePlay, messages[0].Type, tc.name)
+			}
 		}
 
 		if err := cfg.Cleanup(); err != nil {
@@ -278,3 +273,94 @@ func TestProcessPlay(t *testing.T) {
 		}
 	}
 }
+
+// TestVoidRound checks that voiding a round puts back every account the round moved funds from or into
+func TestVoidRound(t *testing.T) {
+	ctx := context.Background()
+	db := startTestDatabase(ctx, t)
+	testDB := NewTestConfig(ctx, t, db)
+	repo := NewGameRepository(db)
+
+	if err := testDB.WithPlayer(1, 1000).Setup(); err != nil {
+		t.Fatalf("error configuring test database: %s", err)
+	}
+	bonus, err := repo.GrantBonus(domain.PlayerBonus{PlayerID: 1, GrantedAmount: 50, WageringRequirement: 1000, ExpiresAt: time.Now().Add(time.Hour)})
+	assert.Nil(t, err)
+
+	// A lost bet staked half in cash and half from the bonus wallet, contributing to the jackpot
+	settlement, err := repo.ProcessPlay(domain.PlayTransaction{
+		Message:             domain.PlayRequest{ClientID: 1, BetAmount: 100, BetType: domain.Even},
+		DiceResult:          1,
+		ChangeAmount:        -100,
+		BonusID:             bonus.BonusID,
+		BonusStakeOrder:     domain.BonusStakeBonusFirst,
+		JackpotContribution: 1,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 950.0, settlement.Balance)
+	assert.Equal(t, 0.0, settlement.BonusBalance)
+
+	void, err := repo.VoidRound(domain.RoundVoid{RoundID: settlement.Round.RoundID, Reason: "test", VoidedBy: "ops"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1000.0, void.Balance)
+	assert.Equal(t, 50.0, void.ReversedAmount)
+	assert.Equal(t, 50.0, void.BonusRestored)
+	assert.Equal(t, -1.0, void.JackpotReversed)
+
+	restored, err := repo.GetActiveBonus(1)
+	assert.Nil(t, err)
+	if assert.NotNil(t, restored) {
+		assert.Equal(t, 50.0, restored.Amount)
+		assert.Equal(t, 0.0, restored.Wagered)
+	}
+	pool, err := repo.GetJackpotPool()
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, pool)
+
+	again, err := repo.VoidRound(domain.RoundVoid{RoundID: settlement.Round.RoundID, Reason: "test", VoidedBy: "ops"})
+	assert.Nil(t, err)
+	assert.True(t, again.AlreadyVoided)
+	assert.Equal(t, 1000.0, again.Balance)
+	assert.Equal(t, 50.0, again.BonusRestored)
+}
+
+// TestVoidRound_RefusesGambledRound checks that a round whose winnings were gambled is left settled,
+// along with the gamble counted in the session summary
+func TestVoidRound_RefusesGambledRound(t *testing.T) {
+	ctx := context.Background()
+	db := startTestDatabase(ctx, t)
+	testDB := NewTestConfig(ctx, t, db)
+	repo := NewGameRepository(db)
+
+	if err := testDB.WithPlayer(1, 1000).Setup(); err != nil {
+		t.Fatalf("error configuring test database: %s", err)
+	}
+
+	settlement, err := repo.ProcessPlay(domain.PlayTransaction{
+		Message:      domain.PlayRequest{ClientID: 1, BetAmount: 100, BetType: domain.Even},
+		DiceResult:   2,
+		Won:          true,
+		Payout:       190,
+		ChangeAmount: 90,
+		NetResult:    90,
+	})
+	assert.Nil(t, err)
+	gamble, err := repo.StartGamble(settlement.Round.RoundID, 1)
+	assert.Nil(t, err)
+	gamble.Amount, gamble.Step, gamble.Status = 0, 1, domain.GambleLost
+	_, err = repo.SettleGamble(domain.GambleResponse{ClientID: 1, Gamble: gamble}, 0, domain.MessageTypeGamble)
+	assert.Nil(t, err)
+
+	_, err = repo.VoidRound(domain.RoundVoid{RoundID: settlement.Round.RoundID, Reason: "test", VoidedBy: "ops"})
+	if assert.Error(t, err) {
+		assert.Equal(t, appErrors.VoidErrorCode, err.(*appErrors.GameError).Code)
+		assert.Equal(t, appErrors.DetailVoidGambled, err.(*appErrors.GameError).DetailKey)
+	}
+
+	balance, err := repo.GetBalance(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 900.0, balance)
+	summary, err := repo.CloseCurrentGameSession(1)
+	assert.Nil(t, err)
+	assert.Equal(t, -100.0, summary.NetResult)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// addLedgerEntry records a wallet movement made outside of the regular plays
+// The reference identifies what caused the movement, e.g. tournament:3
+func (gr *GameRepository) addLedgerEntry(tx *sql.Tx, playerID int, amount float64, kind domain.LedgerEntryKind, reference string) error {
+	query := `
+		INSERT INTO ledger_entry (player_id, amount, kind, reference)
+		VALUES ($1, $2, $3, $4)
+	;`
+	if _, err := tx.Exec(query, playerID, amount, kind, reference); err != nil {
+		return fmt.Errorf("failed to record %s ledger entry for player id %d: %w", kind, playerID, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// migrationLockID is the advisory lock serializing servers migrating the same database
+const migrationLockID = 7303
+
+// Migrate applies the .sql migrations of files that are not yet recorded in schema_migration, in file name order
+// Each migration runs in its own transaction together with its record
+func Migrate(db *sql.DB, files fs.FS) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migration (
+			version varchar(128) PRIMARY KEY,
+			applied_at timestamptz NOT NULL DEFAULT NOW()
+		)
+	;`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("error creating schema_migration table: %w", err)
+	}
+
+	versions, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return fmt.Errorf("error listing migrations: %w", err)
+	}
+	sort.Strings(versions)
+	for _, version := range versions {
+		script, err := fs.ReadFile(files, version)
+		if err != nil {
+			return fmt.Errorf("error reading migration %s: %w", version, err)
+		}
+		if err := applyMigration(db, version, string(script)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one migration unless it was already applied
+func applyMigration(db *sql.DB, version, script string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error creating database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1);`, migrationLockID); err != nil {
+		return fmt.Errorf("error locking schema_migration: %w", err)
+	}
+	var applied bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM schema_migration WHERE version = $1);`, version).Scan(&applied); err != nil {
+		return fmt.Errorf("error checking migration %s: %w", version, err)
+	}
+	if applied {
+		return nil
+	}
+	if _, err := tx.Exec(script); err != nil {
+		return fmt.Errorf("error applying migration %s: %w", version, err)
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migration (version) VALUES ($1);`, version); err != nil {
+		return fmt.Errorf("error recording migration %s: %w", version, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error commiting migration %s: %w", version, err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/lib/pq"
+)
+
+// PromoRepository redeems promo codes and keeps the free bets they grant
+type PromoRepository interface {
+	CreatePromoCode(promo domain.PromoCode) (domain.PromoCode, error)
+	RedeemPromoCode(code string, playerID int) (domain.RedeemPromoResponse, error)
+	GetFreeBet(freeBetID, playerID int) (domain.FreeBet, error)
+	ListFreeBets(playerID int) ([]domain.FreeBet, error)
+}
+
+const freeBetColumns = `free_bet_id, player_id, code, bet_amount, bet_type, COALESCE(bet_number, 0), status, expires_at`
+
+// promoCodeKey is the primary key of promo codes, violated when a code is created twice
+const promoCodeKey = "promo_code_pkey"
+
+// promoCode holds the terms of a promo code read while redeeming it
+type promoCode struct {
+	kind               domain.PromoKind
+	betAmount          sql.NullFloat64
+	betType            sql.NullString
+	betNumber          sql.NullInt64
+	freeBetCount       int
+	bonusAmount        sql.NullFloat64
+	wageringMultiplier float64
+	rewardExpiresAt    time.Time
+	maxRedemptions     sql.NullInt64
+	maxPerPlayer       int
+	redemptions        int
+	expired            bool
+}
+
+// CreatePromoCode stores a promo code, the terms not used by its kind are left empty
+func (gr *GameRepository) CreatePromoCode(promo domain.PromoCode) (domain.PromoCode, error) {
+	var betAmount, bonusAmount sql.NullFloat64
+	var betType sql.NullString
+	var betNumber, maxRedemptions sql.NullInt64
+	if promo.Kind == domain.PromoFreeBet {
+		betAmount = sql.NullFloat64{Float64: promo.BetAmount, Valid: true}
+		betType = sql.NullString{String: string(promo.BetType), Valid: true}
+		betNumber = sql.NullInt64{Int64: int64(promo.BetNumber), Valid: promo.BetNumber != 0}
+	} else {
+		bonusAmount = sql.NullFloat64{Float64: promo.BonusAmount, Valid: true}
+	}
+	if promo.MaxRedemptions > 0 {
+		maxRedemptions = sql.NullInt64{Int64: int64(promo.MaxRedemptions), Valid: true}
+	}
+
+	query := `
+		INSERT INTO promo_code (code, kind, bet_amount, bet_type, bet_number, free_bet_count, bonus_amount,
+			wagering_multiplier, reward_valid_for, max_redemptions, max_per_player, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, make_interval(secs => $9), $10, $11, $12)
+		RETURNING redemptions, created_at
+	;`
+	err := gr.db.QueryRow(query, promo.Code, promo.Kind, betAmount, betType, betNumber, promo.FreeBetCount, bonusAmount,
+		promo.WageringMultiplier, promo.RewardValidForSeconds, maxRedemptions, promo.MaxPerPlayer, promo.ExpiresAt,
+	).Scan(&promo.Redemptions, &promo.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == promoCodeKey {
+			return domain.PromoCode{}, appErrors.NewPromoError(fmt.Sprintf("promo code %s already exists", promo.Code)).WithDetail(appErrors.DetailPromoExists, promo.Code)
+		}
+		return domain.PromoCode{}, fmt.Errorf("error creating promo code %s: %w", promo.Code, err)
+	}
+	return promo, nil
+}
+
+// RedeemPromoCode checks the expiry and usage caps of a promo code and grants its reward in a single transaction
+// The promo code row stays locked until the redemption commits so concurrent redemptions cannot exceed the caps
+func (gr *GameRepository) RedeemPromoCode(code string, playerID int) (domain.RedeemPromoResponse, error) {
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.RedeemPromoResponse{}, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	var promo promoCode
+	lockQuery := `
+		SELECT kind, bet_amount, bet_type, bet_number, free_bet_count, bonus_amount, wagering_multiplier,
+			NOW() + reward_valid_for, max_redemptions, max_per_player, redemptions, expires_at <= NOW()
+		FROM promo_code
+		WHERE code = $1
+		FOR UPDATE
+	;`
+	err = tx.QueryRow(lockQuery, code).Scan(
+		&promo.kind,
+		&promo.betAmount,
+		&promo.betType,
+		&promo.betNumber,
+		&promo.freeBetCount,
+		&promo.bonusAmount,
+		&promo.wageringMultiplier,
+		&promo.rewardExpiresAt,
+		&promo.maxRedemptions,
+		&promo.maxPerPlayer,
+		&promo.redemptions,
+		&promo.expired,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.RedeemPromoResponse{}, appErrors.NewPromoError(fmt.Sprintf("unknown promo code: %s", code)).WithDetail(appErrors.DetailPromoUnknown, code)
+		}
+		return domain.RedeemPromoResponse{}, fmt.Errorf("error locking promo code %s: %w", code, err)
+	}
+	if promo.expired {
+		return domain.RedeemPromoResponse{}, appErrors.NewPromoError(fmt.Sprintf("promo code %s has expired", code)).WithDetail(appErrors.DetailPromoExpired, code)
+	}
+	if promo.maxRedemptions.Valid && int64(promo.redemptions) >= promo.maxRedemptions.Int64 {
+		return domain.RedeemPromoResponse{}, appErrors.NewPromoError(fmt.Sprintf("promo code %s has been fully redeemed", code)).WithDetail(appErrors.DetailPromoExhausted, code)
+	}
+
+	var playerRedemptions int
+	countQuery := `SELECT COUNT(*) FROM promo_redemption WHERE code = $1 AND player_id = $2`
+	if err := tx.QueryRow(countQuery, code, playerID).Scan(&playerRedemptions); err != nil {
+		return domain.RedeemPromoResponse{}, fmt.Errorf("error counting redemptions of promo code %s: %w", code, err)
+	}
+	if playerRedemptions >= promo.maxPerPlayer {
+		return domain.RedeemPromoResponse{}, appErrors.NewPromoError(fmt.Sprintf("promo code %s was already redeemed", code)).WithDetail(appErrors.DetailPromoRedeemed, code)
+	}
+
+	redemptionQuery := `
+		INSERT INTO promo_redemption (code, player_id)
+		VALUES ($1, $2)
+	;`
+	if _, err := tx.Exec(redemptionQuery, code, playerID); err != nil {
+		return domain.RedeemPromoResponse{}, fmt.Errorf("failed to redeem promo code %s for player id %d: %w", code, playerID, err)
+	}
+	countUpdateQuery := `UPDATE promo_code SET redemptions = redemptions + 1 WHERE code = $1`
+	if _, err := tx.Exec(countUpdateQuery, code); err != nil {
+		return domain.RedeemPromoResponse{}, fmt.Errorf("failed to update redemptions of promo code %s: %w", code, err)
+	}
+
+	response := domain.RedeemPromoResponse{ClientID: playerID, Code: code, Kind: promo.kind}
+	switch promo.kind {
+	case domain.PromoFreeBet:
+		response.FreeBets, err = gr.grantFreeBets(tx, code, playerID, promo)
+	case domain.PromoBonus:
+		var bonus domain.PlayerBonus
+		bonus, err = gr.insertBonus(tx, domain.PlayerBonus{
+			PlayerID:            playerID,
+			GrantedAmount:       promo.bonusAmount.Float64,
+			WageringRequirement: promo.bonusAmount.Float64 * promo.wageringMultiplier,
+			ExpiresAt:           promo.rewardExpiresAt,
+		})
+		response.Bonus = &bonus
+	default:
+		err = fmt.Errorf("unknown kind %s of promo code %s", promo.kind, code)
+	}
+	if err != nil {
+		return domain.RedeemPromoResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.RedeemPromoResponse{}, fmt.Errorf("failed to commit promo redemption transaction: %w", err)
+	}
+	return response, nil
+}
+
+func (gr *GameRepository) GetFreeBet(freeBetID, playerID int) (domain.FreeBet, error) {
+	query := `SELECT ` + freeBetColumns + ` FROM free_bet WHERE free_bet_id = $1 AND player_id = $2`
+	freeBet, err := scanFreeBet(gr.db.QueryRow(query, freeBetID, playerID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.FreeBet{}, appErrors.NewPromoError(fmt.Sprintf("unknown free bet: %d", freeBetID)).WithDetail(appErrors.DetailFreeBetUnknown, freeBetID)
+		}
+		return domain.FreeBet{}, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return freeBet, nil
+}
+
+// ListFreeBets returns the free bets of a player that are still available and not expired
+func (gr *GameRepository) ListFreeBets(playerID int) ([]domain.FreeBet, error) {
+	query := `
+		SELECT ` + freeBetColumns + ` FROM free_bet
+		WHERE player_id = $1 AND status = $2 AND expires_at > NOW()
+		ORDER BY expires_at
+	;`
+	rows, err := gr.db.Query(query, playerID, domain.FreeBetAvailable)
+	if err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	defer rows.Close()
+
+	freeBets := []domain.FreeBet{}
+	for rows.Next() {
+		freeBet, err := scanFreeBet(rows)
+		if err != nil {
+			return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+		}
+		freeBets = append(freeBets, freeBet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return freeBets, nil
+}
+
+// grantFreeBets creates the free bets of a redeemed promo code, all with the stake and bet defined by the code
+func (gr *GameRepository) grantFreeBets(tx *sql.Tx, code string, playerID int, promo promoCode) ([]domain.FreeBet, error) {
+	query := `
+		INSERT INTO free_bet (player_id, code, bet_amount, bet_type, bet_number, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + freeBetColumns + `
+	;`
+	freeBets := make([]domain.FreeBet, 0, promo.freeBetCount)
+	for i := 0; i < promo.freeBetCount; i++ {
+		freeBet, err := scanFreeBet(tx.QueryRow(query, playerID, code, promo.betAmount, promo.betType, promo.betNumber, domain.FreeBetAvailable, promo.rewardExpiresAt))
+		if err != nil {
+			return nil, fmt.Errorf("error granting free bet of promo code %s: %w", code, err)
+		}
+		freeBets = append(freeBets, freeBet)
+	}
+	return freeBets, nil
+}
+
+// useFreeBet consumes the free bet funding a play, inside the play transaction
+// The status check in the update guarantees a free bet cannot be played twice
+func (gr *GameRepository) useFreeBet(tx *sql.Tx, round domain.Round, freeBetID int) error {
+	query := `
+		UPDATE free_bet
+		SET status = $1, round_id = $2
+		WHERE free_bet_id = $3 AND player_id = $4 AND status = $5 AND expires_at > NOW()
+	;`
+	result, err := tx.Exec(query, domain.FreeBetUsed, round.RoundID, freeBetID, round.PlayerID, domain.FreeBetAvailable)
+	if err != nil {
+		return fmt.Errorf("failed to use free bet id %d: %w", freeBetID, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return appErrors.NewPromoError(fmt.Sprintf("free bet %d is no longer available", freeBetID)).WithDetail(appErrors.DetailFreeBetUnavailable, freeBetID)
+	}
+	return nil
+}
+
+// scanFreeBet reads a row selected with freeBetColumns
+func scanFreeBet(row interface{ Scan(...any) error }) (domain.FreeBet, error) {
+	var freeBet domain.FreeBet
+	err := row.Scan(
+		&freeBet.FreeBetID,
+		&freeBet.PlayerID,
+		&freeBet.Code,
+		&freeBet.BetAmount,
+		&freeBet.BetType,
+		&freeBet.BetNumber,
+		&freeBet.Status,
+		&freeBet.ExpiresAt,
+	)
+	return freeBet, err
+}
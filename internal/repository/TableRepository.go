@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// TableRepository persists the shared rounds of multiplayer tables and their bets
+type TableRepository interface {
+	CreateTableRound(tableID int, closesAt time.Time) (domain.TableRound, error)
+	UpdateTableRound(round domain.TableRound) error
+	ListUnsettledTableRounds() ([]domain.TableRound, error)
+	PlaceTableBet(bet domain.TableBet) (domain.TableBet, float64, error)
+	SettleTableRound(round domain.TableRound, results []domain.TableBetResult) (domain.TableSettlementResponse, error)
+	VoidTableRound(roundID int) error
+}
+
+func (gr *GameRepository) CreateTableRound(tableID int, closesAt time.Time) (domain.TableRound, error) {
+	round := domain.TableRound{TableID: tableID, State: domain.RoundBettingOpen, ClosesAt: closesAt}
+	query := `
+		INSERT INTO table_round (table_id, state, closes_at)
+		VALUES ($1, $2, $3)
+		RETURNING round_id
+	;`
+	if err := gr.db.QueryRow(query, tableID, round.State, closesAt).Scan(&round.RoundID); err != nil {
+		return domain.TableRound{}, appErrors.NewInternalError(fmt.Sprintf("error creating round for table %d: %v", tableID, err))
+	}
+	return round, nil
+}
+
+// UpdateTableRound stores the state and dice result of a round still being played
+// Rounds already settled or voided are never changed again
+func (gr *GameRepository) UpdateTableRound(round domain.TableRound) error {
+	query := `
+		UPDATE table_round
+		SET state = $1, dice_result = NULLIF($2, 0)
+		WHERE round_id = $3 AND state NOT IN ($4, $5)
+	;`
+	result, err := gr.db.Exec(query, round.State, round.DiceResult, round.RoundID, domain.RoundSettled, domain.RoundVoided)
+	if err != nil {
+		return fmt.Errorf("failed to update round id %d: %w", round.RoundID, err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update round id %d: %w", round.RoundID, err)
+	}
+	if updated == 0 {
+		return appErrors.NewTableRoundError(fmt.Sprintf("round %d is no longer being played", round.RoundID)).WithDetail(appErrors.DetailTableRoundOver, round.RoundID)
+	}
+	return nil
+}
+
+// ListUnsettledTableRounds returns the rounds that were neither settled nor voided, e.g. when the server stopped mid round
+func (gr *GameRepository) ListUnsettledTableRounds() ([]domain.TableRound, error) {
+	query := `
+		SELECT round_id, table_id, state, COALESCE(dice_result, 0) FROM table_round
+		WHERE state NOT IN ($1, $2)
+		ORDER BY round_id
+	;`
+	rows, err := gr.db.Query(query, domain.RoundSettled, domain.RoundVoided)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unsettled table rounds: %w", err)
+	}
+	defer rows.Close()
+
+	rounds := []domain.TableRound{}
+	for rows.Next() {
+		var round domain.TableRound
+		if err := rows.Scan(&round.RoundID, &round.TableID, &round.State, &round.DiceResult); err != nil {
+			return nil, fmt.Errorf("failed to scan table round: %w", err)
+		}
+		rounds = append(rounds, round)
+	}
+	return rounds, rows.Err()
+}
+
+// PlaceTableBet debits the stake and records the bet in a single transaction
+func (gr *GameRepository) PlaceTableBet(bet domain.TableBet) (domain.TableBet, float64, error) {
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.TableBet{}, 0, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	newBalance, err := gr.updateBalance(tx, domain.BalanceUpdate{
+		PlayerID:     bet.PlayerID,
+		ChangeAmount: -bet.BetAmount,
+	})
+	if err != nil {
+		return domain.TableBet{}, 0, err
+	}
+
+	query := `
+		INSERT INTO table_bet (round_id, player_id, bet_amount, bet_type, bet_number)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING bet_id
+	;`
+	if err := tx.QueryRow(query, bet.RoundID, bet.PlayerID, bet.BetAmount, bet.BetType, bet.BetNumber).Scan(&bet.BetID); err != nil {
+		return domain.TableBet{}, 0, fmt.Errorf("error placing bet for player id %d: %w", bet.PlayerID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.TableBet{}, 0, fmt.Errorf("failed to commit table bet transaction: %w", err)
+	}
+	return bet, newBalance, nil
+}
+
+// SettleTableRound credits every payout and closes the round atomically
+// Either every bet of the round is settled or none is, along with the table_bet_settled message of each player
+func (gr *GameRepository) SettleTableRound(round domain.TableRound, results []domain.TableBetResult) (domain.TableSettlementResponse, error) {
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.TableSettlementResponse{}, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	settlement := domain.TableSettlementResponse{TableID: round.TableID, RoundID: round.RoundID, DiceResult: round.DiceResult}
+	for _, result := range results {
+		result.Balance, err = gr.updateBalance(tx, domain.BalanceUpdate{
+			PlayerID:     result.PlayerID,
+			ChangeAmount: result.Payout,
+		})
+		if err != nil {
+			return domain.TableSettlementResponse{}, err
+		}
+
+		query := `
+			UPDATE table_bet
+			SET won = $1, payout = $2
+			WHERE bet_id = $3
+		;`
+		if _, err := tx.Exec(query, result.Won, result.Payout, result.BetID); err != nil {
+			return domain.TableSettlementResponse{}, fmt.Errorf("failed to settle bet id %d: %w", result.BetID, err)
+		}
+		settlement.Results = append(settlement.Results, result)
+	}
+
+	for _, playerResults := range settlement.PlayerResults() {
+		message, err := gr.storeSettlementMessage(tx, playerResults.Results[0].PlayerID, domain.MessageTypeTableBetSettled, playerResults)
+		if err != nil {
+			return domain.TableSettlementResponse{}, err
+		}
+		settlement.Messages = append(settlement.Messages, message)
+	}
+
+	query := `
+		UPDATE table_round
+		SET state = $1, dice_result = $2, settled_at = NOW()
+		WHERE round_id = $3
+	;`
+	if _, err := tx.Exec(query, domain.RoundSettled, round.DiceResult, round.RoundID); err != nil {
+		return domain.TableSettlementResponse{}, fmt.Errorf("failed to settle round id %d: %w", round.RoundID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.TableSettlementResponse{}, fmt.Errorf("failed to commit table settlement transaction: %w", err)
+	}
+	return settlement, nil
+}
+
+// VoidTableRound refunds every stake recorded for a round that could not be settled
+// Rounds already settled or voided are left untouched, so a void can be retried until it commits
+func (gr *GameRepository) VoidTableRound(roundID int) error {
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	var state domain.TableRoundState
+	if err := tx.QueryRow(`SELECT state FROM table_round WHERE round_id = $1 FOR UPDATE`, roundID).Scan(&state); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.NewTableRoundError(fmt.Sprintf("unknown round: %d", roundID)).WithDetail(appErrors.DetailUnknownRound, roundID)
+		}
+		return fmt.Errorf("error locking round id %d: %w", roundID, err)
+	}
+	if state == domain.RoundSettled || state == domain.RoundVoided {
+		return nil
+	}
+
+	rows, err := tx.Query(`SELECT player_id, bet_amount FROM table_bet WHERE round_id = $1 ORDER BY bet_id`, roundID)
+	if err != nil {
+		return fmt.Errorf("failed to list bets of round id %d: %w", roundID, err)
+	}
+	var refunds []domain.BalanceUpdate
+	for rows.Next() {
+		var refund domain.BalanceUpdate
+		if err := rows.Scan(&refund.PlayerID, &refund.ChangeAmount); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan bet of round id %d: %w", roundID, err)
+		}
+		refunds = append(refunds, refund)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list bets of round id %d: %w", roundID, err)
+	}
+
+	for _, refund := range refunds {
+		if _, err := gr.updateBalance(tx, refund); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE table_round
+		SET state = $1, settled_at = NOW()
+		WHERE round_id = $2
+	;`
+	if _, err := tx.Exec(query, domain.RoundVoided, roundID); err != nil {
+		return fmt.Errorf("failed to void round id %d: %w", roundID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit table void transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/lib/pq"
+)
+
+// TournamentRepository persists tournaments, the chip stacks of their entries and the prizes paid at close
+type TournamentRepository interface {
+	CreateTournament(tournament domain.Tournament) (domain.Tournament, error)
+	ListTournaments() ([]domain.Tournament, error)
+	GetTournament(tournamentID int) (domain.Tournament, error)
+	RegisterTournamentEntry(tournament domain.Tournament, playerID int) (domain.TournamentEntry, float64, error)
+	GetTournamentEntry(tournamentID, playerID int) (domain.TournamentEntry, error)
+	GetTournamentEntries(tournamentID int) ([]domain.TournamentEntry, error)
+	UpdateTournamentStack(playerID int, changeAmount float64, res domain.TournamentPlayResponse) (domain.TournamentPlayResponse, error)
+	DueTournaments() ([]domain.Tournament, error)
+	CloseTournament(tournamentID int, prizes []domain.TournamentPrize, leftover float64) (bool, error)
+}
+
+const tournamentColumns = `tournament_id, name, buy_in, starting_stack, ranking, prize_pool, prize_shares, prize_leftover, starts_at, ends_at, closed`
+
+// CreateTournament stores a tournament, its prize pool starts empty and grows with every buy-in
+func (gr *GameRepository) CreateTournament(tournament domain.Tournament) (domain.Tournament, error) {
+	query := `
+		INSERT INTO tournament (name, buy_in, starting_stack, ranking, prize_shares, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + tournamentColumns + `
+	;`
+	created, err := scanTournament(gr.db.QueryRow(query, tournament.Name, tournament.BuyIn, tournament.StartingStack, tournament.Ranking,
+		pq.Array(tournament.PrizeShares), tournament.StartsAt, tournament.EndsAt))
+	if err != nil {
+		return domain.Tournament{}, fmt.Errorf("error creating tournament %s: %w", tournament.Name, err)
+	}
+	return created, nil
+}
+
+// ListTournaments returns the tournaments that have not been closed yet
+func (gr *GameRepository) ListTournaments() ([]domain.Tournament, error) {
+	query := `SELECT ` + tournamentColumns + ` FROM tournament WHERE closed = false ORDER BY starts_at`
+	return gr.queryTournaments(query)
+}
+
+func (gr *GameRepository) GetTournament(tournamentID int) (domain.Tournament, error) {
+	query := `SELECT ` + tournamentColumns + ` FROM tournament WHERE tournament_id = $1`
+	tournament, err := scanTournament(gr.db.QueryRow(query, tournamentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Tournament{}, appErrors.NewTournamentError(fmt.Sprintf("unknown tournament: %d", tournamentID)).WithDetail(appErrors.DetailTournamentUnknown, tournamentID)
+		}
+		return domain.Tournament{}, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return tournament, nil
+}
+
+// RegisterTournamentEntry debits the buy-in from the wallet, records it in the ledger,
+// creates the entry with the starting stack and adds the buy-in to the prize pool in a single transaction
+func (gr *GameRepository) RegisterTournamentEntry(tournament domain.Tournament, playerID int) (domain.TournamentEntry, float64, error) {
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.TournamentEntry{}, 0, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	newBalance, err := gr.updateBalance(tx, domain.BalanceUpdate{
+		PlayerID:     playerID,
+		ChangeAmount: -tournament.BuyIn,
+	})
+	if err != nil {
+		return domain.TournamentEntry{}, 0, err
+	}
+	if err := gr.addLedgerEntry(tx, playerID, -tournament.BuyIn, domain.LedgerTournamentBuyIn, tournamentReference(tournament.TournamentID)); err != nil {
+		return domain.TournamentEntry{}, 0, err
+	}
+
+	entry := domain.TournamentEntry{TournamentID: tournament.TournamentID, PlayerID: playerID}
+	entryQuery := `
+		INSERT INTO tournament_entry (tournament_id, player_id, stack)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		RETURNING stack, rounds_played, registered_at
+	;`
+	err = tx.QueryRow(entryQuery, tournament.TournamentID, playerID, tournament.StartingStack).Scan(&entry.Stack, &entry.RoundsPlayed, &entry.RegisteredAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.TournamentEntry{}, 0, appErrors.NewTournamentError(fmt.Sprintf("player %d is already registered to tournament %d", playerID, tournament.TournamentID)).WithDetail(appErrors.DetailTournamentRegistered, playerID, tournament.TournamentID)
+		}
+		return domain.TournamentEntry{}, 0, fmt.Errorf("error registering player id %d: %w", playerID, err)
+	}
+
+	poolQuery := `
+		UPDATE tournament
+		SET prize_pool = prize_pool + $1
+		WHERE tournament_id = $2
+	;`
+	if _, err := tx.Exec(poolQuery, tournament.BuyIn, tournament.TournamentID); err != nil {
+		return domain.TournamentEntry{}, 0, fmt.Errorf("failed to update prize pool of tournament id %d: %w", tournament.TournamentID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.TournamentEntry{}, 0, fmt.Errorf("failed to commit tournament registration transaction: %w", err)
+	}
+	return entry, newBalance, nil
+}
+
+func (gr *GameRepository) GetTournamentEntry(tournamentID, playerID int) (domain.TournamentEntry, error) {
+	entry := domain.TournamentEntry{TournamentID: tournamentID, PlayerID: playerID}
+	query := `
+		SELECT stack, rounds_played, registered_at FROM tournament_entry
+		WHERE tournament_id = $1 AND player_id = $2
+	;`
+	if err := gr.db.QueryRow(query, tournamentID, playerID).Scan(&entry.Stack, &entry.RoundsPlayed, &entry.RegisteredAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.TournamentEntry{}, appErrors.NewTournamentError(fmt.Sprintf("player %d is not registered to tournament %d", playerID, tournamentID)).WithDetail(appErrors.DetailTournamentNotRegistered, playerID, tournamentID)
+		}
+		return domain.TournamentEntry{}, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return entry, nil
+}
+
+func (gr *GameRepository) GetTournamentEntries(tournamentID int) ([]domain.TournamentEntry, error) {
+	query := `
+		SELECT player_id, stack, rounds_played, registered_at FROM tournament_entry
+		WHERE tournament_id = $1
+	;`
+	rows, err := gr.db.Query(query, tournamentID)
+	if err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	defer rows.Close()
+
+	var entries []domain.TournamentEntry
+	for rows.Next() {
+		entry := domain.TournamentEntry{TournamentID: tournamentID}
+		if err := rows.Scan(&entry.PlayerID, &entry.Stack, &entry.RoundsPlayed, &entry.RegisteredAt); err != nil {
+			return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return entries, nil
+}
+
+// UpdateTournamentStack applies the result of a round to the tournament stack, never touching the wallet,
+// and keeps the message reporting the round in the same transaction
+func (gr *GameRepository) UpdateTournamentStack(playerID int, changeAmount float64, res domain.TournamentPlayResponse) (domain.TournamentPlayResponse, error) {
+	tournamentID := res.TournamentID
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.TournamentPlayResponse{}, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	entry := domain.TournamentEntry{TournamentID: tournamentID, PlayerID: playerID}
+	lockQuery := `
+		SELECT stack, rounds_played, registered_at FROM tournament_entry
+		WHERE tournament_id = $1 AND player_id = $2
+		FOR UPDATE
+	;`
+	if err := tx.QueryRow(lockQuery, tournamentID, playerID).Scan(&entry.Stack, &entry.RoundsPlayed, &entry.RegisteredAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.TournamentPlayResponse{}, appErrors.NewTournamentError(fmt.Sprintf("player %d is not registered to tournament %d", playerID, tournamentID)).WithDetail(appErrors.DetailTournamentNotRegistered, playerID, tournamentID)
+		}
+		return domain.TournamentPlayResponse{}, fmt.Errorf("error locking tournament entry: %w", err)
+	}
+
+	entry.Stack += changeAmount
+	entry.RoundsPlayed++
+	if err := validateBalance(entry.Stack); err != nil {
+		return domain.TournamentPlayResponse{}, err
+	}
+
+	updateQuery := `
+		UPDATE tournament_entry
+		SET stack = $1, rounds_played = $2
+		WHERE tournament_id = $3 AND player_id = $4
+	;`
+	if _, err := tx.Exec(updateQuery, entry.Stack, entry.RoundsPlayed, tournamentID, playerID); err != nil {
+		return domain.TournamentPlayResponse{}, fmt.Errorf("failed to update tournament stack: %w", err)
+	}
+
+	res.Stack = entry.Stack
+	message, err := gr.storeSettlementMessage(tx, playerID, domain.MessageTypeTournamentPlay, res)
+	if err != nil {
+		return domain.TournamentPlayResponse{}, err
+	}
+	res.Seq = message.Seq
+
+	if err := tx.Commit(); err != nil {
+		return domain.TournamentPlayResponse{}, fmt.Errorf("failed to commit tournament play transaction: %w", err)
+	}
+	return res, nil
+}
+
+// DueTournaments returns the tournaments past their end that still have to be closed
+func (gr *GameRepository) DueTournaments() ([]domain.Tournament, error) {
+	query := `SELECT ` + tournamentColumns + ` FROM tournament WHERE closed = false AND ends_at <= NOW() ORDER BY ends_at`
+	return gr.queryTournaments(query)
+}
+
+// CloseTournament marks the tournament closed, recording the leftover of the prize pool no rank was paid,
+// and credits the prizes to the wallets with their ledger entries
+// Returns false without paying anything when the tournament had already been closed
+func (gr *GameRepository) CloseTournament(tournamentID int, prizes []domain.TournamentPrize, leftover float64) (bool, error) {
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return false, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	closeQuery := `
+		UPDATE tournament
+		SET closed = true, prize_leftover = $2
+		WHERE tournament_id = $1 AND closed = false
+	;`
+	result, err := tx.Exec(closeQuery, tournamentID, leftover)
+	if err != nil {
+		return false, fmt.Errorf("failed to close tournament id %d: %w", tournamentID, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	for _, prize := range prizes {
+		if _, err := gr.updateBalance(tx, domain.BalanceUpdate{
+			PlayerID:     prize.PlayerID,
+			ChangeAmount: prize.Amount,
+		}); err != nil {
+			return false, err
+		}
+		if err := gr.addLedgerEntry(tx, prize.PlayerID, prize.Amount, domain.LedgerTournamentPrize, tournamentReference(tournamentID)); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit tournament close transaction: %w", err)
+	}
+	return true, nil
+}
+
+// tournamentReference identifies a tournament in the ledger
+func tournamentReference(tournamentID int) string {
+	return "tournament:" + strconv.Itoa(tournamentID)
+}
+
+func (gr *GameRepository) queryTournaments(query string) ([]domain.Tournament, error) {
+	rows, err := gr.db.Query(query)
+	if err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	defer rows.Close()
+
+	var tournaments []domain.Tournament
+	for rows.Next() {
+		tournament, err := scanTournament(rows)
+		if err != nil {
+			return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+		}
+		tournaments = append(tournaments, tournament)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, appErrors.NewInternalError(fmt.Sprintf("Database error: %v", err))
+	}
+	return tournaments, nil
+}
+
+// scanTournament reads a row selected with tournamentColumns
+func scanTournament(row interface{ Scan(...any) error }) (domain.Tournament, error) {
+	var tournament domain.Tournament
+	err := row.Scan(
+		&tournament.TournamentID,
+		&tournament.Name,
+		&tournament.BuyIn,
+		&tournament.StartingStack,
+		&tournament.Ranking,
+		&tournament.PrizePool,
+		pq.Array(&tournament.PrizeShares),
+		&tournament.PrizeLeftover,
+		&tournament.StartsAt,
+		&tournament.EndsAt,
+		&tournament.Closed,
+	)
+	return tournament, err
+}
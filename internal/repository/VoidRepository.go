@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/lib/pq"
+)
+
+// VoidRepository reverses settled rounds and keeps the audit trail of the voids
+type VoidRepository interface {
+	VoidRound(void domain.RoundVoid) (domain.RoundVoid, error)
+}
+
+// VoidRound marks a round as voided and reverses what it moved in a single transaction: the cash balance change,
+// the jackpot contribution and award, the bonus wallet change and the free bet it used
+// Rounds whose winnings were gambled are refused. Voiding a round twice returns the recorded void without reversing anything again
+// The round_voided message reporting the void to the player is stored in the same transaction
+func (gr *GameRepository) VoidRound(void domain.RoundVoid) (domain.RoundVoid, error) {
+	tx, err := gr.db.Begin()
+	if err != nil {
+		return domain.RoundVoid{}, appErrors.NewInternalError(fmt.Sprintf("error creating database transaction: %s", err))
+	}
+	defer tx.Rollback()
+
+	var round domain.Round
+	lockQuery := `
+		SELECT player_id, bet_amount, balance_change, COALESCE(free_bet_id, 0), voided,
+			jackpot_contribution, jackpot_award, COALESCE(bonus_id, 0), bonus_change, bonus_converted
+		FROM round
+		WHERE round_id = $1
+		FOR UPDATE
+	;`
+	err = tx.QueryRow(lockQuery, void.RoundID).Scan(
+		&round.PlayerID,
+		&round.BetAmount,
+		&round.BalanceChange,
+		&round.FreeBetID,
+		&round.Voided,
+		&round.JackpotContribution,
+		&round.JackpotAward,
+		&round.BonusID,
+		&round.BonusChange,
+		&round.BonusConverted,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.RoundVoid{}, appErrors.NewVoidError(fmt.Sprintf("unknown round: %d", void.RoundID)).WithDetail(appErrors.DetailUnknownRound, void.RoundID)
+		}
+		return domain.RoundVoid{}, fmt.Errorf("error locking round id %d: %w", void.RoundID, err)
+	}
+	if round.Voided {
+		return gr.getRoundVoid(tx, void.RoundID)
+	}
+	round.RoundID = void.RoundID
+	void.PlayerID = round.PlayerID
+
+	// StartGamble locks the round before opening a gamble on it, so none can be opened while it is being voided
+	var gambled bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM gamble WHERE round_id = $1)`, void.RoundID).Scan(&gambled); err != nil {
+		return domain.RoundVoid{}, fmt.Errorf("error reading gamble of round id %d: %w", void.RoundID, err)
+	}
+	if gambled {
+		return domain.RoundVoid{}, appErrors.NewVoidError(fmt.Sprintf("round %d was gambled and cannot be voided", void.RoundID)).WithDetail(appErrors.DetailVoidGambled, void.RoundID)
+	}
+
+	void.Balance, err = gr.updateBalance(tx, domain.BalanceUpdate{PlayerID: void.PlayerID, ChangeAmount: -round.BalanceChange})
+	if err != nil {
+		if errors.Is(err, ErrNegativeBalance) {
+			return domain.RoundVoid{}, appErrors.NewVoidError(fmt.Sprintf("balance of player %d cannot cover the reversal of round %d", void.PlayerID, void.RoundID)).WithDetail(appErrors.DetailVoidBalance, void.PlayerID, void.RoundID)
+		}
+		return domain.RoundVoid{}, err
+	}
+	void.ReversedAmount = -round.BalanceChange
+	if void.ReversedAmount != 0 {
+		if err := gr.addLedgerEntry(tx, void.PlayerID, void.ReversedAmount, domain.LedgerRoundVoid, "round:"+strconv.Itoa(void.RoundID)); err != nil {
+			return domain.RoundVoid{}, err
+		}
+	}
+
+	if void.JackpotReversed, err = gr.reverseJackpot(tx, round); err != nil {
+		return domain.RoundVoid{}, err
+	}
+	if round.BonusID != 0 {
+		if void.BonusRestored, err = gr.restoreBonus(tx, round); err != nil {
+			return domain.RoundVoid{}, err
+		}
+	}
+	if round.FreeBetID != 0 {
+		if err := gr.restoreFreeBet(tx, round); err != nil {
+			return domain.RoundVoid{}, err
+		}
+		void.FreeBetRestored = true
+	}
+
+	if _, err := tx.Exec(`UPDATE round SET voided = true WHERE round_id = $1`, void.RoundID); err != nil {
+		return domain.RoundVoid{}, fmt.Errorf("failed to void round id %d: %w", void.RoundID, err)
+	}
+	auditQuery := `
+		INSERT INTO round_void (round_id, player_id, reason, voided_by, reversed_amount, jackpot_reversed, bonus_restored, free_bet_restored)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING voided_at
+	;`
+	err = tx.QueryRow(
+		auditQuery,
+		void.RoundID,
+		void.PlayerID,
+		void.Reason,
+		void.VoidedBy,
+		void.ReversedAmount,
+		void.JackpotReversed,
+		void.BonusRestored,
+		void.FreeBetRestored,
+	).Scan(&void.VoidedAt)
+	if err != nil {
+		return domain.RoundVoid{}, fmt.Errorf("failed to record void of round id %d: %w", void.RoundID, err)
+	}
+
+	message, err := gr.storeSettlementMessage(tx, void.PlayerID, domain.MessageTypeRoundVoided, void)
+	if err != nil {
+		return domain.RoundVoid{}, err
+	}
+	void.Seq = message.Seq
+
+	if err := tx.Commit(); err != nil {
+		return domain.RoundVoid{}, fmt.Errorf("failed to commit void transaction: %w", err)
+	}
+	return void, nil
+}
+
+// reverseJackpot returns the award of a round to the jackpot pool and takes its contribution back
+// A contribution already paid out by a later hit cannot be recovered, so the pool never goes below zero
+func (gr *GameRepository) reverseJackpot(tx *sql.Tx, round domain.Round) (float64, error) {
+	change := round.JackpotAward - round.JackpotContribution
+	if change == 0 {
+		return 0, nil
+	}
+	var pool float64
+	lockQuery := `SELECT amount FROM jackpot_pool WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(lockQuery, jackpotPoolID).Scan(&pool); err != nil {
+		return 0, fmt.Errorf("error locking jackpot pool: %w", err)
+	}
+	reversed := math.Max(change, -pool)
+	updateQuery := `
+		UPDATE jackpot_pool
+		SET amount = $1, updated_at = NOW()
+		WHERE id = $2
+	;`
+	if _, err := tx.Exec(updateQuery, pool+reversed, jackpotPoolID); err != nil {
+		return 0, fmt.Errorf("failed to reverse jackpot of round id %d: %w", round.RoundID, err)
+	}
+	return reversed, nil
+}
+
+// restoreBonus puts the bonus share of a round back into the bonus wallet and takes its stake off the wagering progress
+// A bonus the round completed or depleted is reopened, a bonus closed by anything else cannot take the funds back
+func (gr *GameRepository) restoreBonus(tx *sql.Tx, round domain.Round) (float64, error) {
+	lockQuery := `
+		SELECT ` + bonusColumns + ` FROM player_bonus
+		WHERE bonus_id = $1
+		FOR UPDATE
+	;`
+	bonus, err := scanBonus(tx.QueryRow(lockQuery, round.BonusID))
+	if err != nil {
+		return 0, fmt.Errorf("error locking bonus id %d: %w", round.BonusID, err)
+	}
+
+	restored := round.BonusConverted - round.BonusChange
+	if bonus.Status != domain.BonusActive {
+		var laterRounds bool
+		laterQuery := `SELECT EXISTS (SELECT 1 FROM round WHERE bonus_id = $1 AND round_id > $2 AND NOT voided)`
+		if err := tx.QueryRow(laterQuery, round.BonusID, round.RoundID).Scan(&laterRounds); err != nil {
+			return 0, fmt.Errorf("error reading rounds of bonus id %d: %w", round.BonusID, err)
+		}
+		closedByRound := !laterRounds && (round.BonusConverted > 0 || bonus.Amount == 0)
+		if !closedByRound || !time.Now().Before(bonus.ExpiresAt) {
+			return 0, appErrors.NewVoidError(fmt.Sprintf("bonus %d funding round %d was closed since and cannot be restored", round.BonusID, round.RoundID)).WithDetail(appErrors.DetailVoidBonusClosed, round.BonusID, round.RoundID)
+		}
+	}
+
+	amount := bonus.Amount + restored
+	if err := validateBalance(amount); err != nil {
+		return 0, appErrors.NewVoidError(fmt.Sprintf("bonus %d cannot cover the reversal of round %d", round.BonusID, round.RoundID)).WithDetail(appErrors.DetailVoidBonusBalance, round.BonusID, round.RoundID)
+	}
+	updateQuery := `
+		UPDATE player_bonus
+		SET amount = $1, wagered = GREATEST(wagered - $2, 0), status = $3, closed_at = NULL
+		WHERE bonus_id = $4
+	;`
+	if _, err := tx.Exec(updateQuery, amount, round.BetAmount, domain.BonusActive, round.BonusID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == activeBonusIndex {
+			return 0, appErrors.NewVoidError(fmt.Sprintf("player %d holds another active bonus, bonus %d cannot be reopened", round.PlayerID, round.BonusID)).WithDetail(appErrors.DetailVoidBonusActive, round.PlayerID, round.BonusID)
+		}
+		return 0, fmt.Errorf("failed to restore bonus id %d: %w", round.BonusID, err)
+	}
+	if round.BonusConverted > 0 {
+		if err := gr.addLedgerEntry(tx, round.PlayerID, -round.BonusConverted, domain.LedgerBonusConversion, "bonus:"+strconv.Itoa(round.BonusID)); err != nil {
+			return 0, err
+		}
+	}
+	return restored, nil
+}
+
+// restoreFreeBet makes the free bet that funded a round available again
+func (gr *GameRepository) restoreFreeBet(tx *sql.Tx, round domain.Round) error {
+	query := `
+		UPDATE free_bet
+		SET status = $1, round_id = NULL
+		WHERE free_bet_id = $2 AND round_id = $3
+	;`
+	if _, err := tx.Exec(query, domain.FreeBetAvailable, round.FreeBetID, round.RoundID); err != nil {
+		return fmt.Errorf("failed to restore free bet id %d: %w", round.FreeBetID, err)
+	}
+	return nil
+}
+
+// getRoundVoid reads the audit record of a round voided earlier along with the current balance of its player
+func (gr *GameRepository) getRoundVoid(tx *sql.Tx, roundID int) (domain.RoundVoid, error) {
+	void := domain.RoundVoid{AlreadyVoided: true}
+	query := `
+		SELECT v.round_id, v.player_id, v.reason, v.voided_by, v.reversed_amount, v.jackpot_reversed, v.bonus_restored,
+			v.free_bet_restored, v.voided_at, p.balance
+		FROM round_void v
+		JOIN player p ON p.id = v.player_id
+		WHERE v.round_id = $1
+	;`
+	err := tx.QueryRow(query, roundID).Scan(
+		&void.RoundID,
+		&void.PlayerID,
+		&void.Reason,
+		&void.VoidedBy,
+		&void.ReversedAmount,
+		&void.JackpotReversed,
+		&void.BonusRestored,
+		&void.FreeBetRestored,
+		&void.VoidedAt,
+		&void.Balance,
+	)
+	if err != nil {
+		return domain.RoundVoid{}, fmt.Errorf("error reading void of round id %d: %w", roundID, err)
+	}
+	return void, nil
+}
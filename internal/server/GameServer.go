@@ -2,71 +2,180 @@ package server
 
 import (
 	"encoding/json"
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
-	"time"
 
 	"github.com/Desgue/SpicyDice/internal/config"
 	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/i18n"
 	"github.com/Desgue/SpicyDice/internal/service"
 	"github.com/gorilla/websocket"
 )
 
-const (
-	tickDuration    time.Duration = 30 * time.Second
-	readBufferSize  int           = 1024
-	writeBufferSize int           = 1024
-	writeTimeout    time.Duration = 10 * time.Second
-	readTimeout     time.Duration = 60 * time.Second
-)
-
+// WsMessage is the envelope of every message, the optional id of a request is echoed on its response or error
+// and server pushes carry ids generated by the server. Settlement messages carry the sequence number clients acknowledge
 type WsMessage struct {
+	ID      string             `json:"id,omitempty"`
+	Seq     int64              `json:"seq,omitempty"`
 	Type    domain.MessageType `json:"type"`
 	Payload json.RawMessage    `json:"payload"`
 }
 
 type WebSocketServer struct {
-	service  *service.GameService
-	upgrader websocket.Upgrader
+	service     *service.GameService
+	tables      *service.TableService
+	tournaments *service.TournamentService
+	conf        *config.Config
+	newDice     func() service.DiceRoller
+	hub         *hub
+	sse         *sseConnections
+	catalog     *i18n.Catalog
+	presence    *presence
+	checks      *realityChecks
+	upgrader    websocket.Upgrader
 }
 
-func NewWebSocketServer(service *service.GameService) *WebSocketServer {
-	return &WebSocketServer{
-		service: service,
+// NewWebSocketServer creates the server, newDice provides each connection and table with its own dice
+func NewWebSocketServer(service *service.GameService, conf *config.Config, newDice func() service.DiceRoller, tables *service.TableService, tournaments *service.TournamentService) *WebSocketServer {
+	s := &WebSocketServer{
+		service:     service,
+		tables:      tables,
+		tournaments: tournaments,
+		conf:        conf,
+		newDice:     newDice,
+		hub:         newHub(),
+		sse:         newSSEConnections(),
+		catalog:     i18n.NewCatalog(conf.Locale),
+		presence:    newPresence(),
 		upgrader: websocket.Upgrader{
-			ReadBufferSize:  readBufferSize,
-			WriteBufferSize: writeBufferSize,
+			ReadBufferSize:    conf.Server.ReadBufferSize,
+			WriteBufferSize:   conf.Server.WriteBufferSize,
+			EnableCompression: conf.Server.EnableCompression,
+			Subprotocols:      subprotocols(),
 			CheckOrigin: func(r *http.Request) bool {
 				return true
 			},
 		},
 	}
+	s.checks = newRealityChecks(conf.RealityCheck.Interval, s.pushRealityCheck)
+	return s
 }
 func (s *WebSocketServer) Run() {
-	http.HandleFunc("/ws/spicy-dice", s.Serve)
-	port := config.New().Server.Port
+	if s.conf.Server.AuthSecret == "" {
+		log.Printf("AUTH_SECRET is not set, every player request will be refused")
+	}
+	if s.service.JackpotEnabled() {
+		go s.broadcastJackpot(s.conf.Game.Jackpot.BroadcastInterval)
+	}
+	if voided, err := s.tables.VoidUnsettledRounds(); err != nil {
+		log.Printf("Error voiding unsettled table rounds: %v", err)
+	} else if voided > 0 {
+		log.Printf("Voided %d unsettled table round(s) and refunded their stakes", voided)
+	}
+	for _, tableID := range s.tables.TableIDs() {
+		go s.runTable(tableID)
+	}
+	go s.closeTournaments(s.conf.Tournaments.CloseInterval)
+	go s.forfeitExpiredBonuses(s.conf.Game.Bonus.ExpiryInterval)
+	go s.purgeSettlementMessages(s.conf.Delivery.PurgeInterval, s.conf.Delivery.Retention)
+	go s.evictPresence(s.conf.Presence.EvictInterval, s.conf.Presence.TTL)
+	port := s.conf.Server.Port
 	log.Printf("Starting WebSocket server on port :%s", port)
-	err := http.ListenAndServe(fmt.Sprintf(":%s", port), nil)
+	err := http.ListenAndServe(fmt.Sprintf(":%s", port), s.routes())
 	if err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
+
+// routes registers the frontend, the player endpoints and, when an admin token is configured, the admin endpoints
+// The server has its own mux so nothing registered on http.DefaultServeMux, such as expvar's /debug/vars, is exposed
+func (s *WebSocketServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	s.registerFrontend(mux)
+	mux.HandleFunc("/ws/spicy-dice", s.authenticate(s.Serve))
+	mux.HandleFunc("GET /sse/spicy-dice", s.authenticate(s.handleSSEStream))
+	mux.HandleFunc("POST /sse/spicy-dice/messages", s.authenticate(s.handleSSEMessage))
+	s.registerAPI(mux)
+	if s.conf.Admin.Token != "" {
+		mux.HandleFunc("/admin/", s.requireAdmin(s.adminRoutes().ServeHTTP))
+	}
+	return mux
+}
+
+// adminRoutes registers the operator endpoints, every one of them is served behind requireAdmin
+func (s *WebSocketServer) adminRoutes() *http.ServeMux {
+	admin := http.NewServeMux()
+	admin.HandleFunc("/admin/rounds/void", s.handleVoidRound)
+	admin.HandleFunc("GET /admin/presence", s.handlePresence)
+	admin.HandleFunc("GET /admin/presence/{id}", s.handlePlayerPresence)
+	admin.HandleFunc("POST /admin/players/{id}/token", s.handleIssuePlayerToken)
+	admin.HandleFunc("POST /admin/players/{id}/bonus", s.handleGrantBonus)
+	admin.HandleFunc("POST /admin/promos", s.handleCreatePromoCode)
+	admin.HandleFunc("POST /admin/tournaments", s.handleCreateTournament)
+	admin.Handle("GET /admin/metrics", expvar.Handler())
+	return admin
+}
+
 func (s *WebSocketServer) Serve(w http.ResponseWriter, r *http.Request) {
-	ws, err := s.upgrader.Upgrade(w, r, nil)
+	proto, err := upgradeProtocol(r, "")
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	ws, err := s.upgrader.Upgrade(countingResponseWriter{w}, r, nil)
 	if err != nil {
 		log.Printf("Error upgrading connection: %v", err)
 		return
 	}
-
-	conn := connection{
-		service:      s.service,
-		ws:           ws,
-		messagesChan: make(chan WsMessage, 100),
-		doneChan:     make(chan struct{}),
+	ws.SetReadLimit(s.conf.Server.MaxMessageSize)
+	if s.conf.Server.EnableCompression {
+		if err := ws.SetCompressionLevel(s.conf.Server.CompressionLevel); err != nil {
+			log.Printf("Error setting compression level: %v", err)
+		}
 	}
+	frameCodec := codec(jsonCodec{})
+	if ws.Subprotocol() != "" {
+		// The upgrader only selects offered subprotocols, so the negotiated one is supported
+		proto, _ = upgradeProtocol(r, ws.Subprotocol())
+		_, frameCodec = splitSubprotocol(ws.Subprotocol())
+	}
+
+	conn := s.newConnection(authenticatedPlayer(r), proto, s.catalog.Negotiate(r.Header.Get("Accept-Language")))
+	conn.ws = ws
+	conn.codec = frameCodec
+	s.hub.register(conn)
+	conn.identify()
 
 	go conn.readPump()
 	go conn.writePump()
 
 }
+
+// newConnection creates a connection of the player speaking the protocol in the locale of the localizer, its transport is attached by the caller
+func (s *WebSocketServer) newConnection(playerID int, proto *protocol, localizer *i18n.Localizer) *connection {
+	conn := &connection{
+		playerID:           playerID,
+		service:            s.service,
+		tables:             s.tables,
+		tournaments:        s.tournaments,
+		dice:               s.newDice(),
+		hub:                s.hub,
+		codec:              jsonCodec{},
+		messagesChan:       make(chan WsMessage, 100),
+		doneChan:           make(chan struct{}),
+		autoplayRoundDelay: s.conf.Game.Autoplay.RoundDelay,
+		features:           s.features(),
+		catalog:            s.catalog,
+		presence:           s.presence,
+		pingInterval:       s.conf.Server.PingInterval,
+		readTimeout:        s.conf.Server.ReadTimeout,
+		writeTimeout:       s.conf.Server.WriteTimeout,
+	}
+	conn.protocol.Store(proto)
+	conn.localizer.Store(localizer)
+	conn.checks = s.checks
+	conn.realityCheck = s.checks.acquire(playerID)
+	return conn
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialTestServer serves the WebSocket endpoint with the given server settings and connects to it
+func dialTestServer(t *testing.T, maxMessageSize int64, compression bool) (*websocket.Conn, *http.Response) {
+	conf := *testConfig
+	conf.Server.ReadBufferSize = 1024
+	conf.Server.WriteBufferSize = 1024
+	conf.Server.MaxMessageSize = maxMessageSize
+	conf.Server.EnableCompression = compression
+	conf.Server.CompressionLevel = 1
+
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 250)
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, conf.Game), &conf, newDice, nil, nil)
+	server := httptest.NewServer(s.authenticate(s.Serve))
+	t.Cleanup(server.Close)
+
+	dialer := websocket.Dialer{EnableCompression: compression}
+	ws, res, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), authHeader(1))
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws, res
+}
+
+// metricValue reads a WebSocket metric, zero until it is first recorded
+func metricValue(key string) int64 {
+	if v, ok := wsMetrics.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestServe_ClosesOversizedMessages(t *testing.T) {
+	ws, _ := dialTestServer(t, 128, false)
+	tooLarge := metricValue(metricMessagesTooLarge)
+
+	require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(`{"type":"wallet","payload":{"client_id":1,"padding":"`+strings.Repeat("x", 256)+`"}}`)))
+
+	_, _, err := ws.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "expected message too big close, got %v", err)
+	assert.Equal(t, tooLarge+1, metricValue(metricMessagesTooLarge))
+}
+
+func TestServe_NegotiatesCompression(t *testing.T) {
+	ws, res := dialTestServer(t, 64*1024, true)
+	rawOut, wireOut := metricValue(metricRawBytesOut), metricValue(metricWireBytesOut)
+
+	// The reply echoes the long and repetitive request id, so it compresses well
+	id := "req-" + strings.Repeat("a", 2048)
+	require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(`{"id":"`+id+`","type":"wallet","payload":{"client_id":1}}`)))
+
+	var message WsMessage
+	require.NoError(t, ws.ReadJSON(&message))
+	assert.Equal(t, id, message.ID)
+	assert.Contains(t, res.Header.Get("Sec-Websocket-Extensions"), "permessage-deflate")
+	require.Eventually(t, func() bool { return metricValue(metricWireBytesOut) > wireOut }, time.Second, 5*time.Millisecond)
+	assert.Greater(t, metricValue(metricRawBytesOut)-rawOut, int64(len(id)))
+	assert.Less(t, metricValue(metricWireBytesOut)-wireOut, metricValue(metricRawBytesOut)-rawOut)
+}
+
+func TestRoutes_ServeMetricsToAdminsOnly(t *testing.T) {
+	conf := *testConfig
+	conf.Admin.Token = "admin-secret"
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repository.NewMemoryRepository(), conf.Game), &conf, newDice, nil, nil)
+	routes := s.routes()
+
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		expectedStatus int
+	}{
+		{name: "admin", path: "/admin/metrics", token: "admin-secret", expectedStatus: http.StatusOK},
+		{name: "no_token", path: "/admin/metrics", expectedStatus: http.StatusUnauthorized},
+		{name: "wrong_token", path: "/admin/metrics", token: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "default_mux", path: "/debug/vars", token: "admin-secret", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			res := httptest.NewRecorder()
+			routes.ServeHTTP(res, req)
+			assert.Equal(t, tt.expectedStatus, res.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, res.Body.String(), `"websocket"`)
+			}
+		})
+	}
+}
+
+func TestRoutes_MountFrontend(t *testing.T) {
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repository.NewMemoryRepository(), testConfig.Game), testConfig, newDice, nil, nil)
+	routes := s.routes()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "home_method", method: http.MethodPost, path: "/", expectedStatus: http.StatusMethodNotAllowed},
+		{name: "unknown_path", method: http.MethodGet, path: "/unknown", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			routes.ServeHTTP(res, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.expectedStatus, res.Code)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// announceAchievements pushes every achievement unlocked by a round to the player
+func (c *connection) announceAchievements(clientID int, achievements []domain.Achievement) {
+	for _, achievement := range achievements {
+		unlocked := domain.AchievementUnlockedResponse{ClientID: clientID, Achievement: c.localizer.Load().Achievement(achievement)}
+		if err := c.writeToChan(domain.MessageTypeAchievementUnlocked, unlocked); err != nil {
+			log.Printf("Error sending unlocked achievement: %v", err)
+		}
+	}
+}
+
+// handleAchievementsMessage lists every achievement along with the ones the player unlocked
+func (c *connection) handleAchievementsMessage(msg WsMessage) error {
+	var payload domain.AchievementsRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid achievements payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Achievements Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	achievements, err := c.service.ListAchievements(payload.ClientID)
+	if err != nil {
+		return err
+	}
+	for i, achievement := range achievements.Achievements {
+		achievements.Achievements[i] = c.localizer.Load().Achievement(achievement)
+	}
+	return c.reply(msg, domain.MessageTypeAchievements, achievements)
+}
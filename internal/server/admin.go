@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// requireAdmin only lets through requests carrying the admin token as a bearer token
+func (s *WebSocketServer) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !validToken(bearerToken(r), s.conf.Admin.Token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleVoidRound voids a settled round and pushes the corrected balance to the connections of its player
+func (s *WebSocketServer) handleVoidRound(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req domain.VoidRoundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeHTTPError(w, r, appErrors.NewInvalidInputError("Invalid void round payload"))
+		return
+	}
+
+	void, err := s.service.VoidRound(req)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	if !void.AlreadyVoided {
+		s.hub.pushSettled(void.PlayerID, domain.MessageTypeRoundVoided, void.Seq, void)
+	}
+	writeJSON(w, http.StatusOK, void)
+}
+
+// handlePresence lists the connected players with their connection count and idle time
+func (s *WebSocketServer) handlePresence(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, domain.PresenceResponse{Players: s.presence.online()})
+}
+
+// handlePlayerPresence reports whether a player is connected and when they were last seen
+func (s *WebSocketServer) handlePlayerPresence(w http.ResponseWriter, r *http.Request) {
+	playerID, err := pathPlayerID(r)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	presence, ok := s.presence.player(playerID)
+	if !ok {
+		s.writeHTTPError(w, r, appErrors.NewUserNotFoundError(fmt.Sprintf("Client ID %d has not connected since the server started", playerID)).WithDetail(appErrors.DetailNotConnected, playerID))
+		return
+	}
+	writeJSON(w, http.StatusOK, presence)
+}
+
+// handleIssuePlayerToken issues a token authenticating an existing player for the configured token lifetime
+func (s *WebSocketServer) handleIssuePlayerToken(w http.ResponseWriter, r *http.Request) {
+	playerID, err := pathPlayerID(r)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	if _, err := s.service.GetBetLimits(playerID); err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	expiresAt := time.Now().Add(s.conf.Server.AuthTokenTTL).Truncate(time.Second)
+	writeJSON(w, http.StatusOK, domain.PlayerToken{
+		PlayerID:  playerID,
+		Token:     signPlayerToken(s.conf.Server.AuthSecret, playerID, expiresAt),
+		ExpiresAt: expiresAt,
+	})
+}
+
+// handleGrantBonus grants bonus funds to an existing player
+func (s *WebSocketServer) handleGrantBonus(w http.ResponseWriter, r *http.Request) {
+	playerID, err := pathPlayerID(r)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	var req domain.GrantBonusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeHTTPError(w, r, appErrors.NewInvalidInputError("Invalid grant bonus payload"))
+		return
+	}
+	if _, err := s.service.GetBetLimits(playerID); err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+
+	bonus, err := s.service.GrantBonus(playerID, req.Amount, req.WageringMultiplier, time.Duration(req.ValidForSeconds)*time.Second)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bonus)
+}
+
+// handleCreatePromoCode creates a promo code players can redeem for free bets or bonus funds
+func (s *WebSocketServer) handleCreatePromoCode(w http.ResponseWriter, r *http.Request) {
+	var req domain.PromoCode
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeHTTPError(w, r, appErrors.NewInvalidInputError("Invalid promo code payload"))
+		return
+	}
+
+	promo, err := s.service.CreatePromoCode(req)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, promo)
+}
+
+// handleCreateTournament creates a tournament players can register to until it ends
+func (s *WebSocketServer) handleCreateTournament(w http.ResponseWriter, r *http.Request) {
+	var req domain.Tournament
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeHTTPError(w, r, appErrors.NewInvalidInputError("Invalid tournament payload"))
+		return
+	}
+
+	tournament, err := s.tournaments.CreateTournament(req)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, tournament)
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleGrantBonus(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 100)
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, testConfig.Game), testConfig, newDice, nil, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/players/{id}/bonus", s.handleGrantBonus)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "granted", path: "/admin/players/1/bonus", body: `{"amount":50,"wagering_multiplier":10,"valid_for_seconds":3600}`, expectedStatus: http.StatusOK, expectedBody: `"wagering_requirement":500`},
+		{name: "already_active", path: "/admin/players/1/bonus", body: `{"amount":50,"wagering_multiplier":10,"valid_for_seconds":3600}`, expectedStatus: http.StatusConflict},
+		{name: "invalid_validity", path: "/admin/players/1/bonus", body: `{"amount":50,"wagering_multiplier":10}`, expectedStatus: http.StatusConflict},
+		{name: "invalid_payload", path: "/admin/players/1/bonus", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "unknown_player", path: "/admin/players/2/bonus", body: `{"amount":50,"wagering_multiplier":10,"valid_for_seconds":3600}`, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			mux.ServeHTTP(res, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.expectedStatus, res.Code, res.Body.String())
+			assert.Contains(t, res.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestHandleCreatePromoCode(t *testing.T) {
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repository.NewMemoryRepository(), testConfig.Game), testConfig, newDice, nil, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/promos", s.handleCreatePromoCode)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "invalid_payload", body: `{`, expectedStatus: http.StatusBadRequest, expectedBody: `"code":1001`},
+		{name: "unknown_kind", body: `{"code":"welcome","kind":"cash","expires_at":"2100-01-01T00:00:00Z"}`, expectedStatus: http.StatusBadRequest, expectedBody: `"field":"kind"`},
+		{name: "free_bet_without_bet", body: `{"code":"welcome","kind":"free_bet","bet_amount":5,"expires_at":"2100-01-01T00:00:00Z"}`, expectedStatus: http.StatusBadRequest, expectedBody: `"field":"bet_type"`},
+		{name: "expired", body: `{"code":"welcome","kind":"bonus","bonus_amount":10,"expires_at":"2000-01-01T00:00:00Z"}`, expectedStatus: http.StatusBadRequest, expectedBody: `"field":"expires_at"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			mux.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/admin/promos", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.expectedStatus, res.Code, res.Body.String())
+			assert.Contains(t, res.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestHandleCreateTournament(t *testing.T) {
+	game := service.NewGameService(repository.NewMemoryRepository(), testConfig.Game)
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(game, testConfig, newDice, nil, service.NewTournamentService(nil, game))
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/tournaments", s.handleCreateTournament)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "invalid_payload", body: `{`, expectedStatus: http.StatusBadRequest, expectedBody: `"code":1001`},
+		{name: "missing_name", body: `{"starting_stack":1000,"ends_at":"2100-01-01T00:00:00Z"}`, expectedStatus: http.StatusBadRequest, expectedBody: `"field":"name"`},
+		{name: "unknown_ranking", body: `{"name":"Weekly","starting_stack":1000,"ranking":"luck","ends_at":"2100-01-01T00:00:00Z"}`, expectedStatus: http.StatusBadRequest, expectedBody: `"field":"ranking"`},
+		{name: "shares_above_pool", body: `{"name":"Weekly","starting_stack":1000,"prize_shares":[0.7,0.4],"ends_at":"2100-01-01T00:00:00Z"}`, expectedStatus: http.StatusBadRequest, expectedBody: `"field":"prize_shares"`},
+		{name: "ended", body: `{"name":"Weekly","starting_stack":1000,"ends_at":"2000-01-01T00:00:00Z"}`, expectedStatus: http.StatusBadRequest, expectedBody: `"field":"ends_at"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			mux.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/admin/tournaments", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.expectedStatus, res.Code, res.Body.String())
+			assert.Contains(t, res.Body.String(), tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/service"
+)
+
+// lockedDice shares one dice between the concurrent requests of the REST API
+type lockedDice struct {
+	mu   sync.Mutex
+	dice service.DiceRoller
+}
+
+func (d *lockedDice) Roll() (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dice.Roll()
+}
+
+// registerAPI exposes the wallet, play, end play and history operations over plain HTTP
+func (s *WebSocketServer) registerAPI(mux *http.ServeMux) {
+	dice := &lockedDice{dice: s.newDice()}
+	mux.HandleFunc("GET /api/v1/players/{id}/wallet", s.authenticate(s.handleAPIWallet))
+	mux.HandleFunc("POST /api/v1/players/{id}/play", s.authenticate(s.handleAPIPlay(dice)))
+	mux.HandleFunc("POST /api/v1/players/{id}/endplay", s.authenticate(s.handleAPIEndPlay))
+	mux.HandleFunc("GET /api/v1/players/{id}/history", s.authenticate(s.handleAPIHistory))
+	mux.HandleFunc("GET /api/v1/errors", handleErrorCodes)
+}
+
+// handleAPIWallet replies with the balances of the player
+func (s *WebSocketServer) handleAPIWallet(w http.ResponseWriter, r *http.Request) {
+	playerID, err := pathPlayerID(r)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	balance, err := s.service.GetBalance(playerID)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, balance)
+}
+
+// handleAPIPlay settles a bet of the player through the same reality check and announcements as WebSocket plays,
+// unlocked achievements are pushed to their open connections
+func (s *WebSocketServer) handleAPIPlay(dice service.DiceRoller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID, err := pathPlayerID(r)
+		if err != nil {
+			s.writeHTTPError(w, r, err)
+			return
+		}
+		var req domain.PlayRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeHTTPError(w, r, appErrors.NewInvalidInputError("Invalid play payload"))
+			return
+		}
+		req.ClientID = playerID
+
+		log.Printf("Handling API Play request id '%s' for User ID: %d", r.Header.Get(requestIDHeader), playerID)
+
+		check := s.checks.get(playerID)
+		if check.isPending() {
+			s.writeHTTPError(w, r, appErrors.NewRealityCheckPendingError(fmt.Sprintf("Client ID %d must acknowledge the reality check before playing.", playerID)).WithDetail(appErrors.DetailRealityCheck))
+			return
+		}
+
+		result, err := s.service.ProcessPlay(req, dice)
+		if err != nil {
+			s.writeHTTPError(w, r, err)
+			return
+		}
+		check.record(result.NetResult)
+		if result.JackpotWon > 0 {
+			announceJackpotWin(s.service, s.hub, result.JackpotWon)
+		}
+		for _, c := range s.hub.members(playerRoom(playerID)) {
+			c.announceAchievements(playerID, result.Achievements)
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// handleAPIEndPlay closes the game session of the player and replies with its summary
+func (s *WebSocketServer) handleAPIEndPlay(w http.ResponseWriter, r *http.Request) {
+	playerID, err := pathPlayerID(r)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	summary, err := s.service.EndPlay(playerID)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	if collected := summary.CollectedGamble; collected != nil {
+		s.hub.pushSettled(playerID, domain.MessageTypeGambleCollect, collected.Seq, *collected)
+	}
+	s.checks.reset(playerID)
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// handleAPIHistory replies with the latest rounds of the player, newest first
+func (s *WebSocketServer) handleAPIHistory(w http.ResponseWriter, r *http.Request) {
+	playerID, err := pathPlayerID(r)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil {
+			s.writeHTTPError(w, r, appErrors.NewInvalidInputError(fmt.Sprintf("Invalid history limit: %s", raw)))
+			return
+		}
+	}
+	history, err := s.service.GetHistory(playerID, limit)
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+// pathPlayerID reads the player id of the request path
+func pathPlayerID(r *http.Request) (int, error) {
+	playerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || playerID <= 0 {
+		return 0, appErrors.NewInvalidInputError(fmt.Sprintf("Invalid player id: %s", r.PathValue("id")))
+	}
+	return playerID, nil
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           int
+		expectedStatus int
+	}{
+		{name: "internal", code: appErrors.InternalErrorCode, expectedStatus: http.StatusInternalServerError},
+		{name: "dice_roll", code: appErrors.DiceRollErrorCode, expectedStatus: http.StatusInternalServerError},
+		{name: "invalid_input", code: appErrors.InvalidInputErrorCode, expectedStatus: http.StatusBadRequest},
+		{name: "user_not_found", code: appErrors.UserNotFoundErrorCode, expectedStatus: http.StatusNotFound},
+		{name: "forbidden", code: appErrors.ForbiddenErrorCode, expectedStatus: http.StatusForbidden},
+		{name: "insufficient_funds", code: appErrors.InsufficientFundsErrorCode, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "invalid_bet_amount", code: appErrors.InvalidBetAmountErrorCode, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "active_session", code: appErrors.ActiveSessionErrorCode, expectedStatus: http.StatusConflict},
+		{name: "reality_check_pending", code: appErrors.RealityCheckPendingErrorCode, expectedStatus: http.StatusConflict},
+		{name: "table_round", code: appErrors.TableRoundErrorCode, expectedStatus: http.StatusConflict},
+		{name: "tournament", code: appErrors.TournamentErrorCode, expectedStatus: http.StatusConflict},
+		{name: "bonus", code: appErrors.BonusErrorCode, expectedStatus: http.StatusConflict},
+		{name: "promo", code: appErrors.PromoErrorCode, expectedStatus: http.StatusConflict},
+		{name: "void", code: appErrors.VoidErrorCode, expectedStatus: http.StatusConflict},
+		{name: "gamble", code: appErrors.GambleErrorCode, expectedStatus: http.StatusConflict},
+		{name: "unknown", code: 9999, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedStatus, httpStatus(tt.code))
+		})
+	}
+}
+
+// newAPITestServer serves the REST API backed by an in memory repository where player 1 holds 250, every roll is a 1
+func newAPITestServer(t *testing.T, conf *config.Config) (*WebSocketServer, *httptest.Server) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 250)
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, conf.Game), conf, newDice, nil, nil)
+	dice := &lockedDice{dice: newDice()}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/players/{id}/wallet", s.authenticate(s.handleAPIWallet))
+	mux.HandleFunc("POST /api/v1/players/{id}/play", s.authenticate(s.handleAPIPlay(dice)))
+	mux.HandleFunc("POST /api/v1/players/{id}/endplay", s.authenticate(s.handleAPIEndPlay))
+	mux.HandleFunc("GET /api/v1/players/{id}/history", s.authenticate(s.handleAPIHistory))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+// doAPI sends a request as player 1 and returns the response status and body
+func doAPI(t *testing.T, method, url, reqBody string) (int, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(reqBody))
+	require.NoError(t, err)
+	req.Header = authHeader(1)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(body)
+}
+
+func TestAPIHandlers(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "wallet", method: http.MethodGet, path: "/api/v1/players/1/wallet", expectedStatus: http.StatusOK, expectedBody: `"balance":250`},
+		{name: "play", method: http.MethodPost, path: "/api/v1/players/1/play", body: `{"bet_amount":10,"bet_type":"even"}`, expectedStatus: http.StatusOK, expectedBody: `"dice_result":1`},
+		{name: "play_invalid_payload", method: http.MethodPost, path: "/api/v1/players/1/play", body: `{`, expectedStatus: http.StatusBadRequest, expectedBody: `"code":1001`},
+		{name: "play_invalid_bet_amount", method: http.MethodPost, path: "/api/v1/players/1/play", body: `{"bet_amount":1000,"bet_type":"even"}`, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "end_play_without_session", method: http.MethodPost, path: "/api/v1/players/1/endplay", expectedStatus: http.StatusConflict},
+		{name: "history", method: http.MethodGet, path: "/api/v1/players/1/history?limit=5", expectedStatus: http.StatusOK, expectedBody: `"client_id":1`},
+		{name: "history_invalid_limit", method: http.MethodGet, path: "/api/v1/players/1/history?limit=x", expectedStatus: http.StatusBadRequest},
+		{name: "other_player", method: http.MethodGet, path: "/api/v1/players/2/wallet", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, server := newAPITestServer(t, testConfig)
+			status, body := doAPI(t, tt.method, server.URL+tt.path, tt.body)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			assert.Contains(t, body, tt.expectedBody)
+		})
+	}
+}
+
+func TestAPIPlay_RealityCheckPending(t *testing.T) {
+	conf := *testConfig
+	conf.RealityCheck.Interval = 20 * time.Millisecond
+	s, server := newAPITestServer(t, &conf)
+
+	status, body := doAPI(t, http.MethodPost, server.URL+"/api/v1/players/1/play", `{"bet_amount":10,"bet_type":"even"}`)
+	require.Equal(t, http.StatusOK, status, body)
+	require.Eventually(t, s.checks.get(1).isPending, time.Second, 5*time.Millisecond)
+
+	status, body = doAPI(t, http.MethodPost, server.URL+"/api/v1/players/1/play", `{"bet_amount":10,"bet_type":"even"}`)
+	assert.Equal(t, http.StatusConflict, status)
+	var gameErr appErrors.GameError
+	require.NoError(t, json.Unmarshal([]byte(body), &gameErr))
+	assert.Equal(t, appErrors.RealityCheckPendingErrorCode, gameErr.Code)
+
+	s.checks.get(1).acknowledge()
+	status, _ = doAPI(t, http.MethodPost, server.URL+"/api/v1/players/1/play", `{"bet_amount":10,"bet_type":"even"}`)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestAPIPlay_AnnouncesToConnectionsOfPlayer(t *testing.T) {
+	s, server := newAPITestServer(t, testConfig)
+	conn := s.newConnection(1, protocols[len(protocols)-1], s.catalog.Negotiate(""))
+	conn.identify()
+
+	// An exact hit unlocks an achievement
+	status, body := doAPI(t, http.MethodPost, server.URL+"/api/v1/players/1/play", `{"bet_amount":10,"bet_type":"exact","bet_number":1}`)
+	require.Equal(t, http.StatusOK, status, body)
+
+	select {
+	case message := <-conn.messagesChan:
+		assert.Equal(t, domain.MessageTypeAchievementUnlocked, message.Type)
+		var unlocked domain.AchievementUnlockedResponse
+		require.NoError(t, json.Unmarshal(message.Payload, &unlocked))
+		assert.Equal(t, domain.AchievementFirstExactHit, unlocked.Achievement.ID)
+	default:
+		t.Fatal("unlocked achievement was not pushed")
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// playerTokenCookie carries the player token of browsers, which cannot set headers on WebSocket and EventSource requests
+const playerTokenCookie = "spicy_dice_token"
+
+// playerContextKey stores the id of the authenticated player in the request context
+type playerContextKey struct{}
+
+// signPlayerToken issues the token authenticating a player until expiresAt, formatted as <player id>.<expiry>.<signature>
+func signPlayerToken(secret string, playerID int, expiresAt time.Time) string {
+	claims := fmt.Sprintf("%d.%d", playerID, expiresAt.Unix())
+	return claims + "." + tokenSignature(secret, claims)
+}
+
+// verifyPlayerToken returns the player a token was issued to, forged and expired tokens and an empty secret are refused
+func verifyPlayerToken(secret, token string, now time.Time) (int, bool) {
+	if secret == "" {
+		return 0, false
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	claims := parts[0] + "." + parts[1]
+	if !validToken(parts[2], tokenSignature(secret, claims)) {
+		return 0, false
+	}
+	playerID, err := strconv.Atoi(parts[0])
+	if err != nil || playerID <= 0 {
+		return 0, false
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() >= expiresAt {
+		return 0, false
+	}
+	return playerID, true
+}
+
+// tokenSignature signs the claims of a player token with the secret
+func tokenSignature(secret, claims string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(claims))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// requestToken reads the player token of a request from its bearer token, falling back to the token cookie
+// Tokens are never read from the query string, which ends up in access logs
+func requestToken(r *http.Request) string {
+	if token := bearerToken(r); token != "" {
+		return token
+	}
+	if cookie, err := r.Cookie(playerTokenCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// authenticate guards the player facing endpoints with player tokens, every request is refused while AUTH_SECRET is unset
+// Endpoints with a player id in their path only accept the token of that player
+func (s *WebSocketServer) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID, ok := verifyPlayerToken(s.conf.Server.AuthSecret, requestToken(r), time.Now())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.PathValue("id") != "" {
+			if pathID, err := pathPlayerID(r); err != nil || pathID != playerID {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), playerContextKey{}, playerID)))
+	}
+}
+
+// authenticatedPlayer returns the id of the player authenticated for the request, zero outside authenticate
+func authenticatedPlayer(r *http.Request) int {
+	playerID, _ := r.Context().Value(playerContextKey{}).(int)
+	return playerID
+}
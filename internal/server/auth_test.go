@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyPlayerToken(t *testing.T) {
+	now := time.Now()
+	valid := signPlayerToken("secret", 7, now.Add(time.Hour))
+
+	tests := []struct {
+		name       string
+		secret     string
+		token      string
+		expectedID int
+		expectedOK bool
+	}{
+		{name: "valid", secret: "secret", token: valid, expectedID: 7, expectedOK: true},
+		{name: "empty_secret", secret: "", token: signPlayerToken("", 7, now.Add(time.Hour))},
+		{name: "other_secret", secret: "other", token: valid},
+		{name: "expired", secret: "secret", token: signPlayerToken("secret", 7, now.Add(-time.Second))},
+		{name: "other_player", secret: "secret", token: "8" + valid[1:]},
+		{name: "malformed", secret: "secret", token: "7"},
+		{name: "empty", secret: "secret", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playerID, ok := verifyPlayerToken(tt.secret, tt.token, now)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedID, playerID)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, testConfig.Game), testConfig, newDice, nil, nil)
+	mux := http.NewServeMux()
+	echoPlayer := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, authenticatedPlayer(r))
+	}
+	mux.HandleFunc("GET /players/{id}", s.authenticate(echoPlayer))
+	mux.HandleFunc("GET /ws", s.authenticate(echoPlayer))
+	token := signPlayerToken(testConfig.Server.AuthSecret, 1, time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name           string
+		path           string
+		header         string
+		cookie         string
+		expectedStatus int
+	}{
+		{name: "bearer", path: "/players/1", header: "Bearer " + token, expectedStatus: http.StatusOK},
+		{name: "cookie", path: "/ws", cookie: token, expectedStatus: http.StatusOK},
+		{name: "other_player", path: "/players/2", header: "Bearer " + token, expectedStatus: http.StatusForbidden},
+		{name: "query_token", path: "/ws?token=" + token, expectedStatus: http.StatusUnauthorized},
+		{name: "missing", path: "/ws", expectedStatus: http.StatusUnauthorized},
+		{name: "forged", path: "/ws", header: "Bearer 1.9999999999.forged", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: playerTokenCookie, Value: tt.cookie})
+			}
+			res := httptest.NewRecorder()
+			mux.ServeHTTP(res, req)
+			require.Equal(t, tt.expectedStatus, res.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, "1", res.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthenticate_FailsClosedWithoutSecret(t *testing.T) {
+	conf := *testConfig
+	conf.Server = config.ServerConfig{}
+	repo := repository.NewMemoryRepository()
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, conf.Game), &conf, newDice, nil, nil)
+	handler := s.authenticate(func(w http.ResponseWriter, r *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Authorization", "Bearer "+signPlayerToken("", 1, time.Now().Add(time.Hour)))
+	res := httptest.NewRecorder()
+	handler(res, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
+
+func TestHandleIssuePlayerToken(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 100)
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, testConfig.Game), testConfig, newDice, nil, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/players/{id}/token", s.handleIssuePlayerToken)
+
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/admin/players/1/token", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	var issued domain.PlayerToken
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &issued))
+	playerID, ok := verifyPlayerToken(testConfig.Server.AuthSecret, issued.Token, time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, 1, playerID)
+
+	res = httptest.NewRecorder()
+	mux.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/admin/players/2/token", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/service"
+)
+
+// handleAutoplayMessage validates an autoplay request and starts the series in the background
+// Only one series can run per connection at a time
+func (c *connection) handleAutoplayMessage(msg WsMessage) error {
+	var payload domain.AutoplayRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid autoplay payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Autoplay Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	if err := c.service.ValidateAutoplay(payload); err != nil {
+		return err
+	}
+	if c.realityCheck.isPending() {
+		return appErrors.NewRealityCheckPendingError(fmt.Sprintf("Client ID %d must acknowledge the reality check before playing.", payload.ClientID)).WithDetail(appErrors.DetailRealityCheck)
+	}
+
+	c.autoplayMu.Lock()
+	defer c.autoplayMu.Unlock()
+	if c.stopAutoplay != nil {
+		return appErrors.NewActiveSessionError(fmt.Sprintf("Client ID %d already has an autoplay in progress.", payload.ClientID)).WithDetail(appErrors.DetailAutoplayRunning, payload.ClientID)
+	}
+	stop := make(chan struct{})
+	c.stopAutoplay = stop
+
+	go c.runAutoplay(msg, payload, stop)
+	return nil
+}
+
+// handleStopAutoplayMessage stops the running series, which reports the stop through an autoplay_end message
+func (c *connection) handleStopAutoplayMessage(msg WsMessage) error {
+	var payload domain.StopAutoplayRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid stop autoplay payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Stop Autoplay Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	if !c.cancelAutoplay() {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("Client ID %d does not have an autoplay in progress.", payload.ClientID)).WithDetail(appErrors.DetailAutoplayIdle, payload.ClientID)
+	}
+	return nil
+}
+
+// runAutoplay plays every round through ProcessPlay, streaming each result under the id of the autoplay request
+// until the series completes, a stop condition is met or the player stops it
+func (c *connection) runAutoplay(msg WsMessage, req domain.AutoplayRequest, stop chan struct{}) {
+	end := domain.AutoplayEndResponse{ClientID: req.ClientID, Reason: domain.AutoplayCompleted}
+	defer func() {
+		c.autoplayMu.Lock()
+		c.stopAutoplay = nil
+		c.autoplayMu.Unlock()
+		if err := c.reply(msg, domain.MessageTypeAutoplayEnd, c.localizer.Load().AutoplayEnd(end)); err != nil {
+			log.Printf("Error sending autoplay end: %v", err)
+		}
+	}()
+
+	for round := 1; round <= req.Rounds; round++ {
+		if round > 1 {
+			select {
+			case <-stop:
+				end.Reason = domain.AutoplayStopped
+				return
+			case <-c.doneChan:
+				return
+			case <-time.After(c.autoplayRoundDelay):
+			}
+		}
+		if c.realityCheck.isPending() {
+			end.Reason = domain.AutoplayRealityCheck
+			return
+		}
+
+		result, err := c.playAutoplayRound(req, round)
+		if err != nil {
+			log.Printf("Error playing autoplay round %d for User ID %d: %v", round, req.ClientID, err)
+			if err := c.replyError(msg, err); err != nil {
+				log.Printf("Error sending autoplay error: %v", err)
+			}
+			end.Reason = domain.AutoplayError
+			return
+		}
+
+		end.RoundsPlayed = round
+		end.NetResult += result.NetResult
+		if err := c.replySettled(msg, domain.MessageTypeAutoplayRound, result.Seq, domain.AutoplayRoundResponse{Round: round, PlayResponse: result}); err != nil {
+			log.Printf("Error sending autoplay round: %v", err)
+		}
+
+		if reason := service.AutoplayStopReason(req, end.NetResult, result); reason != "" {
+			end.Reason = reason
+			return
+		}
+	}
+}
+
+// playAutoplayRound settles a single round, appending it to the open session of the player
+func (c *connection) playAutoplayRound(req domain.AutoplayRequest, round int) (domain.PlayResponse, error) {
+	play := req.PlayRequest()
+	play.AutoplayRound = round
+	result, err := c.service.ProcessPlay(play, c.dice)
+	if err != nil {
+		return domain.PlayResponse{}, err
+	}
+	c.realityCheck.record(result.NetResult)
+	if result.JackpotWon > 0 {
+		announceJackpotWin(c.service, c.hub, result.JackpotWon)
+	}
+	c.announceAchievements(req.ClientID, result.Achievements)
+	return result, nil
+}
+
+// isAutoplayRunning reports whether an autoplay series is in progress on this connection
+func (c *connection) isAutoplayRunning() bool {
+	c.autoplayMu.Lock()
+	defer c.autoplayMu.Unlock()
+	return c.stopAutoplay != nil
+}
+
+// cancelAutoplay signals the running series to stop, returning false when none is running
+func (c *connection) cancelAutoplay() bool {
+	c.autoplayMu.Lock()
+	defer c.autoplayMu.Unlock()
+	if c.stopAutoplay == nil {
+		return false
+	}
+	select {
+	case <-c.stopAutoplay:
+	default:
+		close(c.stopAutoplay)
+	}
+	return true
+}
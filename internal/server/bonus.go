@@ -0,0 +1,23 @@
+package server
+
+import (
+	"log"
+	"time"
+)
+
+// forfeitExpiredBonuses periodically forfeits the bonuses that expired before being wagered through
+func (s *WebSocketServer) forfeitExpiredBonuses(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		forfeited, err := s.service.ForfeitExpiredBonuses()
+		if err != nil {
+			log.Printf("Error forfeiting expired bonuses: %v", err)
+			continue
+		}
+		if forfeited > 0 {
+			log.Printf("Forfeited %d expired bonus(es)", forfeited)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// codec encodes the frames of a connection. Handlers keep working with JSON payloads,
+// binary codecs carry the same document so every domain type keeps its field names and structure
+type codec interface {
+	// name is the suffix of the subprotocols selecting the codec, empty for the default
+	name() string
+	// frameType is the WebSocket message type of the frames
+	frameType() int
+	marshal(msg WsMessage) ([]byte, error)
+	unmarshal(data []byte, msg *WsMessage) error
+}
+
+// codecs lists the frame codecs offered during the upgrade, in the server's order of preference
+var codecs = []codec{jsonCodec{}, msgpackCodec{}}
+
+// codecSubprotocol names the subprotocol selecting a protocol version and a codec, e.g. spicydice.v1.msgpack
+func codecSubprotocol(version int, c codec) string {
+	if c.name() == "" {
+		return fmt.Sprintf("%s%d", subprotocolPrefix, version)
+	}
+	return fmt.Sprintf("%s%d.%s", subprotocolPrefix, version, c.name())
+}
+
+// splitSubprotocol separates the protocol version of a negotiated subprotocol from its codec
+func splitSubprotocol(subprotocol string) (string, codec) {
+	version, name, _ := strings.Cut(strings.TrimPrefix(subprotocol, subprotocolPrefix), ".")
+	for _, c := range codecs {
+		if c.name() == name {
+			return version, c
+		}
+	}
+	return version, jsonCodec{}
+}
+
+// jsonCodec sends the messages as JSON text frames
+type jsonCodec struct{}
+
+func (jsonCodec) name() string {
+	return ""
+}
+
+func (jsonCodec) frameType() int {
+	return websocket.TextMessage
+}
+
+func (jsonCodec) marshal(msg WsMessage) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (jsonCodec) unmarshal(data []byte, msg *WsMessage) error {
+	return json.Unmarshal(data, msg)
+}
+
+// msgpackCodec sends the messages as MessagePack binary frames, with the payloads keyed by their JSON field names
+type msgpackCodec struct{}
+
+// msgpackMessage is the MessagePack form of WsMessage
+type msgpackMessage struct {
+	ID      string             `msgpack:"id,omitempty"`
+	Seq     int64              `msgpack:"seq,omitempty"`
+	Type    domain.MessageType `msgpack:"type"`
+	Payload interface{}        `msgpack:"payload"`
+}
+
+func (msgpackCodec) name() string {
+	return "msgpack"
+}
+
+func (msgpackCodec) frameType() int {
+	return websocket.BinaryMessage
+}
+
+func (msgpackCodec) marshal(msg WsMessage) ([]byte, error) {
+	var payload interface{}
+	if len(msg.Payload) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(msg.Payload))
+		decoder.UseNumber()
+		if err := decoder.Decode(&payload); err != nil {
+			return nil, fmt.Errorf("error decoding %s payload: %w", msg.Type, err)
+		}
+	}
+	return msgpack.Marshal(msgpackMessage{ID: msg.ID, Seq: msg.Seq, Type: msg.Type, Payload: msgpackValue(payload)})
+}
+
+func (msgpackCodec) unmarshal(data []byte, msg *WsMessage) error {
+	var decoded msgpackMessage
+	if err := msgpack.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(decoded.Payload)
+	if err != nil {
+		return fmt.Errorf("error encoding %s payload: %w", decoded.Type, err)
+	}
+	*msg = WsMessage{ID: decoded.ID, Seq: decoded.Seq, Type: decoded.Type, Payload: payload}
+	return nil
+}
+
+// msgpackValue converts the numbers of a decoded JSON document so integers are sent as MessagePack integers
+func msgpackValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = msgpackValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = msgpackValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// codecPayloads lists every payload sent over the wire: each domain struct and the error
+var codecPayloads = []interface{}{
+	domain.WalletRequest{}, domain.WalletResponse{}, domain.PlayerBonus{}, domain.PlayRequest{}, domain.PlayResponse{},
+	domain.EndPlayResponse{}, domain.EndPlayRequest{}, domain.LimitsRequest{}, domain.LimitsResponse{},
+	domain.TierLimits{}, domain.JackpotResponse{}, domain.AutoplayRequest{}, domain.AutoplayRoundResponse{},
+	domain.AutoplayEndResponse{}, domain.StopAutoplayRequest{}, domain.TableJoinRequest{}, domain.TableLeaveRequest{},
+	domain.TableLeaveResponse{}, domain.TableBetRequest{}, domain.TableBetResponse{}, domain.TableBet{},
+	domain.TableBetResult{}, domain.TableRound{}, domain.TableRoundResponse{}, domain.TableSettlementResponse{},
+	domain.FreeBet{}, domain.RedeemPromoRequest{}, domain.RedeemPromoResponse{}, domain.FreeBetsRequest{},
+	domain.FreeBetsResponse{}, domain.Gamble{}, domain.GambleRequest{}, domain.GambleResponse{},
+	domain.GambleCollectRequest{}, domain.Achievement{}, domain.PlayerStats{}, domain.AchievementsRequest{},
+	domain.AchievementsResponse{}, domain.AchievementUnlockedResponse{}, domain.HelloRequest{}, domain.HelloResponse{},
+	domain.SettlementMessage{}, domain.AckRequest{}, domain.AckResponse{}, domain.Tournament{}, domain.TournamentEntry{},
+	domain.TournamentPrize{}, domain.TournamentsResponse{}, domain.TournamentRegisterRequest{},
+	domain.TournamentRegisterResponse{}, domain.TournamentPlayRequest{}, domain.TournamentPlayResponse{},
+	domain.LeaderboardRequest{}, domain.LeaderboardEntry{}, domain.LeaderboardResponse{}, domain.RealityCheckResponse{},
+	domain.RealityCheckAckRequest{}, domain.RealityCheckAckResponse{}, domain.GameSession{}, domain.Round{},
+	domain.HistoryResponse{}, domain.GrantBonusRequest{}, domain.PromoCode{}, domain.VoidRoundRequest{}, domain.RoundVoid{}, domain.PlayerPresence{},
+	domain.PlayerToken{}, domain.PresenceResponse{}, domain.SessionSummary{}, domain.PlayTransaction{},
+	domain.PlaySettlement{}, domain.BalanceUpdate{},
+	appErrors.GameError{},
+}
+
+// fill sets every field of v to a non zero value so the round trip covers all of them
+// Floats get a fraction and integers stay integers, the two number forms a binary codec could confuse
+func fill(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(7)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(9.6)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0))
+	case reflect.Map:
+		key, value := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fill(key)
+		fill(value)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, value)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	}
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	// Every message type and every payload appear in at least one case
+	messageTypes := domain.MessageTypes()
+	cases := max(len(messageTypes), len(codecPayloads))
+
+	for _, c := range codecs {
+		for i := 0; i < cases; i++ {
+			msgType := messageTypes[i%len(messageTypes)]
+			payloadType := reflect.TypeOf(codecPayloads[i%len(codecPayloads)])
+			t.Run(codecName(c)+"/"+string(msgType)+"/"+payloadType.Name(), func(t *testing.T) {
+				sent := reflect.New(payloadType)
+				fill(sent.Elem())
+				payload, err := json.Marshal(sent.Interface())
+				require.NoError(t, err)
+				msg := WsMessage{ID: "req-1", Seq: 7, Type: msgType, Payload: payload}
+
+				data, err := c.marshal(msg)
+				require.NoError(t, err)
+				var decoded WsMessage
+				require.NoError(t, c.unmarshal(data, &decoded))
+
+				assert.Equal(t, msg.ID, decoded.ID)
+				assert.Equal(t, msg.Seq, decoded.Seq)
+				assert.Equal(t, msg.Type, decoded.Type)
+				// Fields hidden from JSON are dropped by both sides, so the payload is compared once decoded
+				expected, result := reflect.New(payloadType).Interface(), reflect.New(payloadType).Interface()
+				require.NoError(t, json.Unmarshal(payload, expected))
+				require.NoError(t, json.Unmarshal(decoded.Payload, result))
+				assert.Equal(t, expected, result)
+			})
+		}
+	}
+}
+
+func TestCodecPayloads_CoverEveryDomainStruct(t *testing.T) {
+	listed := make(map[string]bool)
+	for _, payload := range codecPayloads {
+		listed[reflect.TypeOf(payload).Name()] = true
+	}
+
+	packages, err := parser.ParseDir(token.NewFileSet(), "../domain", nil, 0)
+	require.NoError(t, err)
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					if _, isStruct := typeSpec.Type.(*ast.StructType); isStruct && typeSpec.Name.IsExported() {
+						assert.True(t, listed[typeSpec.Name.Name], "codecPayloads lacks domain.%s", typeSpec.Name.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSplitSubprotocol(t *testing.T) {
+	tests := []struct {
+		subprotocol     string
+		expectedVersion string
+		expectedCodec   codec
+	}{
+		{subprotocol: "spicydice.v1", expectedVersion: "1", expectedCodec: jsonCodec{}},
+		{subprotocol: "spicydice.v1.msgpack", expectedVersion: "1", expectedCodec: msgpackCodec{}},
+		{subprotocol: "", expectedVersion: "", expectedCodec: jsonCodec{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subprotocol, func(t *testing.T) {
+			version, c := splitSubprotocol(tt.subprotocol)
+			assert.Equal(t, tt.expectedVersion, version)
+			assert.Equal(t, tt.expectedCodec, c)
+		})
+	}
+
+	for _, version := range supportedVersions() {
+		for _, c := range codecs {
+			_, split := splitSubprotocol(codecSubprotocol(version, c))
+			assert.Equal(t, c, split)
+		}
+	}
+}
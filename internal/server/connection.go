@@ -2,26 +2,53 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Desgue/SpicyDice/internal/appErrors"
 	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/i18n"
 	"github.com/Desgue/SpicyDice/internal/service"
 	"github.com/gorilla/websocket"
 )
 
 // connection implements concurrent safe bidirectional communication
 // using separate read/write goroutines with proper cleanup mechanisms
+// Connections of the SSE transport have no websocket, their messages are streamed by handleSSEStream
+// Every connection is bound to the player its request was authenticated as
 type connection struct {
+	playerID     int
 	service      *service.GameService
+	tables       *service.TableService
+	tournaments  *service.TournamentService
+	dice         service.DiceRoller
+	hub          *hub
 	ws           *websocket.Conn
+	codec        codec
 	mu           sync.Mutex
+	dispatchMu   sync.Mutex
 	messagesChan chan (WsMessage)
 	doneChan     chan (struct{})
 	closeOnce    sync.Once
+	redelivered  sync.Once
+	realityCheck *realityCheck
+	checks       *realityChecks
+	protocol     atomic.Pointer[protocol]
+	features     []string
+	catalog      *i18n.Catalog
+	localizer    atomic.Pointer[i18n.Localizer]
+	presence     *presence
+	pingInterval time.Duration
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+
+	autoplayMu         sync.Mutex
+	stopAutoplay       chan (struct{})
+	autoplayRoundDelay time.Duration
 }
 
 // readPump maintains the read side of the websocket, implementing ping/pong heartbeat
@@ -33,29 +60,48 @@ func (c *connection) readPump() {
 	}()
 
 	c.ws.SetPongHandler(func(string) error {
-		return c.ws.SetReadDeadline(time.Now().Add(readTimeout))
+		c.presence.touch(c)
+		return c.ws.SetReadDeadline(time.Now().Add(c.readTimeout))
 	})
 
 	for {
-		c.ws.SetReadDeadline(time.Now().Add(readTimeout))
-		var message WsMessage
-		err := c.ws.ReadJSON(&message)
+		c.ws.SetReadDeadline(time.Now().Add(c.readTimeout))
+		_, data, err := c.ws.ReadMessage()
+		if errors.Is(err, websocket.ErrReadLimit) {
+			wsMetrics.Add(metricMessagesTooLarge, 1)
+			log.Printf("Closing connection sending a message over the size limit")
+			break
+		}
 		if err != nil {
 			log.Printf("Error reading message: %v", err)
 			break
 		}
-
-		if err = c.handleMessage(message); err != nil {
-			log.Printf("Error handling message type '%s': %v", message.Type, err)
-			c.writeToChan(domain.MessageTypeError, err)
+		wsMetrics.Add(metricRawBytesIn, int64(len(data)))
+		var message WsMessage
+		if err := c.codec.unmarshal(data, &message); err != nil {
+			log.Printf("Error decoding message: %v", err)
+			break
 		}
+		c.dispatch(message)
+	}
+}
+
+// dispatch handles one request at a time whatever the transport it arrived on, replying with the error it fails with
+// Every message counts as activity of the players of the connection
+func (c *connection) dispatch(message WsMessage) {
+	c.dispatchMu.Lock()
+	defer c.dispatchMu.Unlock()
+	defer c.presence.touch(c)
+	if err := c.handleMessage(message); err != nil {
+		log.Printf("Error handling message type '%s' id '%s': %v", message.Type, message.ID, err)
+		c.replyError(message, err)
 	}
 }
 
 // writePump handles the write side of the websocket with periodic ping messages
 // Uses mutex for concurrent write safety and implements graceful shutdown
 func (c *connection) writePump() {
-	ticker := time.NewTicker(tickDuration)
+	ticker := time.NewTicker(c.pingInterval)
 
 	defer func() {
 		log.Println("Closing connection from writePump routine...")
@@ -73,7 +119,7 @@ func (c *connection) writePump() {
 		select {
 		case <-ticker.C:
 			c.mu.Lock()
-			c.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+			c.ws.SetWriteDeadline(time.Now().Add(c.writeTimeout))
 			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
 				c.mu.Unlock()
 				log.Printf("Error sending ping: %v", err)
@@ -83,9 +129,16 @@ func (c *connection) writePump() {
 
 		case message := <-c.messagesChan:
 			c.mu.Lock()
-			c.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
-			if err := c.ws.WriteJSON(message); err != nil {
-				log.Printf("error writing json message: %s", err)
+			data, err := c.codec.marshal(message)
+			if err != nil {
+				log.Printf("error encoding message: %s", err)
+				c.mu.Unlock()
+				continue
+			}
+			wsMetrics.Add(metricRawBytesOut, int64(len(data)))
+			c.ws.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+			if err := c.ws.WriteMessage(c.codec.frameType(), data); err != nil {
+				log.Printf("error writing message: %s", err)
 				c.mu.Unlock()
 				return
 			}
@@ -101,32 +154,120 @@ func (c *connection) writePump() {
 // handleMessage routes incoming messages to their appropriate handlers based on message type
 // Returns domain specific errors for invalid messages or processing failures
 func (c *connection) handleMessage(msg WsMessage) error {
+	log.Printf("Received message type '%s' id '%s'", msg.Type, msg.ID)
+	payload, err := c.protocol.Load().codec.decode(msg.Type, msg.Payload)
+	if err != nil {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("Invalid %s payload: %v", msg.Type, err))
+	}
+	msg.Payload = payload
+	if err := c.authorize(msg); err != nil {
+		return err
+	}
 	switch msg.Type {
+	case domain.MessageTypeHello:
+		return c.handleHelloMessage(msg)
+	case domain.MessageTypeAck:
+		return c.handleAckMessage(msg)
 	case domain.MessageTypeWallet:
 		return c.handleWalletMessage(msg)
 	case domain.MessageTypePlay:
 		return c.handlePlayMessage(msg)
 	case domain.MessageTypeEndPlay:
 		return c.handleEndPlayMessage(msg)
+	case domain.MessageTypeRealityCheckAck:
+		return c.handleRealityCheckAckMessage(msg)
+	case domain.MessageTypeLimits:
+		return c.handleLimitsMessage(msg)
+	case domain.MessageTypeAutoplay:
+		return c.handleAutoplayMessage(msg)
+	case domain.MessageTypeStopAutoplay:
+		return c.handleStopAutoplayMessage(msg)
+	case domain.MessageTypeJackpot:
+		return c.handleJackpotMessage(msg)
+	case domain.MessageTypeTableJoin:
+		return c.handleTableJoinMessage(msg)
+	case domain.MessageTypeTableLeave:
+		return c.handleTableLeaveMessage(msg)
+	case domain.MessageTypeTableBet:
+		return c.handleTableBetMessage(msg)
+	case domain.MessageTypeTournaments:
+		return c.handleTournamentsMessage(msg)
+	case domain.MessageTypeTournamentRegister:
+		return c.handleTournamentRegisterMessage(msg)
+	case domain.MessageTypeTournamentPlay:
+		return c.handleTournamentPlayMessage(msg)
+	case domain.MessageTypeTournamentLeaderboard:
+		return c.handleTournamentLeaderboardMessage(msg)
+	case domain.MessageTypeRedeemPromo:
+		return c.handleRedeemPromoMessage(msg)
+	case domain.MessageTypeFreeBets:
+		return c.handleFreeBetsMessage(msg)
+	case domain.MessageTypeGamble:
+		return c.handleGambleMessage(msg)
+	case domain.MessageTypeGambleCollect:
+		return c.handleGambleCollectMessage(msg)
+	case domain.MessageTypeAchievements:
+		return c.handleAchievementsMessage(msg)
 	default:
 		return appErrors.NewInvalidInputError(fmt.Sprintf("Unknown message type: %s", msg.Type))
 	}
 }
 
+// identify subscribes the connection to the room of its player so server side changes to the player's account,
+// such as voided rounds, can be pushed to it, and marks the player online
+// A reality check still waiting for the player's acknowledgement is sent again
+func (c *connection) identify() {
+	c.hub.join(playerRoom(c.playerID), c)
+	c.presence.join(c)
+	if check, ok := c.realityCheck.pendingCheck(); ok {
+		c.sendRealityCheck(check)
+	}
+}
+
+// authorize refuses messages whose payload names another player than the one the connection is bound to
+// Handlers act on the connection's player whatever the payload names, so a payload naming no player is accepted
+func (c *connection) authorize(msg WsMessage) error {
+	var payload struct {
+		ClientID int `json:"client_id"`
+	}
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.ClientID == 0 || payload.ClientID == c.playerID {
+		return nil
+	}
+	return appErrors.NewForbiddenError(fmt.Sprintf("Connection is authenticated as client ID %d, not %d", c.playerID, payload.ClientID)).WithDetail(appErrors.DetailForbiddenPlayer, c.playerID, payload.ClientID)
+}
+
 // handleWalletMessage processes wallet related requests ensuring payload validity
 func (c *connection) handleWalletMessage(msg WsMessage) error {
 	var payload domain.WalletRequest
 	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 		return appErrors.NewInvalidInputError("Invalid wallet payload")
 	}
+	payload.ClientID = c.playerID
 
-	log.Printf("Handling Wallet Message for User ID: %d", payload.ClientID)
+	log.Printf("Handling Wallet Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
 
 	balance, err := c.service.GetBalance(payload.ClientID)
 	if err != nil {
 		return err
 	}
-	return c.writeToChan(domain.MessageTypeWallet, balance)
+	return c.reply(msg, domain.MessageTypeWallet, balance)
+}
+
+// handleLimitsMessage processes bet limits requests ensuring payload validity
+func (c *connection) handleLimitsMessage(msg WsMessage) error {
+	var payload domain.LimitsRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid limits payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Limits Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	limits, err := c.service.GetBetLimits(payload.ClientID)
+	if err != nil {
+		return err
+	}
+	return c.reply(msg, domain.MessageTypeLimits, limits)
 }
 
 // handlePlayMessage processes game play requests ensuring payload validity
@@ -135,15 +276,31 @@ func (c *connection) handlePlayMessage(msg WsMessage) error {
 	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 		return appErrors.NewInvalidInputError("Invalid play payload")
 	}
+	payload.ClientID = c.playerID
 
-	log.Printf("Handling Play Message for User ID: %d", payload.ClientID)
+	log.Printf("Handling Play Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
 
-	result, err := c.service.ProcessPlay(payload, service.Dice{Sides: 6})
+	if c.realityCheck.isPending() {
+		return appErrors.NewRealityCheckPendingError(fmt.Sprintf("Client ID %d must acknowledge the reality check before playing.", payload.ClientID)).WithDetail(appErrors.DetailRealityCheck)
+	}
+	if c.isAutoplayRunning() {
+		return appErrors.NewActiveSessionError(fmt.Sprintf("Client ID %d has an autoplay in progress.", payload.ClientID)).WithDetail(appErrors.DetailAutoplayRunning, payload.ClientID)
+	}
+
+	result, err := c.service.ProcessPlay(payload, c.dice)
 	if err != nil {
 		return err
 	}
+	c.realityCheck.record(result.NetResult)
+	if result.JackpotWon > 0 {
+		announceJackpotWin(c.service, c.hub, result.JackpotWon)
+	}
 
-	return c.writeToChan(domain.MessageTypePlay, result)
+	if err := c.replySettled(msg, domain.MessageTypePlay, result.Seq, result); err != nil {
+		return err
+	}
+	c.announceAchievements(payload.ClientID, result.Achievements)
+	return nil
 }
 
 // handleEndPlayMessage processes session end requests ensuring payload validity
@@ -152,25 +309,88 @@ func (c *connection) handleEndPlayMessage(msg WsMessage) error {
 	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 		return appErrors.NewInvalidInputError("Invalid end-play payload")
 	}
+	payload.ClientID = c.playerID
 
-	log.Printf("Handling End Play Message for User ID: %d", payload.ClientID)
+	log.Printf("Handling End Play Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	if c.isAutoplayRunning() {
+		return appErrors.NewActiveSessionError(fmt.Sprintf("Client ID %d has an autoplay in progress.", payload.ClientID)).WithDetail(appErrors.DetailAutoplayRunning, payload.ClientID)
+	}
 
 	endPlayResponse, err := c.service.EndPlay(payload.ClientID)
 	if err != nil {
 		return err
 	}
+	if collected := endPlayResponse.CollectedGamble; collected != nil {
+		c.hub.pushSettled(payload.ClientID, domain.MessageTypeGambleCollect, collected.Seq, *collected)
+	}
+	c.realityCheck.reset()
 
-	return c.writeToChan(domain.MessageTypeEndPlay, endPlayResponse)
+	return c.reply(msg, domain.MessageTypeEndPlay, endPlayResponse)
 }
 
-// Returns error if connection is closed or message buffer is full
+// handleRealityCheckAckMessage clears a pending reality check so the player can resume playing
+func (c *connection) handleRealityCheckAckMessage(msg WsMessage) error {
+	var payload domain.RealityCheckAckRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid reality check acknowledgement payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Reality Check Ack Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	c.realityCheck.acknowledge()
+	return c.reply(msg, domain.MessageTypeRealityCheckAck, domain.RealityCheckAckResponse{ClientID: payload.ClientID})
+}
+
+// sendRealityCheck pushes a reality check reminder to the client
+func (c *connection) sendRealityCheck(check domain.RealityCheckResponse) {
+	if err := c.writeToChan(domain.MessageTypeRealityCheck, c.localizer.Load().RealityCheck(check)); err != nil {
+		log.Printf("Error sending reality check: %v", err)
+	}
+}
+
+// reply queues the response to a request, echoing its id so clients pipelining requests can match them
+func (c *connection) reply(req WsMessage, msgType domain.MessageType, data interface{}) error {
+	return c.send(req.ID, msgType, data)
+}
+
+// replyError answers a request with the error it failed with, translated to the locale of the connection
+func (c *connection) replyError(req WsMessage, err error) error {
+	return c.reply(req, domain.MessageTypeError, c.localizer.Load().Error(appErrors.AsGameError(err)).WithRequestID(req.ID))
+}
+
+// writeToChan queues a server push under a server generated id
 func (c *connection) writeToChan(msgType domain.MessageType, data interface{}) error {
+	return c.send(c.hub.nextMessageID(), msgType, data)
+}
+
+// send queues a message with the given id
+func (c *connection) send(id string, msgType domain.MessageType, data interface{}) error {
+	return c.sendMessage(WsMessage{ID: id, Type: msgType}, data)
+}
+
+// sendMessage queues the envelope with data as its payload, encoded for the protocol version of the connection
+// Returns error if connection is closed or message buffer is full
+func (c *connection) sendMessage(msg WsMessage, data interface{}) error {
+	data, err := c.localize(msg.Type, data)
+	if err != nil {
+		return err
+	}
 	payload, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("error marshaling incomming message: %w", err)
 	}
+	if msg.Payload, err = c.protocol.Load().codec.encode(msg.Type, payload); err != nil {
+		return fmt.Errorf("error encoding %s message for protocol version %d: %w", msg.Type, c.protocol.Load().version, err)
+	}
 	select {
-	case c.messagesChan <- WsMessage{Type: msgType, Payload: payload}:
+	case <-c.doneChan:
+		return fmt.Errorf("connection closed")
+	default:
+	}
+	select {
+	case c.messagesChan <- msg:
 		return nil
 	case <-c.doneChan:
 		return fmt.Errorf("connection closed")
@@ -179,15 +399,47 @@ func (c *connection) writeToChan(msgType domain.MessageType, data interface{}) e
 	}
 }
 
+// localize translates the pushes shared by connections of different locales, so each copy is in the locale of its connection
+// Stored table_bet_settled payloads are decoded to be translated on every delivery
+func (c *connection) localize(msgType domain.MessageType, data interface{}) (interface{}, error) {
+	localizer := c.localizer.Load()
+	switch payload := data.(type) {
+	case domain.JackpotResponse:
+		return localizer.Jackpot(payload), nil
+	case domain.TableSettlementResponse:
+		if msgType == domain.MessageTypeTableBetSettled {
+			return localizer.TableBetSettled(payload), nil
+		}
+		return localizer.TableSettlement(payload), nil
+	case domain.LeaderboardResponse:
+		return localizer.Leaderboard(payload, c.playerID), nil
+	case json.RawMessage:
+		if msgType != domain.MessageTypeTableBetSettled {
+			return data, nil
+		}
+		var settlement domain.TableSettlementResponse
+		if err := json.Unmarshal(payload, &settlement); err != nil {
+			return nil, fmt.Errorf("error decoding %s message: %w", msgType, err)
+		}
+		return localizer.TableBetSettled(settlement), nil
+	}
+	return data, nil
+}
+
 // cleanUpOnce ensures connection cleanup happens only once
-// Uses sync.Once to prevent duplicate cleanup operations
+// Uses sync.Once to prevent duplicate cleanup operations, messagesChan is left open so late senders
+// never send on a closed channel and see doneChan instead
 func (c *connection) cleanUpOnce() {
 	c.closeOnce.Do(func() {
 		log.Println("Closing connection...")
+		c.hub.unregister(c)
+		c.presence.leave(c)
+		c.checks.release(c.playerID)
 		close(c.doneChan)
-		if err := c.ws.Close(); err != nil {
-			log.Printf("Error closing connection: %v", err)
+		if c.ws != nil {
+			if err := c.ws.Close(); err != nil {
+				log.Printf("Error closing connection: %v", err)
+			}
 		}
-		close(c.messagesChan)
 	})
 }
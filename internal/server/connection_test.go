@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnection_LocalizesPushes(t *testing.T) {
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repository.NewMemoryRepository(), testConfig.Game), testConfig, newDice, nil, nil)
+	proto := protocols[len(protocols)-1]
+	english := s.newConnection(1, proto, s.catalog.Negotiate("en"))
+	german := s.newConnection(2, proto, s.catalog.Negotiate("de"))
+	s.hub.register(english)
+	s.hub.register(german)
+
+	settlement := domain.TableSettlementResponse{TableID: 3, RoundID: 9, DiceResult: 4, Results: []domain.TableBetResult{
+		{TableBet: domain.TableBet{PlayerID: 1, BetAmount: 10, BetType: domain.Even}, Won: true, Payout: 20},
+	}}
+	stored, err := json.Marshal(settlement)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		msgType  domain.MessageType
+		data     interface{}
+		expected map[*connection]string
+	}{
+		{
+			name:     "jackpot",
+			msgType:  domain.MessageTypeJackpot,
+			data:     domain.JackpotResponse{Amount: 1500, LastWinAmount: 2000},
+			expected: map[*connection]string{english: "Jackpot of €2,000.00 won! New jackpot: €1,500.00", german: "Jackpot von 2.000,00 € geknackt! Neuer Jackpot: 1.500,00 €"},
+		},
+		{
+			name:     "table_settlement",
+			msgType:  domain.MessageTypeTableSettlement,
+			data:     settlement,
+			expected: map[*connection]string{english: "Table 3 rolled a 4", german: "Tisch 3 hat eine 4 gewürfelt"},
+		},
+		{
+			name:     "stored_table_bet_settled",
+			msgType:  domain.MessageTypeTableBetSettled,
+			data:     json.RawMessage(stored),
+			expected: map[*connection]string{english: "Table 3 rolled a 4, you won €10.00", german: "Tisch 3 hat eine 4 gewürfelt, du hast 10,00 € gewonnen"},
+		},
+		{
+			name:    "closed_leaderboard",
+			msgType: domain.MessageTypeTournamentLeaderboard,
+			data: domain.LeaderboardResponse{TournamentID: 5, Closed: true, Entries: []domain.LeaderboardEntry{
+				{Rank: 1, PlayerID: 1, Prize: 50},
+				{Rank: 2, PlayerID: 2},
+			}},
+			expected: map[*connection]string{english: "Tournament 5 has ended, you finished in place 1 and won €50.00", german: "Turnier 5 ist beendet, du bist auf Platz 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.hub.broadcast(tt.msgType, tt.data)
+			for c, expected := range tt.expected {
+				message := <-c.messagesChan
+				assert.Equal(t, tt.msgType, message.Type)
+				var payload struct {
+					Message string `json:"message"`
+				}
+				require.NoError(t, json.Unmarshal(message.Payload, &payload))
+				assert.Equal(t, expected, payload.Message)
+			}
+		})
+	}
+}
+
+func TestConnection_EchoesRequestIDs(t *testing.T) {
+	tests := []struct {
+		name         string
+		msg          WsMessage
+		expectedType domain.MessageType
+	}{
+		{name: "reply", msg: WsMessage{ID: "req-1", Type: domain.MessageTypeWallet, Payload: json.RawMessage(`{"client_id":1}`)}, expectedType: domain.MessageTypeWallet},
+		{name: "invalid_payload", msg: WsMessage{ID: "req-2", Type: domain.MessageTypePlay, Payload: json.RawMessage(`{"bet_amount":"ten"}`)}, expectedType: domain.MessageTypeError},
+		{name: "forbidden_player", msg: WsMessage{ID: "req-3", Type: domain.MessageTypeWallet, Payload: json.RawMessage(`{"client_id":2}`)}, expectedType: domain.MessageTypeError},
+		{name: "unknown_type", msg: WsMessage{ID: "req-4", Type: "unknown", Payload: json.RawMessage(`{}`)}, expectedType: domain.MessageTypeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			repo.AddPlayer(1, 250)
+			newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+			s := NewWebSocketServer(service.NewGameService(repo, testConfig.Game), testConfig, newDice, nil, nil)
+			conn := s.newConnection(1, protocols[len(protocols)-1], s.catalog.Negotiate(""))
+
+			conn.dispatch(tt.msg)
+
+			message := <-conn.messagesChan
+			assert.Equal(t, tt.msg.ID, message.ID)
+			require.Equal(t, tt.expectedType, message.Type)
+			if tt.expectedType == domain.MessageTypeError {
+				var gameErr appErrors.GameError
+				require.NoError(t, json.Unmarshal(message.Payload, &gameErr))
+				assert.Equal(t, tt.msg.ID, gameErr.RequestID)
+			}
+		})
+	}
+}
+
+func TestConnection_ActsAsAuthenticatedPlayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{name: "client_id_omitted", payload: `{}`},
+		{name: "client_id_zero", payload: `{"client_id":0}`},
+		{name: "client_id_matching", payload: `{"client_id":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			repo.AddPlayer(1, 250)
+			newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+			s := NewWebSocketServer(service.NewGameService(repo, testConfig.Game), testConfig, newDice, nil, nil)
+			conn := s.newConnection(1, protocols[len(protocols)-1], s.catalog.Negotiate(""))
+
+			conn.dispatch(WsMessage{Type: domain.MessageTypeWallet, Payload: json.RawMessage(tt.payload)})
+
+			message := <-conn.messagesChan
+			require.Equal(t, domain.MessageTypeWallet, message.Type)
+			var wallet domain.WalletResponse
+			require.NoError(t, json.Unmarshal(message.Payload, &wallet))
+			assert.Equal(t, 1, wallet.ClientID)
+			assert.Equal(t, 250.0, wallet.Balance)
+		})
+	}
+}
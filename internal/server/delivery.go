@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// replySettled replies with the outcome of a bet whose settlement message was stored along with the outcome
+func (c *connection) replySettled(req WsMessage, msgType domain.MessageType, seq int64, data interface{}) error {
+	return c.sendMessage(WsMessage{ID: req.ID, Seq: seq, Type: msgType}, data)
+}
+
+// pushSettled pushes a settlement message stored along with its outcome to every connection of the player
+func (h *hub) pushSettled(playerID int, msgType domain.MessageType, seq int64, data interface{}) {
+	h.broadcastRoomMessage(playerRoom(playerID), WsMessage{ID: h.nextMessageID(), Seq: seq, Type: msgType}, data)
+}
+
+// purgeSettlementMessages periodically drops the settlement messages kept for longer than the retention
+func (s *WebSocketServer) purgeSettlementMessages(interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := s.service.PurgeExpiredSettlements(retention)
+		if err != nil {
+			log.Printf("Error purging settlement messages: %v", err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("Purged %d expired settlement message(s)", purged)
+		}
+	}
+}
+
+// redeliver sends the settlement messages the player has not acknowledged, under their original sequence numbers
+func (c *connection) redeliver(playerID int) {
+	messages, err := c.service.PendingSettlements(playerID)
+	if err != nil {
+		log.Printf("Error loading settlement messages for User ID %d: %v", playerID, err)
+		return
+	}
+	for _, message := range messages {
+		msg := WsMessage{ID: c.hub.nextMessageID(), Seq: message.Seq, Type: message.Type}
+		if err := c.sendMessage(msg, json.RawMessage(message.Payload)); err != nil {
+			log.Printf("Error redelivering settlement message %d for User ID %d: %v", message.Seq, playerID, err)
+			return
+		}
+	}
+}
+
+// handleAckMessage acknowledges the settlement messages the client received, always those of the connection's player
+func (c *connection) handleAckMessage(msg WsMessage) error {
+	var payload domain.AckRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid ack payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Ack Message id '%s' for User ID: %d up to seq %d", msg.ID, payload.ClientID, payload.Seq)
+
+	ack, err := c.service.AckSettlements(payload)
+	if err != nil {
+		return err
+	}
+	return c.reply(msg, domain.MessageTypeAck, ack)
+}
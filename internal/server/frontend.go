@@ -0,0 +1,28 @@
+package server
+
+import (
+	"log"
+	"net/http"
+)
+
+// frontendDir is the directory the bundled frontend is served from, relative to the working directory
+const frontendDir = "./frontend"
+
+// registerFrontend serves the bundled frontend, its page on / and its assets under /frontend/
+func (s *WebSocketServer) registerFrontend(mux *http.ServeMux) {
+	mux.HandleFunc("/", serveHome)
+	mux.Handle("/frontend/", http.StripPrefix("/frontend/", http.FileServer(http.Dir(frontendDir))))
+}
+
+func serveHome(w http.ResponseWriter, r *http.Request) {
+	log.Println(r.URL)
+	if r.URL.Path != "/" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	http.ServeFile(w, r, frontendDir+"/index.html")
+}
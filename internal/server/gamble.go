@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// handleGambleMessage rolls the double-or-nothing gamble on the winnings of the player's last round
+func (c *connection) handleGambleMessage(msg WsMessage) error {
+	var payload domain.GambleRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid gamble payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Gamble Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	if c.realityCheck.isPending() {
+		return appErrors.NewRealityCheckPendingError(fmt.Sprintf("Client ID %d must acknowledge the reality check before playing.", payload.ClientID)).WithDetail(appErrors.DetailRealityCheck)
+	}
+
+	result, err := c.service.Gamble(payload, c.dice)
+	if err != nil {
+		return err
+	}
+	c.recordGamble(result.Gamble)
+	return c.replySettled(msg, domain.MessageTypeGamble, result.Seq, result)
+}
+
+// handleGambleCollectMessage ends the open gamble of the player and credits its amount
+func (c *connection) handleGambleCollectMessage(msg WsMessage) error {
+	var payload domain.GambleCollectRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid gamble collect payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Gamble Collect Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	result, err := c.service.CollectGamble(payload.ClientID)
+	if err != nil {
+		return err
+	}
+	c.recordGamble(result.Gamble)
+	return c.replySettled(msg, domain.MessageTypeGambleCollect, result.Seq, result)
+}
+
+// recordGamble adds the result of a finished gamble to the reality check
+func (c *connection) recordGamble(gamble domain.Gamble) {
+	if gamble.Status != domain.GambleOpen {
+		c.realityCheck.record(gamble.Amount - gamble.Stake)
+	}
+}
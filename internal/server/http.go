@@ -0,0 +1,68 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+)
+
+// httpStatus maps a game error code to the HTTP status of the responses carrying it
+func httpStatus(code int) int {
+	switch code {
+	case appErrors.InternalErrorCode, appErrors.DiceRollErrorCode:
+		return http.StatusInternalServerError
+	case appErrors.InvalidInputErrorCode:
+		return http.StatusBadRequest
+	case appErrors.UserNotFoundErrorCode:
+		return http.StatusNotFound
+	case appErrors.ForbiddenErrorCode:
+		return http.StatusForbidden
+	case appErrors.InsufficientFundsErrorCode, appErrors.InvalidBetAmountErrorCode:
+		return http.StatusUnprocessableEntity
+	case appErrors.ActiveSessionErrorCode, appErrors.RealityCheckPendingErrorCode, appErrors.TableRoundErrorCode,
+		appErrors.TournamentErrorCode, appErrors.BonusErrorCode, appErrors.PromoErrorCode,
+		appErrors.VoidErrorCode, appErrors.GambleErrorCode:
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// requestIDHeader carries the correlation id of an HTTP request, echoed in the body of its errors
+const requestIDHeader = "X-Request-ID"
+
+// writeHTTPError replies with the JSON body of the game error in the locale of the Accept-Language header,
+// errors of other types are reported as internal
+func (s *WebSocketServer) writeHTTPError(w http.ResponseWriter, r *http.Request, err error) {
+	localizer := s.catalog.Negotiate(r.Header.Get("Accept-Language"))
+	gameErr := localizer.Error(appErrors.AsGameError(err)).WithRequestID(r.Header.Get(requestIDHeader))
+	writeJSON(w, httpStatus(gameErr.Code), gameErr)
+}
+
+// handleErrorCodes lists every error code so client developers can map them
+func handleErrorCodes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, appErrors.Definitions())
+}
+
+// writeJSON writes a JSON response body with the given status
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Printf("Error writing HTTP response: %v", err)
+	}
+}
+
+// bearerToken reads the bearer token of the Authorization header
+func bearerToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// validToken compares tokens in constant time
+func validToken(token, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// hub keeps track of the open connections so server pushes can reach every client
+// or only the clients subscribed to a room, such as a table, a tournament or a single player
+type hub struct {
+	mu          sync.RWMutex
+	connections map[*connection]struct{}
+	rooms       map[string]map[*connection]struct{}
+	messageSeq  atomic.Uint64
+}
+
+func newHub() *hub {
+	return &hub{
+		connections: make(map[*connection]struct{}),
+		rooms:       make(map[string]map[*connection]struct{}),
+	}
+}
+
+// tableRoom names the room of the players seated at a table
+func tableRoom(tableID int) string {
+	return fmt.Sprintf("table:%d", tableID)
+}
+
+// tournamentRoom names the room of the players following a tournament
+func tournamentRoom(tournamentID int) string {
+	return fmt.Sprintf("tournament:%d", tournamentID)
+}
+
+// playerRoom names the room of the connections authenticated as a player
+func playerRoom(playerID int) string {
+	return fmt.Sprintf("player:%d", playerID)
+}
+
+// nextMessageID generates the id of a server push, prefixed with srv- so clients can tell pushes from replies
+// Ids chosen by clients are not checked, a client using the srv- prefix for its own requests can get replies with the ids of pushes
+func (h *hub) nextMessageID() string {
+	return fmt.Sprintf("srv-%d", h.messageSeq.Add(1))
+}
+
+// register adds a connection to the broadcast list
+func (h *hub) register(c *connection) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.connections[c] = struct{}{}
+}
+
+// unregister removes a connection from the broadcast list and every room, must happen before its channels are closed
+func (h *hub) unregister(c *connection) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.connections, c)
+	for _, members := range h.rooms {
+		delete(members, c)
+	}
+}
+
+// join subscribes a connection to the broadcasts of a room
+func (h *hub) join(room string, c *connection) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*connection]struct{})
+	}
+	h.rooms[room][c] = struct{}{}
+}
+
+// leave unsubscribes a connection from a room, returning false when it was not a member
+func (h *hub) leave(room string, c *connection) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.rooms[room][c]; !ok {
+		return false
+	}
+	delete(h.rooms[room], c)
+	return true
+}
+
+// isMember reports whether a connection is subscribed to a room
+func (h *hub) isMember(room string, c *connection) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.rooms[room][c]
+	return ok
+}
+
+// members returns the connections subscribed to a room
+func (h *hub) members(room string) []*connection {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	members := make([]*connection, 0, len(h.rooms[room]))
+	for c := range h.rooms[room] {
+		members = append(members, c)
+	}
+	return members
+}
+
+// broadcast queues the message on every registered connection
+// Connections with a full buffer miss the message instead of blocking the others
+// Every copy of a broadcast carries the same id
+func (h *hub) broadcast(msgType domain.MessageType, data interface{}) {
+	id := h.nextMessageID()
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.connections {
+		c.send(id, msgType, data)
+	}
+}
+
+// broadcastRoom queues the message on every connection subscribed to the room
+func (h *hub) broadcastRoom(room string, msgType domain.MessageType, data interface{}) {
+	h.broadcastRoomMessage(room, WsMessage{ID: h.nextMessageID(), Type: msgType}, data)
+}
+
+// broadcastRoomMessage queues the envelope with data as its payload on every connection subscribed to the room
+func (h *hub) broadcastRoomMessage(room string, msg WsMessage, data interface{}) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.rooms[room] {
+		c.sendMessage(msg, data)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/service"
+)
+
+// broadcastJackpot periodically pushes the jackpot pool to every client whenever its value changed
+func (s *WebSocketServer) broadcastJackpot(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastAmount := -1.0
+	for range ticker.C {
+		jackpot, err := s.service.GetJackpot()
+		if err != nil {
+			log.Printf("Error getting jackpot pool: %v", err)
+			continue
+		}
+		if jackpot.Amount == lastAmount {
+			continue
+		}
+		lastAmount = jackpot.Amount
+		s.hub.broadcast(domain.MessageTypeJackpot, jackpot)
+	}
+}
+
+// handleJackpotMessage replies with the current jackpot pool
+func (c *connection) handleJackpotMessage(msg WsMessage) error {
+	log.Printf("Handling Jackpot Message id '%s'", msg.ID)
+
+	jackpot, err := c.service.GetJackpot()
+	if err != nil {
+		return err
+	}
+	return c.reply(msg, domain.MessageTypeJackpot, jackpot)
+}
+
+// announceJackpotWin immediately tells every client that the jackpot was won and reset
+// Shared by the connections and the REST API so a win is announced whatever transport it was played on
+func announceJackpotWin(gs *service.GameService, h *hub, amount float64) {
+	jackpot, err := gs.GetJackpot()
+	if err != nil {
+		log.Printf("Error getting jackpot pool: %v", err)
+		return
+	}
+	jackpot.LastWinAmount = amount
+	h.broadcast(domain.MessageTypeJackpot, jackpot)
+}
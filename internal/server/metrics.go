@@ -0,0 +1,55 @@
+package server
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Keys of the WebSocket metrics, published by expvar under "websocket" at the admin endpoint /admin/metrics
+// Raw bytes are the encoded messages, wire bytes what crossed the network once compressed and framed
+const (
+	metricRawBytesIn       = "raw_bytes_in"
+	metricRawBytesOut      = "raw_bytes_out"
+	metricWireBytesIn      = "wire_bytes_in"
+	metricWireBytesOut     = "wire_bytes_out"
+	metricMessagesTooLarge = "messages_too_large"
+)
+
+var wsMetrics = expvar.NewMap("websocket")
+
+// countingResponseWriter hands the upgrader a connection counting the bytes crossing the network
+type countingResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return countingConn{Conn: conn}, rw, nil
+}
+
+// countingConn adds the bytes read and written to the wire metrics
+type countingConn struct {
+	net.Conn
+}
+
+func (c countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	wsMetrics.Add(metricWireBytesIn, int64(n))
+	return n, err
+}
+
+func (c countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	wsMetrics.Add(metricWireBytesOut, int64(n))
+	return n, err
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// presence records which players are connected and when each was last seen, through their messages and heartbeats
+// Players stay registered once disconnected so their last seen time can still be queried, until they are evicted
+type presence struct {
+	mu          sync.Mutex
+	players     map[int]*playerPresence
+	connections map[*connection]int
+}
+
+// playerPresence is the state of one player, who is online while they have connections
+type playerPresence struct {
+	connections int
+	lastSeen    time.Time
+}
+
+func newPresence() *presence {
+	return &presence{
+		players:     make(map[int]*playerPresence),
+		connections: make(map[*connection]int),
+	}
+}
+
+// join counts the connection among the connections of the player it is authenticated as, joining again has no effect
+func (p *presence) join(c *connection) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.connections[c]; ok {
+		return
+	}
+	p.connections[c] = c.playerID
+
+	player, ok := p.players[c.playerID]
+	if !ok {
+		player = &playerPresence{}
+		p.players[c.playerID] = player
+	}
+	player.connections++
+	player.lastSeen = time.Now()
+}
+
+// touch marks the player of the connection as seen now
+func (p *presence) touch(c *connection) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if playerID, ok := p.connections[c]; ok {
+		p.players[playerID].lastSeen = time.Now()
+	}
+}
+
+// leave removes a closed connection, its player is last seen when it closed
+func (p *presence) leave(c *connection) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	playerID, ok := p.connections[c]
+	if !ok {
+		return
+	}
+	player := p.players[playerID]
+	player.connections--
+	player.lastSeen = time.Now()
+	delete(p.connections, c)
+}
+
+// evict forgets the offline players last seen before the given time and returns how many were forgotten
+func (p *presence) evict(before time.Time) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	evicted := 0
+	for playerID, player := range p.players {
+		if player.connections == 0 && player.lastSeen.Before(before) {
+			delete(p.players, playerID)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// online lists the connected players ordered by id
+func (p *presence) online() []domain.PlayerPresence {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	players := make([]domain.PlayerPresence, 0, len(p.players))
+	for playerID, player := range p.players {
+		if player.connections > 0 {
+			players = append(players, player.report(playerID, now))
+		}
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].PlayerID < players[j].PlayerID })
+	return players
+}
+
+// player returns the presence of a player, false when they have not connected since the server started or were evicted
+func (p *presence) player(playerID int) (domain.PlayerPresence, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	player, ok := p.players[playerID]
+	if !ok {
+		return domain.PlayerPresence{}, false
+	}
+	return player.report(playerID, time.Now()), true
+}
+
+// report describes the presence of the player at the given time
+func (pp *playerPresence) report(playerID int, now time.Time) domain.PlayerPresence {
+	return domain.PlayerPresence{
+		PlayerID:    playerID,
+		Online:      pp.connections > 0,
+		Connections: pp.connections,
+		LastSeen:    pp.lastSeen,
+		IdleSeconds: int64(now.Sub(pp.lastSeen).Seconds()),
+	}
+}
+
+// evictPresence periodically forgets the players offline for longer than the ttl along with their idle reality checks
+func (s *WebSocketServer) evictPresence(interval, ttl time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		before := time.Now().Add(-ttl)
+		if evicted := s.presence.evict(before); evicted > 0 {
+			log.Printf("Evicted %d offline player(s) from presence", evicted)
+		}
+		if evicted := s.checks.evict(before); evicted > 0 {
+			log.Printf("Evicted %d idle reality check(s)", evicted)
+		}
+	}
+}
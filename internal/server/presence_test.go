@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresence_TracksConnectionsPerPlayer(t *testing.T) {
+	p := newPresence()
+	first, second, third := &connection{playerID: 1}, &connection{playerID: 1}, &connection{playerID: 2}
+
+	p.join(first)
+	p.join(first)
+	p.join(second)
+	p.join(third)
+	online := p.online()
+	require.Len(t, online, 2)
+	assert.Equal(t, 2, online[0].Connections)
+	assert.Equal(t, 1, online[1].Connections)
+
+	seen := online[1].LastSeen
+	p.touch(third)
+	player, _ := p.player(2)
+	assert.False(t, player.LastSeen.Before(seen))
+
+	p.leave(third)
+	p.leave(third)
+	online = p.online()
+	require.Len(t, online, 1)
+	assert.Equal(t, 1, online[0].PlayerID)
+	assert.Equal(t, 2, online[0].Connections)
+
+	player, ok := p.player(2)
+	assert.True(t, ok)
+	assert.False(t, player.Online)
+	assert.Equal(t, 0, player.Connections)
+
+	_, ok = p.player(3)
+	assert.False(t, ok)
+}
+
+func TestPresence_EvictsOfflinePlayers(t *testing.T) {
+	p := newPresence()
+	online, offline := &connection{playerID: 1}, &connection{playerID: 2}
+	p.join(online)
+	p.join(offline)
+	p.leave(offline)
+
+	assert.Equal(t, 0, p.evict(time.Now().Add(-time.Hour)))
+	assert.Equal(t, 1, p.evict(time.Now().Add(time.Second)))
+
+	_, ok := p.player(2)
+	assert.False(t, ok)
+	player, ok := p.player(1)
+	assert.True(t, ok)
+	assert.True(t, player.Online)
+}
+
+func TestHandlePresence(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, testConfig.Game), testConfig, newDice, nil, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/presence", s.handlePresence)
+	mux.HandleFunc("GET /admin/presence/{id}", s.handlePlayerPresence)
+	s.presence.join(&connection{playerID: 1})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "list", path: "/admin/presence", expectedStatus: http.StatusOK},
+		{name: "connected_player", path: "/admin/presence/1", expectedStatus: http.StatusOK},
+		{name: "unknown_player", path: "/admin/presence/2", expectedStatus: http.StatusNotFound},
+		{name: "invalid_player", path: "/admin/presence/abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			mux.ServeHTTP(res, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.expectedStatus, res.Code)
+		})
+	}
+
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/admin/presence", nil))
+	var presence domain.PresenceResponse
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &presence))
+	require.Len(t, presence.Players, 1)
+	assert.Equal(t, 1, presence.Players[0].PlayerID)
+	assert.True(t, presence.Players[0].Online)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// handleRedeemPromoMessage redeems a promo code and replies with the free bets or bonus it granted
+func (c *connection) handleRedeemPromoMessage(msg WsMessage) error {
+	var payload domain.RedeemPromoRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid redeem promo payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Redeem Promo Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	redemption, err := c.service.RedeemPromoCode(payload)
+	if err != nil {
+		return err
+	}
+	return c.reply(msg, domain.MessageTypeRedeemPromo, redemption)
+}
+
+// handleFreeBetsMessage lists the free bets the player can still play
+func (c *connection) handleFreeBetsMessage(msg WsMessage) error {
+	var payload domain.FreeBetsRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid free bets payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Free Bets Message id '%s' for User ID: %d", msg.ID, payload.ClientID)
+
+	freeBets, err := c.service.ListFreeBets(payload.ClientID)
+	if err != nil {
+		return err
+	}
+	return c.reply(msg, domain.MessageTypeFreeBets, freeBets)
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+const (
+	// subprotocolPrefix names the WebSocket subprotocols, e.g. spicydice.v1
+	subprotocolPrefix = "spicydice.v"
+	// defaultProtocolVersion is spoken by clients that never declare a version, which predate versioning
+	defaultProtocolVersion = 1
+	// featureSettlementRedelivery is requested in hello by clients that acknowledge settlement messages
+	// and want the unacknowledged ones redelivered
+	featureSettlementRedelivery = "settlement_redelivery"
+)
+
+// protocol is a version of the message schema. The handlers work with the current schema,
+// the codec of each version adapts payloads between it and what the clients of that version send and expect
+type protocol struct {
+	version int
+	codec   payloadCodec
+}
+
+// payloadCodec translates the payloads of one protocol version
+type payloadCodec interface {
+	// decode adapts a request payload of the version to the current schema
+	decode(msgType domain.MessageType, payload json.RawMessage) (json.RawMessage, error)
+	// encode adapts a payload of the current schema to the version
+	encode(msgType domain.MessageType, payload json.RawMessage) (json.RawMessage, error)
+}
+
+// currentCodec passes payloads through unchanged, for versions matching the current schema
+type currentCodec struct{}
+
+func (currentCodec) decode(_ domain.MessageType, payload json.RawMessage) (json.RawMessage, error) {
+	return payload, nil
+}
+
+func (currentCodec) encode(_ domain.MessageType, payload json.RawMessage) (json.RawMessage, error) {
+	return payload, nil
+}
+
+// protocols lists every supported version, oldest first
+// A schema change adds a version whose codec keeps the older versions on their schema
+var protocols = []*protocol{
+	{version: 1, codec: currentCodec{}},
+}
+
+// findProtocol returns the supported protocol of a version
+func findProtocol(version int) (*protocol, bool) {
+	for _, p := range protocols {
+		if p.version == version {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// supportedVersions lists the supported protocol versions, oldest first
+func supportedVersions() []int {
+	versions := make([]int, 0, len(protocols))
+	for _, p := range protocols {
+		versions = append(versions, p.version)
+	}
+	return versions
+}
+
+// subprotocols lists the WebSocket subprotocols offered during the upgrade, newest version first
+// with one subprotocol per codec of each version
+func subprotocols() []string {
+	names := make([]string, 0, len(protocols)*len(codecs))
+	for i := len(protocols) - 1; i >= 0; i-- {
+		for _, c := range codecs {
+			names = append(names, codecSubprotocol(protocols[i].version, c))
+		}
+	}
+	return names
+}
+
+// upgradeProtocol resolves the version declared during the upgrade, through the negotiated subprotocol
+// or the version query parameter, falling back to the default version
+func upgradeProtocol(r *http.Request, subprotocol string) (*protocol, error) {
+	raw, _ := splitSubprotocol(subprotocol)
+	if subprotocol == "" {
+		raw = r.URL.Query().Get("version")
+	}
+	if raw == "" {
+		p, _ := findProtocol(defaultProtocolVersion)
+		return p, nil
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, appErrors.NewInvalidInputError(fmt.Sprintf("Invalid protocol version: %s", raw))
+	}
+	return negotiateProtocol(version)
+}
+
+// negotiateProtocol returns the protocol of a version, refusing the unsupported ones
+func negotiateProtocol(version int) (*protocol, error) {
+	p, ok := findProtocol(version)
+	if !ok {
+		return nil, appErrors.NewInvalidInputError(fmt.Sprintf("Unsupported protocol version %d, supported versions are %v", version, supportedVersions()))
+	}
+	return p, nil
+}
+
+// codecName names a codec in hello responses
+func codecName(c codec) string {
+	if c.name() == "" {
+		return "json"
+	}
+	return c.name()
+}
+
+// features lists the optional capabilities enabled on this server, a disabled one is not advertised
+func (s *WebSocketServer) features() []string {
+	features := []string{"correlation_ids", "promo", "achievements", "localization", featureSettlementRedelivery}
+	if s.conf.Game.Autoplay.MaxRounds > 0 {
+		features = append(features, "autoplay")
+	}
+	if s.tables != nil && len(s.tables.TableIDs()) > 0 {
+		features = append(features, "tables")
+	}
+	if s.tournaments != nil {
+		features = append(features, "tournaments")
+	}
+	if s.conf.RealityCheck.Interval > 0 {
+		features = append(features, "reality_check")
+	}
+	if s.service.JackpotEnabled() {
+		features = append(features, "jackpot")
+	}
+	if s.service.GambleEnabled() {
+		features = append(features, "gamble")
+	}
+	return features
+}
+
+// handleHelloMessage switches the connection to the declared protocol version and locale and replies with the server capabilities
+// The reply is encoded with the newly negotiated version, an unsupported locale keeps the current one.
+// Clients requesting settlement redelivery are then sent their unacknowledged settlement messages, once per connection
+func (c *connection) handleHelloMessage(msg WsMessage) error {
+	var payload domain.HelloRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid hello payload")
+	}
+
+	log.Printf("Handling Hello Message id '%s' for protocol version %d", msg.ID, payload.Version)
+
+	if payload.Version != 0 {
+		p, err := negotiateProtocol(payload.Version)
+		if err != nil {
+			return err
+		}
+		c.protocol.Store(p)
+	}
+	if payload.Locale != "" {
+		c.localizer.Store(c.catalog.Negotiate(payload.Locale, c.localizer.Load().Locale()))
+	}
+	err := c.reply(msg, domain.MessageTypeHello, domain.HelloResponse{
+		Version:           c.protocol.Load().version,
+		Codec:             codecName(c.codec),
+		SupportedVersions: supportedVersions(),
+		Features:          c.features,
+		Locale:            c.localizer.Load().Locale(),
+	})
+	if err != nil {
+		return err
+	}
+	if slices.Contains(payload.Features, featureSettlementRedelivery) {
+		c.redelivered.Do(func() { c.redeliver(c.playerID) })
+	}
+	return nil
+}
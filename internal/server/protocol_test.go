@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newProtocolTestServer creates a server backed by an in memory repository with the given tables, every roll is a 1
+// Tables and tournaments have no repository, the protocol tests never play them
+func newProtocolTestServer(conf *config.Config, tableCount int) *WebSocketServer {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 250)
+	gs := service.NewGameService(repo, conf.Game)
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	return NewWebSocketServer(gs, conf, newDice, service.NewTableService(nil, gs, tableCount), service.NewTournamentService(nil, gs))
+}
+
+func TestFeatures(t *testing.T) {
+	always := []string{"correlation_ids", "promo", "achievements", "localization", featureSettlementRedelivery}
+	tests := []struct {
+		name       string
+		configure  func(conf *config.Config)
+		tableCount int
+		expected   []string
+	}{
+		{name: "optional_features_disabled", configure: func(conf *config.Config) {}, expected: append(always, "tournaments")},
+		{
+			name: "every_feature_enabled",
+			configure: func(conf *config.Config) {
+				conf.Game.Autoplay.MaxRounds = 10
+				conf.RealityCheck.Interval = time.Hour
+				conf.Game.Jackpot.Enabled = true
+				conf.Game.Gamble.MaxSteps = 3
+			},
+			tableCount: 2,
+			expected:   append(always, "autoplay", "tables", "tournaments", "reality_check", "jackpot", "gamble"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := *testConfig
+			tt.configure(&conf)
+			s := newProtocolTestServer(&conf, tt.tableCount)
+			assert.ElementsMatch(t, tt.expected, s.features())
+		})
+	}
+}
+
+func TestUpgradeProtocol(t *testing.T) {
+	tests := []struct {
+		name            string
+		url             string
+		subprotocol     string
+		expectedVersion int
+		expectError     bool
+	}{
+		{name: "undeclared", url: "/ws", expectedVersion: defaultProtocolVersion},
+		{name: "query_parameter", url: "/ws?version=1", expectedVersion: 1},
+		{name: "subprotocol", url: "/ws?version=9", subprotocol: "spicydice.v1.msgpack", expectedVersion: 1},
+		{name: "invalid_version", url: "/ws?version=x", expectError: true},
+		{name: "unsupported_version", url: "/ws?version=9", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proto, err := upgradeProtocol(httptest.NewRequest("GET", tt.url, nil), tt.subprotocol)
+			if tt.expectError {
+				assert.Equal(t, appErrors.InvalidInputErrorCode, appErrors.AsGameError(err).Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedVersion, proto.version)
+		})
+	}
+}
+
+func TestHandleHelloMessage(t *testing.T) {
+	tests := []struct {
+		name           string
+		hello          domain.HelloRequest
+		expectedType   domain.MessageType
+		expectedLocale string
+	}{
+		{name: "negotiates_version_and_locale", hello: domain.HelloRequest{Version: 1, Locale: "de-AT"}, expectedType: domain.MessageTypeHello, expectedLocale: "de"},
+		{name: "keeps_locale_when_unsupported", hello: domain.HelloRequest{Version: 1, Locale: "xx"}, expectedType: domain.MessageTypeHello, expectedLocale: "en"},
+		{name: "unsupported_version", hello: domain.HelloRequest{Version: 9}, expectedType: domain.MessageTypeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := *testConfig
+			conf.Game.Jackpot.Enabled = true
+			s := newProtocolTestServer(&conf, 0)
+			conn := s.newConnection(1, protocols[0], s.catalog.Negotiate("en"))
+
+			payload, err := json.Marshal(tt.hello)
+			require.NoError(t, err)
+			conn.dispatch(WsMessage{ID: "hello-1", Type: domain.MessageTypeHello, Payload: payload})
+
+			message := <-conn.messagesChan
+			assert.Equal(t, "hello-1", message.ID)
+			require.Equal(t, tt.expectedType, message.Type)
+			if tt.expectedType != domain.MessageTypeHello {
+				return
+			}
+			var hello domain.HelloResponse
+			require.NoError(t, json.Unmarshal(message.Payload, &hello))
+			assert.Equal(t, tt.hello.Version, hello.Version)
+			assert.Equal(t, "json", hello.Codec)
+			assert.Equal(t, supportedVersions(), hello.SupportedVersions)
+			assert.Equal(t, s.features(), hello.Features)
+			assert.Contains(t, hello.Features, "jackpot")
+			assert.NotContains(t, hello.Features, "gamble")
+			assert.Equal(t, tt.expectedLocale, hello.Locale)
+		})
+	}
+}
+
+func TestAsyncAPI_DocumentsEveryMessageType(t *testing.T) {
+	spec, err := os.ReadFile("../../asyncapi.yaml")
+	require.NoError(t, err)
+	for _, msgType := range domain.MessageTypes() {
+		assert.Contains(t, string(spec), "\n              - "+string(msgType)+"\n", "asyncapi.yaml lacks message type %s", msgType)
+	}
+}
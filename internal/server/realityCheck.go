@@ -0,0 +1,234 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// realityChecks keeps the reality check of every player, shared by all their connections and the REST API
+// so a reminder cannot be skipped by opening another connection or playing over another transport
+// A check is dropped once its player has no connections left, unless a session or reminder is still running,
+// and whatever is left is evicted after going idle
+type realityChecks struct {
+	mu       sync.Mutex
+	interval time.Duration
+	notify   func(playerID int, check domain.RealityCheckResponse)
+	checks   map[int]*realityCheck
+}
+
+func newRealityChecks(interval time.Duration, notify func(playerID int, check domain.RealityCheckResponse)) *realityChecks {
+	return &realityChecks{
+		interval: interval,
+		notify:   notify,
+		checks:   make(map[int]*realityCheck),
+	}
+}
+
+// get returns the reality check of a player, creating it on first use
+func (rcs *realityChecks) get(playerID int) *realityCheck {
+	rcs.mu.Lock()
+	defer rcs.mu.Unlock()
+	return rcs.getLocked(playerID)
+}
+
+// getLocked returns the reality check of a player, creating it on first use, callers must hold the lock
+func (rcs *realityChecks) getLocked(playerID int) *realityCheck {
+	rc, ok := rcs.checks[playerID]
+	if !ok {
+		rc = newRealityCheck(rcs.interval, func(check domain.RealityCheckResponse) { rcs.notify(playerID, check) })
+		rcs.checks[playerID] = rc
+	}
+	return rc
+}
+
+// acquire returns the reality check of a player for one of their connections, which releases it once closed
+func (rcs *realityChecks) acquire(playerID int) *realityCheck {
+	rcs.mu.Lock()
+	defer rcs.mu.Unlock()
+	rc := rcs.getLocked(playerID)
+	rc.connections++
+	return rc
+}
+
+// release drops the reality check of a player whose last connection closed, unless it is still tracking
+// a session or holds a reminder waiting for an acknowledgement
+func (rcs *realityChecks) release(playerID int) {
+	rcs.mu.Lock()
+	defer rcs.mu.Unlock()
+	rc, ok := rcs.checks[playerID]
+	if !ok {
+		return
+	}
+	rc.connections--
+	if rc.connections == 0 && rc.isIdle() {
+		delete(rcs.checks, playerID)
+	}
+}
+
+// reset ends the session tracked for a player, the next round starts a new one
+func (rcs *realityChecks) reset(playerID int) {
+	rcs.mu.Lock()
+	defer rcs.mu.Unlock()
+	if rc, ok := rcs.checks[playerID]; ok {
+		rc.reset()
+	}
+}
+
+// evict drops the reality checks of players without connections and inactive since the given time,
+// returning how many were dropped. Their sessions and pending reminders are forgotten
+func (rcs *realityChecks) evict(before time.Time) int {
+	rcs.mu.Lock()
+	defer rcs.mu.Unlock()
+	evicted := 0
+	for playerID, rc := range rcs.checks {
+		if rc.connections == 0 && rc.inactiveSince(before) {
+			rc.reset()
+			delete(rcs.checks, playerID)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// realityCheck tracks the play time and net result of a player and
+// periodically reminds the player of both, blocking play until acknowledged
+type realityCheck struct {
+	mu         sync.Mutex
+	interval   time.Duration
+	notify     func(domain.RealityCheckResponse)
+	startedAt  time.Time
+	activeAt   time.Time
+	netResult  float64
+	rounds     int
+	pending    bool
+	check      domain.RealityCheckResponse
+	generation int
+
+	// connections counts the open connections of the player, guarded by the realityChecks lock
+	connections int
+}
+
+// newRealityCheck creates a tracker that calls notify every interval of play
+// A zero interval disables the reminders entirely
+func newRealityCheck(interval time.Duration, notify func(domain.RealityCheckResponse)) *realityCheck {
+	return &realityCheck{
+		interval: interval,
+		notify:   notify,
+		activeAt: time.Now(),
+	}
+}
+
+// record accounts a settled round, starting the session clock on the first one
+func (rc *realityCheck) record(netResult float64) {
+	rc.account(netResult, 1)
+}
+
+// adjust accounts money staked or won outside of a round, such as tournament buy-ins and prizes
+func (rc *realityCheck) adjust(amount float64) {
+	rc.account(amount, 0)
+}
+
+// account adds to the net result and rounds played, starting the session clock on the first call
+func (rc *realityCheck) account(netResult float64, rounds int) {
+	if rc.interval <= 0 {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.activeAt = time.Now()
+	if rc.startedAt.IsZero() {
+		rc.startedAt = rc.activeAt
+		rc.schedule()
+	}
+	rc.netResult += netResult
+	rc.rounds += rounds
+}
+
+// reset ends the tracked session, the next round starts the clock again from zero
+// Reminders scheduled for the ended session are cancelled, one already sent stays pending until acknowledged
+func (rc *realityCheck) reset() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.startedAt = time.Time{}
+	rc.netResult = 0
+	rc.rounds = 0
+	rc.generation++
+}
+
+// isIdle reports whether no session is tracked and no reminder is pending
+func (rc *realityCheck) isIdle() bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.startedAt.IsZero() && !rc.pending
+}
+
+// inactiveSince reports whether nothing was played or acknowledged since the given time
+func (rc *realityCheck) inactiveSince(before time.Time) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.activeAt.Before(before)
+}
+
+// isPending reports whether a reminder was sent and not yet acknowledged
+func (rc *realityCheck) isPending() bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.pending
+}
+
+// pendingCheck returns the reminder waiting for an acknowledgement, if any
+func (rc *realityCheck) pendingCheck() (domain.RealityCheckResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.check, rc.pending
+}
+
+// acknowledge clears a pending reminder and schedules the next one while a session is tracked
+func (rc *realityCheck) acknowledge() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.pending {
+		return
+	}
+	rc.pending = false
+	rc.activeAt = time.Now()
+	if !rc.startedAt.IsZero() {
+		rc.schedule()
+	}
+}
+
+// schedule arms the timer for the next reminder of the current session, callers must hold the lock
+func (rc *realityCheck) schedule() {
+	generation := rc.generation
+	time.AfterFunc(rc.interval, func() { rc.fire(generation) })
+}
+
+// fire marks the reminder as pending and pushes it to the player, unless its session was reset since it was scheduled
+// A player without open connections gets it with their next connection, play stays blocked until then
+func (rc *realityCheck) fire(generation int) {
+	rc.mu.Lock()
+	if generation != rc.generation {
+		rc.mu.Unlock()
+		return
+	}
+	rc.pending = true
+	rc.check = domain.RealityCheckResponse{
+		ElapsedSeconds: int64(time.Since(rc.startedAt).Seconds()),
+		NetResult:      rc.netResult,
+		RoundsPlayed:   rc.rounds,
+	}
+	check := rc.check
+	rc.mu.Unlock()
+
+	rc.notify(check)
+}
+
+// pushRealityCheck sends a reminder to every open connection of the player, each in its own locale
+func (s *WebSocketServer) pushRealityCheck(playerID int, check domain.RealityCheckResponse) {
+	for _, c := range s.hub.members(playerRoom(playerID)) {
+		c.sendRealityCheck(check)
+	}
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notification is a reality check reminder sent to a player
+type notification struct {
+	playerID int
+	check    domain.RealityCheckResponse
+}
+
+func TestRealityChecks_TrackedPerPlayer(t *testing.T) {
+	notified := make(chan notification, 2)
+	checks := newRealityChecks(20*time.Millisecond, func(playerID int, check domain.RealityCheckResponse) {
+		notified <- notification{playerID, check}
+	})
+
+	assert.Same(t, checks.get(1), checks.get(1))
+	assert.NotSame(t, checks.get(1), checks.get(2))
+
+	checks.get(1).record(-10)
+	checks.get(1).record(5)
+	checks.get(1).adjust(-20)
+
+	select {
+	case n := <-notified:
+		assert.Equal(t, 1, n.playerID)
+		assert.Equal(t, 2, n.check.RoundsPlayed)
+		assert.Equal(t, -25.0, n.check.NetResult)
+	case <-time.After(time.Second):
+		t.Fatal("reality check was not sent")
+	}
+	assert.True(t, checks.get(1).isPending())
+	assert.False(t, checks.get(2).isPending())
+
+	checks.get(1).acknowledge()
+	assert.False(t, checks.get(1).isPending())
+}
+
+func TestRealityCheck_Disabled(t *testing.T) {
+	checks := newRealityChecks(0, func(int, domain.RealityCheckResponse) {
+		t.Fatal("disabled reality check was sent")
+	})
+	checks.get(1).record(-10)
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, checks.get(1).isPending())
+}
+
+func TestRealityCheck_SharedByConnectionsOfPlayer(t *testing.T) {
+	conf := *testConfig
+	conf.RealityCheck.Interval = 20 * time.Millisecond
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repository.NewMemoryRepository(), conf.Game), &conf, newDice, nil, nil)
+	proto := protocols[len(protocols)-1]
+
+	first := s.newConnection(1, proto, s.catalog.Negotiate(""))
+	first.identify()
+	first.realityCheck.record(-10)
+	select {
+	case message := <-first.messagesChan:
+		assert.Equal(t, domain.MessageTypeRealityCheck, message.Type)
+	case <-time.After(time.Second):
+		t.Fatal("reality check was not sent")
+	}
+
+	// A new connection of the player is still blocked and gets the pending reminder
+	second := s.newConnection(1, proto, s.catalog.Negotiate(""))
+	second.identify()
+	require.True(t, second.realityCheck.isPending())
+	message := <-second.messagesChan
+	assert.Equal(t, domain.MessageTypeRealityCheck, message.Type)
+
+	other := s.newConnection(2, proto, s.catalog.Negotiate(""))
+	assert.False(t, other.realityCheck.isPending())
+}
+
+func TestRealityCheck_RestartsWithEachSession(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	conf := *testConfig
+	conf.RealityCheck.Interval = interval
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 1000)
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, conf.Game), &conf, newDice, nil, nil)
+	conn := s.newConnection(1, protocols[len(protocols)-1], s.catalog.Negotiate(""))
+	conn.identify()
+
+	play := WsMessage{Type: domain.MessageTypePlay, Payload: json.RawMessage(`{"client_id":1,"bet_amount":10,"bet_type":"odd"}`)}
+	endPlay := WsMessage{Type: domain.MessageTypeEndPlay, Payload: json.RawMessage(`{"client_id":1}`)}
+	for _, msg := range []WsMessage{play, play, endPlay} {
+		conn.dispatch(msg)
+		require.Equal(t, msg.Type, (<-conn.messagesChan).Type)
+	}
+
+	// The reminder of the first session would be due halfway through the second one
+	time.Sleep(interval / 2)
+	secondStartedAt := time.Now()
+	conn.dispatch(play)
+	require.Equal(t, domain.MessageTypePlay, (<-conn.messagesChan).Type)
+
+	select {
+	case message := <-conn.messagesChan:
+		require.Equal(t, domain.MessageTypeRealityCheck, message.Type)
+		assert.GreaterOrEqual(t, time.Since(secondStartedAt), interval)
+		var check domain.RealityCheckResponse
+		require.NoError(t, json.Unmarshal(message.Payload, &check))
+		assert.Equal(t, 1, check.RoundsPlayed)
+		assert.Equal(t, 9.0, check.NetResult)
+	case <-time.After(time.Second):
+		t.Fatal("reality check was not sent")
+	}
+}
+
+func TestRealityChecks_Eviction(t *testing.T) {
+	tests := []struct {
+		name            string
+		play            bool
+		endSession      bool
+		expectedRelease bool
+		expectedEvicted int
+	}{
+		{name: "no_session", expectedRelease: true},
+		{name: "ended_session", play: true, endSession: true, expectedRelease: true},
+		{name: "running_session_waits_for_idle", play: true, expectedEvicted: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := newRealityChecks(time.Hour, func(int, domain.RealityCheckResponse) {})
+			rc := checks.acquire(1)
+			if tt.play {
+				rc.record(-10)
+			}
+			if tt.endSession {
+				checks.reset(1)
+			}
+
+			checks.release(1)
+			_, kept := checks.checks[1]
+			assert.Equal(t, !tt.expectedRelease, kept)
+
+			assert.Equal(t, tt.expectedEvicted, checks.evict(time.Now().Add(time.Second)))
+			assert.Empty(t, checks.checks)
+		})
+	}
+}
+
+func TestRealityChecks_KeepsConnectedPlayers(t *testing.T) {
+	checks := newRealityChecks(time.Hour, func(int, domain.RealityCheckResponse) {})
+	first := checks.acquire(1)
+	checks.acquire(1)
+	first.record(-10)
+
+	checks.release(1)
+	assert.Equal(t, 0, checks.evict(time.Now().Add(time.Second)))
+	assert.Same(t, first, checks.get(1))
+}
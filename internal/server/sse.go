@@ -0,0 +1,173 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+)
+
+const (
+	// connectionTokenHeader carries the token binding the requests of an SSE client to its stream
+	connectionTokenHeader = "X-Connection-Token"
+)
+
+// sseConnections keeps the connections of the open SSE streams by connection token
+type sseConnections struct {
+	mu          sync.RWMutex
+	connections map[string]*connection
+}
+
+func newSSEConnections() *sseConnections {
+	return &sseConnections{connections: make(map[string]*connection)}
+}
+
+func (sc *sseConnections) add(token string, c *connection) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.connections[token] = c
+}
+
+func (sc *sseConnections) remove(token string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.connections, token)
+}
+
+func (sc *sseConnections) get(token string) (*connection, bool) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	c, ok := sc.connections[token]
+	return c, ok
+}
+
+// sseConnected is the first event of a stream, handing the client the token of its connection
+type sseConnected struct {
+	Token string `json:"token"`
+}
+
+// handleSSEStream opens a connection for clients that cannot upgrade to WebSocket and streams
+// its responses and pushes as server-sent events until the client goes away
+func (s *WebSocketServer) handleSSEStream(w http.ResponseWriter, r *http.Request) {
+	proto, err := upgradeProtocol(r, "")
+	if err != nil {
+		s.writeHTTPError(w, r, err)
+		return
+	}
+	token, err := newConnectionToken()
+	if err != nil {
+		s.writeHTTPError(w, r, appErrors.NewInternalError(err.Error()))
+		return
+	}
+
+	conn := s.newConnection(authenticatedPlayer(r), proto, s.catalog.Negotiate(r.Header.Get("Accept-Language")))
+	s.sse.add(token, conn)
+	s.hub.register(conn)
+	conn.identify()
+	defer func() {
+		log.Println("Closing SSE stream...")
+		s.sse.remove(token)
+		conn.cleanUpOnce()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	connected, _ := json.Marshal(sseConnected{Token: token})
+	if err := s.writeSSE(rc, w, fmt.Sprintf("event: connected\ndata: %s\n\n", connected)); err != nil {
+		log.Printf("Error opening SSE stream: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(s.conf.Server.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-conn.doneChan:
+			return
+		case <-ticker.C:
+			if err := s.writeSSE(rc, w, ": ping\n\n"); err != nil {
+				log.Printf("Error sending SSE keepalive: %v", err)
+				return
+			}
+		case message := <-conn.messagesChan:
+			data, err := conn.codec.marshal(message)
+			if err != nil {
+				log.Printf("error encoding message: %s", err)
+				continue
+			}
+			event := fmt.Sprintf("data: %s\n\n", data)
+			if message.ID != "" {
+				event = fmt.Sprintf("id: %s\n%s", message.ID, event)
+			}
+			if err := s.writeSSE(rc, w, event); err != nil {
+				log.Printf("error writing message: %s", err)
+				return
+			}
+		}
+	}
+}
+
+// handleSSEMessage dispatches a request of an SSE client to its connection, the response is sent on the stream
+// Only the player the stream was opened by can send on its connection
+func (s *WebSocketServer) handleSSEMessage(w http.ResponseWriter, r *http.Request) {
+	conn, ok := s.sse.get(r.Header.Get(connectionTokenHeader))
+	if !ok {
+		http.Error(w, "Unknown connection token", http.StatusUnauthorized)
+		return
+	}
+	if conn.playerID != authenticatedPlayer(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	body := r.Body
+	if s.conf.Server.MaxMessageSize > 0 {
+		body = http.MaxBytesReader(w, r.Body, s.conf.Server.MaxMessageSize)
+	}
+	var message WsMessage
+	if err := json.NewDecoder(body).Decode(&message); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			wsMetrics.Add(metricMessagesTooLarge, 1)
+			http.Error(w, "Message too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		s.writeHTTPError(w, r, appErrors.NewInvalidInputError("Invalid message"))
+		return
+	}
+
+	conn.dispatch(message)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// writeSSE writes and flushes an event within the write timeout
+func (s *WebSocketServer) writeSSE(rc *http.ResponseController, w http.ResponseWriter, event string) error {
+	if err := rc.SetWriteDeadline(time.Now().Add(s.conf.Server.WriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if _, err := fmt.Fprint(w, event); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
+
+// newConnectionToken generates the unguessable token of an SSE connection
+func newConnectionToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate connection token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
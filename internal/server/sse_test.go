@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/Desgue/SpicyDice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = &config.Config{
+	Server: config.ServerConfig{
+		AuthSecret:   "test-secret",
+		AuthTokenTTL: time.Hour,
+		PingInterval: 30 * time.Second,
+		ReadTimeout:  60 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	},
+	Game: config.GameConfig{
+		MinBetAmount: 10,
+		MaxBetAmount: 100,
+		MaxRTP:       0.99,
+		Variants: map[string]config.VariantConfig{
+			service.VariantParity: {Multiplier: 1.9, TargetRTP: 0.95},
+			service.VariantExact:  {Multiplier: 5.7, TargetRTP: 0.95},
+		},
+	},
+}
+
+// sseClient is a client of the SSE transport reading the events of its stream
+type sseClient struct {
+	t      *testing.T
+	url    string
+	token  string
+	body   io.Closer
+	reader *bufio.Reader
+}
+
+// newSSETestServer serves the SSE transport backed by an in memory repository, every roll is a 1
+func newSSETestServer(t *testing.T, repo *repository.MemoryRepository) *httptest.Server {
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repo, testConfig.Game), testConfig, newDice, nil, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sse/spicy-dice", s.authenticate(s.handleSSEStream))
+	mux.HandleFunc("POST /sse/spicy-dice/messages", s.authenticate(s.handleSSEMessage))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// authHeader authenticates a test request as the player
+func authHeader(playerID int) http.Header {
+	return http.Header{"Authorization": {"Bearer " + signPlayerToken(testConfig.Server.AuthSecret, playerID, time.Now().Add(time.Hour))}}
+}
+
+// openSSEStream opens a stream as player 1 and reads the token of its connection
+func openSSEStream(t *testing.T, url string) *sseClient {
+	req, err := http.NewRequest(http.MethodGet, url+"/sse/spicy-dice", nil)
+	require.NoError(t, err)
+	req.Header = authHeader(1)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	client := &sseClient{t: t, url: url, body: stream.Body, reader: bufio.NewReader(stream.Body)}
+	t.Cleanup(func() { client.body.Close() })
+
+	var connected sseConnected
+	require.NoError(t, json.Unmarshal([]byte(client.readData()), &connected))
+	client.token = connected.Token
+	return client
+}
+
+// readData returns the data of the next event of the stream
+func (c *sseClient) readData() string {
+	for {
+		line, err := c.reader.ReadString('\n')
+		require.NoError(c.t, err)
+		if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+			return data
+		}
+	}
+}
+
+// readMessage returns the next message of the stream
+func (c *sseClient) readMessage() WsMessage {
+	var message WsMessage
+	require.NoError(c.t, json.Unmarshal([]byte(c.readData()), &message))
+	return message
+}
+
+// post sends a request as player 1 with the given connection token and returns the response status
+func (c *sseClient) post(token, body string) int {
+	return c.postAs(1, token, body)
+}
+
+// postAs sends a request as the player with the given connection token and returns the response status
+func (c *sseClient) postAs(playerID int, token, body string) int {
+	req, err := http.NewRequest(http.MethodPost, c.url+"/sse/spicy-dice/messages", strings.NewReader(body))
+	require.NoError(c.t, err)
+	req.Header = authHeader(playerID)
+	req.Header.Set(connectionTokenHeader, token)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+	res.Body.Close()
+	return res.StatusCode
+}
+
+func TestSSETransport_DispatchesToConnection(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 250)
+	client := openSSEStream(t, newSSETestServer(t, repo).URL)
+
+	assert.Equal(t, http.StatusUnauthorized, client.post("unknown", `{"type":"wallet","payload":{"client_id":1}}`))
+	assert.Equal(t, http.StatusAccepted, client.post(client.token, `{"id":"req-1","type":"wallet","payload":{"client_id":1}}`))
+
+	message := client.readMessage()
+	assert.Equal(t, "req-1", message.ID)
+	assert.Equal(t, domain.MessageTypeWallet, message.Type)
+	var wallet domain.WalletResponse
+	require.NoError(t, json.Unmarshal(message.Payload, &wallet))
+	assert.Equal(t, 250.0, wallet.Balance)
+}
+
+func TestConnection_BoundToAuthenticatedPlayer(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 250)
+	repo.AddPlayer(2, 500)
+	client := openSSEStream(t, newSSETestServer(t, repo).URL)
+
+	assert.Equal(t, http.StatusForbidden, client.postAs(2, client.token, `{"type":"wallet","payload":{"client_id":2}}`))
+
+	assert.Equal(t, http.StatusAccepted, client.post(client.token, `{"id":"req-1","type":"wallet","payload":{"client_id":2}}`))
+	message := client.readMessage()
+	assert.Equal(t, domain.MessageTypeError, message.Type)
+	var gameErr appErrors.GameError
+	require.NoError(t, json.Unmarshal(message.Payload, &gameErr))
+	assert.Equal(t, appErrors.ForbiddenErrorCode, gameErr.Code)
+	assert.Equal(t, "req-1", gameErr.RequestID)
+}
+
+func TestSettlementMessages_RedeliveredUntilAcknowledged(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 250)
+	url := newSSETestServer(t, repo).URL
+
+	first := openSSEStream(t, url)
+	first.post(first.token, `{"id":"play-1","type":"play","payload":{"client_id":1,"bet_amount":10,"bet_type":"odd"}}`)
+	played := first.readMessage()
+	assert.Equal(t, domain.MessageTypePlay, played.Type)
+	assert.Equal(t, int64(1), played.Seq)
+	first.body.Close()
+
+	// Clients that do not request redelivery never receive it
+	legacy := openSSEStream(t, url)
+	legacy.post(legacy.token, `{"type":"wallet","payload":{"client_id":1}}`)
+	assert.Equal(t, domain.MessageTypeWallet, legacy.readMessage().Type)
+
+	second := openSSEStream(t, url)
+	second.post(second.token, `{"type":"hello","payload":{"version":1,"features":["settlement_redelivery"]}}`)
+	assert.Equal(t, domain.MessageTypeHello, second.readMessage().Type)
+	redelivered := second.readMessage()
+	assert.Equal(t, domain.MessageTypePlay, redelivered.Type)
+	assert.Equal(t, played.Seq, redelivered.Seq)
+	assert.JSONEq(t, string(played.Payload), string(redelivered.Payload))
+
+	second.post(second.token, `{"type":"ack","payload":{"seq":1}}`)
+	assert.Equal(t, domain.MessageTypeAck, second.readMessage().Type)
+
+	third := openSSEStream(t, url)
+	third.post(third.token, `{"type":"hello","payload":{"version":1,"features":["settlement_redelivery"]}}`)
+	assert.Equal(t, domain.MessageTypeHello, third.readMessage().Type)
+	third.post(third.token, `{"type":"wallet","payload":{"client_id":1}}`)
+	assert.Equal(t, domain.MessageTypeWallet, third.readMessage().Type)
+}
+
+func TestErrors_LocalizedToNegotiatedLocale(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 250)
+	client := openSSEStream(t, newSSETestServer(t, repo).URL)
+
+	client.post(client.token, `{"type":"hello","payload":{"version":1,"locale":"de-AT"}}`)
+	var hello domain.HelloResponse
+	require.NoError(t, json.Unmarshal(client.readMessage().Payload, &hello))
+	assert.Equal(t, "de", hello.Locale)
+
+	client.post(client.token, `{"id":"play-1","type":"play","payload":{"client_id":1,"bet_amount":5,"bet_type":"odd"}}`)
+	message := client.readMessage()
+	assert.Equal(t, domain.MessageTypeError, message.Type)
+	var gameErr appErrors.GameError
+	require.NoError(t, json.Unmarshal(message.Payload, &gameErr))
+	assert.Equal(t, appErrors.InvalidBetAmountErrorCode, gameErr.Code)
+	assert.Equal(t, "Ungültiger Einsatz", gameErr.Message)
+	assert.Equal(t, "Der Mindesteinsatz beträgt 10,00\u00a0€", gameErr.Details)
+	assert.Equal(t, "play-1", gameErr.RequestID)
+}
+
+func TestConnection_SendAfterCleanupReturnsError(t *testing.T) {
+	newDice := func() service.DiceRoller { return service.NewScriptedDice([]int{1}) }
+	s := NewWebSocketServer(service.NewGameService(repository.NewMemoryRepository(), testConfig.Game), testConfig, newDice, nil, nil)
+	conn := s.newConnection(1, protocols[len(protocols)-1], nil)
+	conn.cleanUpOnce()
+
+	assert.NotPanics(t, func() {
+		assert.Error(t, conn.send("", domain.MessageTypeWallet, domain.WalletResponse{}))
+	})
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/service"
+)
+
+// runTable drives the rounds of a table: open betting for the configured window, close it,
+// roll once for every bet, settle them and broadcast each step to the seated players
+// Each player is also sent the results of their own bets, stored along with the settlement
+func (s *WebSocketServer) runTable(tableID int) {
+	dice := s.newDice()
+	for {
+		if err := s.playTableRound(tableID, dice); err != nil {
+			log.Printf("Error playing round on table %d: %v", tableID, err)
+			s.voidTableRound(tableID)
+		}
+		time.Sleep(s.conf.Tables.Intermission)
+	}
+}
+
+// voidTableRound refunds the round a failed step left unfinished, retrying until the refund is committed
+// so the next round can be opened
+func (s *WebSocketServer) voidTableRound(tableID int) {
+	for {
+		round, err := s.tables.VoidRound(tableID)
+		if err == nil {
+			if round.State == domain.RoundVoided {
+				s.hub.broadcastRoom(tableRoom(tableID), domain.MessageTypeTableRound, round)
+			}
+			return
+		}
+		log.Printf("Error voiding round on table %d, retrying: %v", tableID, err)
+		time.Sleep(s.conf.Tables.Intermission)
+	}
+}
+
+// playTableRound runs a single round through every state of the table state machine
+func (s *WebSocketServer) playTableRound(tableID int, dice service.DiceRoller) error {
+	round, err := s.tables.OpenRound(tableID, time.Now().Add(s.conf.Tables.BettingWindow))
+	if err != nil {
+		return err
+	}
+	s.hub.broadcastRoom(tableRoom(tableID), domain.MessageTypeTableRound, round)
+
+	time.Sleep(s.conf.Tables.BettingWindow)
+
+	round, err = s.tables.CloseBetting(tableID)
+	if err != nil {
+		return err
+	}
+	s.hub.broadcastRoom(tableRoom(tableID), domain.MessageTypeTableRound, round)
+
+	round, err = s.tables.Roll(tableID, dice)
+	if err != nil {
+		return err
+	}
+	s.hub.broadcastRoom(tableRoom(tableID), domain.MessageTypeTableRound, round)
+
+	settlement, err := s.tables.Settle(tableID)
+	if err != nil {
+		return err
+	}
+	s.hub.broadcastRoom(tableRoom(tableID), domain.MessageTypeTableSettlement, settlement)
+	for _, player := range settlement.PlayerResults() {
+		var netResult float64
+		for _, result := range player.Results {
+			netResult += result.Payout - result.BetAmount
+		}
+		s.checks.get(player.Results[0].PlayerID).record(netResult)
+	}
+	for _, message := range settlement.Messages {
+		s.hub.pushSettled(message.PlayerID, domain.MessageTypeTableBetSettled, message.Seq, json.RawMessage(message.Payload))
+	}
+	return nil
+}
+
+// handleTableJoinMessage seats the connection at a table and replies with the current round
+func (c *connection) handleTableJoinMessage(msg WsMessage) error {
+	var payload domain.TableJoinRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid table join payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Table Join Message id '%s' for User ID: %d, Table ID: %d", msg.ID, payload.ClientID, payload.TableID)
+
+	round, err := c.tables.CurrentRound(payload.TableID)
+	if err != nil {
+		return err
+	}
+	c.hub.join(tableRoom(payload.TableID), c)
+	return c.reply(msg, domain.MessageTypeTableRound, round)
+}
+
+// handleTableLeaveMessage removes the connection from a table, bets already placed are still settled
+func (c *connection) handleTableLeaveMessage(msg WsMessage) error {
+	var payload domain.TableLeaveRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid table leave payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Table Leave Message id '%s' for User ID: %d, Table ID: %d", msg.ID, payload.ClientID, payload.TableID)
+
+	if !c.hub.leave(tableRoom(payload.TableID), c) {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("Client ID %d is not seated at table %d.", payload.ClientID, payload.TableID)).WithDetail(appErrors.DetailTableNotSeated, payload.ClientID, payload.TableID)
+	}
+	return c.reply(msg, domain.MessageTypeTableLeave, domain.TableLeaveResponse{ClientID: payload.ClientID, TableID: payload.TableID})
+}
+
+// handleTableBetMessage places a bet on the current round of a table the connection is seated at
+func (c *connection) handleTableBetMessage(msg WsMessage) error {
+	var payload domain.TableBetRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid table bet payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Table Bet Message id '%s' for User ID: %d, Table ID: %d", msg.ID, payload.ClientID, payload.TableID)
+
+	if !c.hub.isMember(tableRoom(payload.TableID), c) {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("Client ID %d must join table %d before betting.", payload.ClientID, payload.TableID)).WithDetail(appErrors.DetailTableJoinFirst, payload.ClientID, payload.TableID)
+	}
+	if c.realityCheck.isPending() {
+		return appErrors.NewRealityCheckPendingError(fmt.Sprintf("Client ID %d must acknowledge the reality check before playing.", payload.ClientID)).WithDetail(appErrors.DetailRealityCheck)
+	}
+
+	result, err := c.tables.PlaceBet(payload)
+	if err != nil {
+		return err
+	}
+	return c.reply(msg, domain.MessageTypeTableBet, result)
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// closeTournaments periodically closes the tournaments past their end, paying their prizes
+// and broadcasting the final leaderboard to the players following them
+func (s *WebSocketServer) closeTournaments(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		closed, err := s.tournaments.CloseDueTournaments()
+		if err != nil {
+			log.Printf("Error closing tournaments: %v", err)
+		}
+		for _, leaderboard := range closed {
+			for _, entry := range leaderboard.Entries {
+				if entry.Prize > 0 {
+					s.checks.get(entry.PlayerID).adjust(entry.Prize)
+				}
+			}
+			s.hub.broadcastRoom(tournamentRoom(leaderboard.TournamentID), domain.MessageTypeTournamentLeaderboard, leaderboard)
+		}
+	}
+}
+
+// handleTournamentsMessage lists the tournaments open for registration or play
+func (c *connection) handleTournamentsMessage(msg WsMessage) error {
+	log.Printf("Handling Tournaments Message id '%s'", msg.ID)
+
+	tournaments, err := c.tournaments.ListTournaments()
+	if err != nil {
+		return err
+	}
+	return c.reply(msg, domain.MessageTypeTournaments, tournaments)
+}
+
+// handleTournamentRegisterMessage pays the buy-in and subscribes the connection to the tournament leaderboard
+func (c *connection) handleTournamentRegisterMessage(msg WsMessage) error {
+	var payload domain.TournamentRegisterRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid tournament register payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Tournament Register Message id '%s' for User ID: %d, Tournament ID: %d", msg.ID, payload.ClientID, payload.TournamentID)
+
+	if c.realityCheck.isPending() {
+		return appErrors.NewRealityCheckPendingError("Client must acknowledge the reality check before playing.").WithDetail(appErrors.DetailRealityCheck)
+	}
+
+	result, err := c.tournaments.Register(payload)
+	if err != nil {
+		return err
+	}
+	c.realityCheck.adjust(-result.BuyIn)
+	c.hub.join(tournamentRoom(payload.TournamentID), c)
+	if err := c.reply(msg, domain.MessageTypeTournamentRegister, result); err != nil {
+		return err
+	}
+	c.broadcastLeaderboard(payload.TournamentID)
+	return nil
+}
+
+// handleTournamentPlayMessage plays a round with the tournament stack and pushes the updated leaderboard
+func (c *connection) handleTournamentPlayMessage(msg WsMessage) error {
+	var payload domain.TournamentPlayRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid tournament play payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Tournament Play Message id '%s' for User ID: %d, Tournament ID: %d", msg.ID, payload.ClientID, payload.TournamentID)
+
+	if c.realityCheck.isPending() {
+		return appErrors.NewRealityCheckPendingError("Client must acknowledge the reality check before playing.").WithDetail(appErrors.DetailRealityCheck)
+	}
+
+	result, err := c.tournaments.Play(payload, c.dice)
+	if err != nil {
+		return err
+	}
+	// Tournament chips are not money, the round counts toward the play time only
+	c.realityCheck.record(0)
+	if err := c.replySettled(msg, domain.MessageTypeTournamentPlay, result.Seq, result); err != nil {
+		return err
+	}
+	c.broadcastLeaderboard(payload.TournamentID)
+	return nil
+}
+
+// handleTournamentLeaderboardMessage replies with the current ranking and subscribes to its live updates
+func (c *connection) handleTournamentLeaderboardMessage(msg WsMessage) error {
+	var payload domain.LeaderboardRequest
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return appErrors.NewInvalidInputError("Invalid tournament leaderboard payload")
+	}
+	payload.ClientID = c.playerID
+
+	log.Printf("Handling Tournament Leaderboard Message id '%s' for Tournament ID: %d", msg.ID, payload.TournamentID)
+
+	leaderboard, err := c.tournaments.Leaderboard(payload.TournamentID)
+	if err != nil {
+		return err
+	}
+	c.hub.join(tournamentRoom(payload.TournamentID), c)
+	return c.reply(msg, domain.MessageTypeTournamentLeaderboard, leaderboard)
+}
+
+// broadcastLeaderboard pushes the live ranking of a tournament to every connection following it
+func (c *connection) broadcastLeaderboard(tournamentID int) {
+	leaderboard, err := c.tournaments.Leaderboard(tournamentID)
+	if err != nil {
+		log.Printf("Error getting leaderboard of tournament %d: %v", tournamentID, err)
+		return
+	}
+	c.hub.broadcastRoom(tournamentRoom(tournamentID), domain.MessageTypeTournamentLeaderboard, leaderboard)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"log"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// achievementDefinition pairs an achievement with the check unlocking it
+type achievementDefinition struct {
+	achievement domain.Achievement
+	unlocked    func(stats domain.PlayerStats) bool
+}
+
+// achievementDefinitions lists every achievement in the order they are reported to players
+var achievementDefinitions = []achievementDefinition{
+	{
+		achievement: domain.Achievement{ID: domain.AchievementWinStreak, Name: "On Fire", Description: "Win five rounds in a row"},
+		unlocked:    func(stats domain.PlayerStats) bool { return stats.WinStreak >= 5 },
+	},
+	{
+		achievement: domain.Achievement{ID: domain.AchievementRoundsPlayed, Name: "Regular", Description: "Play 100 rounds"},
+		unlocked:    func(stats domain.PlayerStats) bool { return stats.RoundsPlayed >= 100 },
+	},
+	{
+		achievement: domain.Achievement{ID: domain.AchievementFirstExactHit, Name: "Bullseye", Description: "Win an exact number bet"},
+		unlocked:    func(stats domain.PlayerStats) bool { return stats.ExactHits >= 1 },
+	},
+}
+
+// ListAchievements returns every achievement with the unlock time of those the player earned
+func (gs *GameService) ListAchievements(playerID int) (domain.AchievementsResponse, error) {
+	unlocked, err := gs.repo.GetUnlockedAchievements(playerID)
+	if err != nil {
+		return domain.AchievementsResponse{}, wrapRepositoryError("Error while listing achievements", err)
+	}
+
+	achievements := make([]domain.Achievement, 0, len(achievementDefinitions))
+	for _, definition := range achievementDefinitions {
+		achievement := definition.achievement
+		if unlockedAt, ok := unlocked[achievement.ID]; ok {
+			achievement.UnlockedAt = &unlockedAt
+		}
+		achievements = append(achievements, achievement)
+	}
+	return domain.AchievementsResponse{ClientID: playerID, Achievements: achievements}, nil
+}
+
+// evaluateAchievements checks the settled rounds of a player after a play and returns the achievements it unlocked,
+// each achievement is returned by the one play that recorded it
+// The play is already settled, so failures are only logged and the achievement is checked again after the next play
+func (gs *GameService) evaluateAchievements(playerID int) []domain.Achievement {
+	stats, err := gs.repo.GetPlayerStats(playerID)
+	if err != nil {
+		log.Printf("Error loading stats of client id %d: %v", playerID, err)
+		return nil
+	}
+	unlocked, err := gs.repo.GetUnlockedAchievements(playerID)
+	if err != nil {
+		log.Printf("Error loading achievements of client id %d: %v", playerID, err)
+		return nil
+	}
+
+	var achievements []domain.Achievement
+	for _, definition := range achievementDefinitions {
+		if _, ok := unlocked[definition.achievement.ID]; ok || !definition.unlocked(stats) {
+			continue
+		}
+		unlockedAt, unlockedNow, err := gs.repo.UnlockAchievement(playerID, definition.achievement.ID)
+		if err != nil {
+			log.Printf("Error unlocking achievement %s for client id %d: %v", definition.achievement.ID, playerID, err)
+			continue
+		}
+		// A concurrent play of the player unlocked it first and announces it
+		if !unlockedNow {
+			continue
+		}
+		achievement := definition.achievement
+		achievement.UnlockedAt = &unlockedAt
+		achievements = append(achievements, achievement)
+	}
+	return achievements
+}
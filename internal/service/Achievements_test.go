@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEvaluateAchievements(t *testing.T) {
+	unlockedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		stats         domain.PlayerStats
+		unlocked      map[domain.AchievementID]time.Time
+		concurrent    bool
+		expected      []domain.AchievementID
+		expectedCalls int
+	}{
+		{
+			name:  "nothing_reached",
+			stats: domain.PlayerStats{RoundsPlayed: 10, WinStreak: 4},
+		},
+		{
+			name:          "streak_and_exact_hit_reached",
+			stats:         domain.PlayerStats{RoundsPlayed: 10, WinStreak: 5, ExactHits: 1},
+			expected:      []domain.AchievementID{domain.AchievementWinStreak, domain.AchievementFirstExactHit},
+			expectedCalls: 2,
+		},
+		{
+			name:          "already_unlocked_is_skipped",
+			stats:         domain.PlayerStats{RoundsPlayed: 100, WinStreak: 6},
+			unlocked:      map[domain.AchievementID]time.Time{domain.AchievementWinStreak: unlockedAt},
+			expected:      []domain.AchievementID{domain.AchievementRoundsPlayed},
+			expectedCalls: 1,
+		},
+		{
+			name:          "unlocked_by_concurrent_play_is_not_announced",
+			stats:         domain.PlayerStats{RoundsPlayed: 10, WinStreak: 5},
+			concurrent:    true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("GetPlayerStats", 1).Return(tt.stats, nil)
+			mockRepo.On("GetUnlockedAchievements", 1).Return(tt.unlocked, nil)
+			mockRepo.On("UnlockAchievement", 1, mock.Anything).Return(unlockedAt, !tt.concurrent, nil)
+			service := NewGameService(mockRepo, TestGameConfig)
+
+			achievements := service.evaluateAchievements(1)
+
+			var ids []domain.AchievementID
+			for _, achievement := range achievements {
+				ids = append(ids, achievement.ID)
+				assert.Equal(t, unlockedAt, *achievement.UnlockedAt)
+			}
+			assert.Equal(t, tt.expected, ids)
+			mockRepo.AssertNumberOfCalls(t, "UnlockAchievement", tt.expectedCalls)
+		})
+	}
+}
+
+func TestProcessPlay_UnlocksWinStreak(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 1000)
+	gs := NewGameService(repo, TestGameConfig)
+	dice := NewScriptedDice([]int{2})
+	play := domain.PlayRequest{ClientID: 1, BetAmount: TestMinBet, BetType: domain.Even}
+
+	for i := 0; i < 4; i++ {
+		result, err := gs.ProcessPlay(play, dice)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Achievements)
+	}
+	result, err := gs.ProcessPlay(play, dice)
+	assert.NoError(t, err)
+	if assert.Len(t, result.Achievements, 1) {
+		assert.Equal(t, domain.AchievementWinStreak, result.Achievements[0].ID)
+	}
+
+	result, err = gs.ProcessPlay(play, dice)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Achievements)
+
+	list, err := gs.ListAchievements(1)
+	assert.NoError(t, err)
+	assert.Len(t, list.Achievements, len(achievementDefinitions))
+	assert.NotNil(t, list.Achievements[0].UnlockedAt)
+	assert.Nil(t, list.Achievements[1].UnlockedAt)
+}
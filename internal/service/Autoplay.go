@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// ValidateAutoplay checks the series length and stop conditions before any round is played
+// Bet amount and type are validated by ProcessPlay on every round
+func (gs *GameService) ValidateAutoplay(req domain.AutoplayRequest) error {
+	if req.Rounds < 1 {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("autoplay rounds must be between 1 and %d", gs.conf.Autoplay.MaxRounds)).
+			WithField("rounds", appErrors.ConstraintMin, "1").
+			WithDetail(appErrors.DetailAutoplayRounds, gs.conf.Autoplay.MaxRounds)
+	}
+	if req.Rounds > gs.conf.Autoplay.MaxRounds {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("autoplay rounds must be between 1 and %d", gs.conf.Autoplay.MaxRounds)).
+			WithField("rounds", appErrors.ConstraintMax, fmt.Sprint(gs.conf.Autoplay.MaxRounds)).
+			WithDetail(appErrors.DetailAutoplayRounds, gs.conf.Autoplay.MaxRounds)
+	}
+
+	err := appErrors.NewInvalidInputError("autoplay stop conditions cannot be negative").WithDetail(appErrors.DetailStopConditions)
+	stopConditions := []struct {
+		field string
+		value float64
+	}{
+		{"stop_on_loss", req.StopOnLoss},
+		{"stop_on_win", req.StopOnWin},
+		{"stop_on_balance_below", req.StopOnBalanceBelow},
+	}
+	for _, condition := range stopConditions {
+		if condition.value < 0 {
+			err.WithField(condition.field, appErrors.ConstraintMin, "0")
+		}
+	}
+	if len(err.Fields) > 0 {
+		return err
+	}
+	return nil
+}
+
+// AutoplayStopReason evaluates the stop conditions after a round, netResult being the total of the series so far
+// Returns an empty reason when the series should continue
+func AutoplayStopReason(req domain.AutoplayRequest, netResult float64, round domain.PlayResponse) domain.AutoplayStopReason {
+	switch {
+	case req.StopOnLoss > 0 && -netResult > req.StopOnLoss:
+		return domain.AutoplayLossLimit
+	case req.StopOnWin > 0 && round.NetResult > req.StopOnWin:
+		return domain.AutoplayWinLimit
+	case req.StopOnBalanceBelow > 0 && round.Balance < req.StopOnBalanceBelow:
+		return domain.AutoplayBalanceLimit
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoplayStopReason(t *testing.T) {
+	req := domain.AutoplayRequest{
+		StopOnLoss:         50,
+		StopOnWin:          80,
+		StopOnBalanceBelow: 100,
+	}
+
+	tests := []struct {
+		name           string
+		req            domain.AutoplayRequest
+		netResult      float64
+		round          domain.PlayResponse
+		expectedReason domain.AutoplayStopReason
+	}{
+		{
+			name:           "within_limits",
+			req:            req,
+			netResult:      -40,
+			round:          domain.PlayResponse{NetResult: -10, Balance: 500},
+			expectedReason: "",
+		},
+		{
+			name:           "loss_over_limit",
+			req:            req,
+			netResult:      -60,
+			round:          domain.PlayResponse{NetResult: -10, Balance: 500},
+			expectedReason: domain.AutoplayLossLimit,
+		},
+		{
+			name:           "single_win_over_limit",
+			req:            req,
+			netResult:      90,
+			round:          domain.PlayResponse{NetResult: 90, Balance: 500},
+			expectedReason: domain.AutoplayWinLimit,
+		},
+		{
+			name:           "balance_below_limit",
+			req:            req,
+			netResult:      -10,
+			round:          domain.PlayResponse{NetResult: -10, Balance: 90},
+			expectedReason: domain.AutoplayBalanceLimit,
+		},
+		{
+			name:           "disabled_conditions",
+			req:            domain.AutoplayRequest{},
+			netResult:      -1000,
+			round:          domain.PlayResponse{NetResult: -10, Balance: 0},
+			expectedReason: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedReason, AutoplayStopReason(tt.req, tt.netResult, tt.round))
+		})
+	}
+}
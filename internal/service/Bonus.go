@@ -0,0 +1,50 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// GrantBonus credits bonus funds that convert to cash once wagered wageringMultiplier times before they expire
+func (gs *GameService) GrantBonus(playerID int, amount, wageringMultiplier float64, validFor time.Duration) (domain.PlayerBonus, error) {
+	log.Printf("\nGranting bonus of %g to client id -> %d", amount, playerID)
+	if amount <= 0 {
+		return domain.PlayerBonus{}, appErrors.NewBonusError(fmt.Sprintf("bonus amount must be positive: %.2f", amount)).WithDetail(appErrors.DetailBonusAmount, domain.Amount(amount))
+	}
+	if wageringMultiplier < 0 {
+		return domain.PlayerBonus{}, appErrors.NewBonusError(fmt.Sprintf("wagering multiplier cannot be negative: %g", wageringMultiplier)).WithDetail(appErrors.DetailBonusWagering, wageringMultiplier)
+	}
+	if validFor <= 0 {
+		return domain.PlayerBonus{}, appErrors.NewBonusError("bonus must be valid for a positive duration").WithDetail(appErrors.DetailBonusValidity)
+	}
+
+	bonus, err := gs.repo.GrantBonus(domain.PlayerBonus{
+		PlayerID:            playerID,
+		GrantedAmount:       amount,
+		WageringRequirement: amount * wageringMultiplier,
+		ExpiresAt:           time.Now().Add(validFor),
+	})
+	if err != nil {
+		return domain.PlayerBonus{}, wrapRepositoryError("Error while granting bonus", err)
+	}
+	return bonus, nil
+}
+
+// ForfeitExpiredBonuses forfeits the remaining funds of every bonus that expired before being wagered through
+func (gs *GameService) ForfeitExpiredBonuses() (int64, error) {
+	forfeited, err := gs.repo.ForfeitExpiredBonuses()
+	if err != nil {
+		return 0, wrapRepositoryError("Error while forfeiting expired bonuses", err)
+	}
+	return forfeited, nil
+}
+
+// roundCents rounds an amount to the cent, the precision balances are stored with
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
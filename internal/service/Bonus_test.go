@@ -0,0 +1,105 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessPlay_SplitsStakeBetweenWallets(t *testing.T) {
+	tests := []struct {
+		name                 string
+		stakeOrder           string
+		cash                 float64
+		bonus                float64
+		diceResult           int
+		expectedBalance      float64
+		expectedBonusBalance float64
+	}{
+		{
+			name: "no_bonus",
+			cash: 200, diceResult: 2,
+			expectedBalance: 290,
+		},
+		{
+			name:       "cash_first_covered_by_cash",
+			stakeOrder: string(domain.BonusStakeCashFirst),
+			cash:       200, bonus: 80, diceResult: 2,
+			expectedBalance: 290, expectedBonusBalance: 80,
+		},
+		{
+			name:       "cash_first_remainder_from_bonus",
+			stakeOrder: string(domain.BonusStakeCashFirst),
+			cash:       60, bonus: 80, diceResult: 2,
+			expectedBalance: 114, expectedBonusBalance: 116,
+		},
+		{
+			name:       "bonus_first_loss",
+			stakeOrder: string(domain.BonusStakeBonusFirst),
+			cash:       200, bonus: 80, diceResult: 1,
+			expectedBalance: 180,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			repo.AddPlayer(1, tt.cash)
+			conf := TestGameConfig
+			conf.Bonus = config.BonusConfig{StakeOrder: tt.stakeOrder}
+			gs := NewGameService(repo, conf)
+			if tt.bonus > 0 {
+				_, err := gs.GrantBonus(1, tt.bonus, 10, time.Hour)
+				assert.NoError(t, err)
+			}
+
+			// The stake is split from the balances read when the play is settled
+			play := domain.PlayRequest{ClientID: 1, BetAmount: TestValidBet, BetType: domain.Even}
+			result, err := gs.ProcessPlay(play, NewScriptedDice([]int{tt.diceResult}))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBalance, result.Balance)
+			assert.Equal(t, tt.expectedBonusBalance, result.BonusBalance)
+		})
+	}
+}
+
+func TestProcessPlay_BonusWagering(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 0)
+
+	conf := TestGameConfig
+	conf.Bonus = config.BonusConfig{StakeOrder: string(domain.BonusStakeBonusFirst)}
+	gs := NewGameService(repo, conf)
+
+	_, err := gs.GrantBonus(1, 100, 2, time.Hour)
+	assert.NoError(t, err)
+	_, err = gs.GrantBonus(1, 100, 2, time.Hour)
+	assert.Error(t, err)
+
+	// Wins are credited back to the bonus wallet until the 200 wagering requirement is met
+	play := domain.PlayRequest{ClientID: 1, BetAmount: TestValidBet, BetType: domain.Even}
+	dice := NewScriptedDice([]int{2})
+
+	result, err := gs.ProcessPlay(play, dice)
+	assert.NoError(t, err)
+	assert.Equal(t, 190.0, result.BonusBalance)
+	assert.Zero(t, result.Balance)
+
+	result, err = gs.ProcessPlay(play, dice)
+	assert.NoError(t, err)
+	assert.Equal(t, 280.0, result.BonusConverted)
+	assert.Equal(t, 280.0, result.Balance)
+	summary, err := gs.EndPlay(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.RoundsPlayed)
+
+	wallet, err := gs.GetBalance(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 280.0, wallet.Balance)
+	assert.Zero(t, wallet.BonusBalance)
+	assert.Nil(t, wallet.Bonus)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// AckSettlements acknowledges the settlement messages of the player up to and including the sequence number
+func (gs *GameService) AckSettlements(req domain.AckRequest) (domain.AckResponse, error) {
+	if req.Seq <= 0 {
+		return domain.AckResponse{}, appErrors.NewInvalidInputError(fmt.Sprintf("invalid sequence number: %d", req.Seq)).WithDetail(appErrors.DetailInvalidSeq, req.Seq).
+			WithField("seq", appErrors.ConstraintMin, "1")
+	}
+	if err := gs.repo.AckSettlementMessages(req.ClientID, req.Seq); err != nil {
+		return domain.AckResponse{}, wrapRepositoryError("Error while acknowledging settlement messages", err)
+	}
+	return domain.AckResponse{ClientID: req.ClientID, Seq: req.Seq}, nil
+}
+
+// PendingSettlements returns the settlement messages the player has not acknowledged, oldest first
+func (gs *GameService) PendingSettlements(playerID int) ([]domain.SettlementMessage, error) {
+	messages, err := gs.repo.ListUnackedMessages(playerID)
+	if err != nil {
+		return nil, wrapRepositoryError("Error while listing settlement messages", err)
+	}
+	return messages, nil
+}
+
+// PurgeExpiredSettlements drops the settlement messages kept for longer than the retention,
+// clients that never acknowledge would otherwise grow them forever
+func (gs *GameService) PurgeExpiredSettlements(retention time.Duration) (int64, error) {
+	purged, err := gs.repo.PurgeSettlementMessages(time.Now().Add(-retention))
+	if err != nil {
+		return 0, wrapRepositoryError("Error while purging settlement messages", err)
+	}
+	return purged, nil
+}
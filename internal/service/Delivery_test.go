@@ -0,0 +1,143 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAckSettlements(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     domain.AckRequest
+		expectedErr int
+	}{
+		{name: "acknowledges_up_to_seq", request: domain.AckRequest{ClientID: 1, Seq: 3}},
+		{name: "invalid_seq", request: domain.AckRequest{ClientID: 1, Seq: 0}, expectedErr: appErrors.InvalidInputErrorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("AckSettlementMessages", 1, int64(3)).Return(nil)
+			service := NewGameService(mockRepo, TestGameConfig)
+
+			res, err := service.AckSettlements(tt.request)
+
+			if tt.expectedErr != 0 {
+				assert.Equal(t, tt.expectedErr, err.(*appErrors.GameError).Code)
+				mockRepo.AssertNotCalled(t, "AckSettlementMessages", 1, int64(0))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domain.AckResponse{ClientID: 1, Seq: 3}, res)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPendingSettlements_KeepsUnacknowledged(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 1000)
+	gs := NewGameService(repo, TestGameConfig)
+
+	for i := 0; i < 3; i++ {
+		_, err := gs.ProcessPlay(domain.PlayRequest{ClientID: 1, BetAmount: TestValidBet, BetType: domain.Odd}, NewScriptedDice([]int{1}))
+		assert.NoError(t, err)
+	}
+	_, err := gs.AckSettlements(domain.AckRequest{ClientID: 1, Seq: 2})
+	assert.NoError(t, err)
+
+	pending, err := gs.PendingSettlements(1)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, int64(3), pending[0].Seq)
+		assert.Contains(t, string(pending[0].Payload), `"round_id":3`)
+	}
+}
+
+func TestProcessPlay_StoresSettlementMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		autoplayRound int
+		expectedType  domain.MessageType
+	}{
+		{name: "play", expectedType: domain.MessageTypePlay},
+		{name: "autoplay_round", autoplayRound: 2, expectedType: domain.MessageTypeAutoplayRound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			repo.AddPlayer(1, 1000)
+			gs := NewGameService(repo, TestGameConfig)
+
+			res, err := gs.ProcessPlay(domain.PlayRequest{ClientID: 1, BetAmount: TestValidBet, BetType: domain.Odd, AutoplayRound: tt.autoplayRound}, NewScriptedDice([]int{1}))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), res.Seq)
+
+			pending, err := gs.PendingSettlements(1)
+			assert.NoError(t, err)
+			if assert.Len(t, pending, 1) {
+				assert.Equal(t, res.Seq, pending[0].Seq)
+				assert.Equal(t, tt.expectedType, pending[0].Type)
+				assert.Contains(t, string(pending[0].Payload), `"round_id":1`)
+			}
+		})
+	}
+}
+
+func TestPurgeExpiredSettlements(t *testing.T) {
+	tests := []struct {
+		name           string
+		retention      time.Duration
+		expectedPurged int64
+	}{
+		{name: "keeps_messages_within_retention", retention: time.Hour, expectedPurged: 0},
+		{name: "purges_expired_messages", retention: -time.Second, expectedPurged: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			repo.AddPlayer(1, 1000)
+			gs := NewGameService(repo, TestGameConfig)
+			for i := 0; i < 2; i++ {
+				_, err := gs.ProcessPlay(domain.PlayRequest{ClientID: 1, BetAmount: TestValidBet, BetType: domain.Odd}, NewScriptedDice([]int{1}))
+				assert.NoError(t, err)
+			}
+
+			purged, err := gs.PurgeExpiredSettlements(tt.retention)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPurged, purged)
+
+			pending, err := gs.PendingSettlements(1)
+			assert.NoError(t, err)
+			assert.Len(t, pending, 2-int(tt.expectedPurged))
+		})
+	}
+}
+
+func TestPendingSettlements_MemoryRepositoryKeepsLatest(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	repo.AddPlayer(1, 100000)
+	gs := NewGameService(repo, TestGameConfig)
+
+	var last domain.PlayResponse
+	for i := 0; i < 150; i++ {
+		res, err := gs.ProcessPlay(domain.PlayRequest{ClientID: 1, BetAmount: TestValidBet, BetType: domain.Odd}, NewScriptedDice([]int{1}))
+		assert.NoError(t, err)
+		last = res
+	}
+
+	pending, err := gs.PendingSettlements(1)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 100) {
+		assert.Equal(t, last.Seq-99, pending[0].Seq)
+		assert.Equal(t, last.Seq, pending[99].Seq)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	mathrand "math/rand/v2"
+	"sync"
+
+	"github.com/Desgue/SpicyDice/internal/config"
+)
+
+// Dice modes selectable through configuration
+const (
+	DiceModeCrypto   = "crypto"
+	DiceModeSeeded   = "seeded"
+	DiceModeScripted = "scripted"
+)
+
+// Environments where deterministic dice are allowed, every other environment, including a misspelt one, requires crypto dice
+const (
+	DevelopmentEnvironment = "dev"
+	TestEnvironment        = "test"
+)
+
+// DiceRoller defines the contract for dice rolling implementations
+type DiceRoller interface {
+	Roll() (int, error)
+}
+
+// Dice implements secure random number generation for fair game outcomes
+type Dice struct {
+	Sides int
+}
+
+// Roll uses crypto/rand for cryptographically secure random number generation
+func (d Dice) Roll() (int, error) {
+	bigI, err := rand.Int(rand.Reader, big.NewInt(int64(d.Sides)))
+	if err != nil {
+		return 0, err
+	}
+	roll := int(bigI.Int64()) + 1
+	return roll, nil
+}
+
+// SeededDice rolls from a PCG source so the same seed always produces the same sequence
+// Not suitable for real money play, intended for replays, simulations and QA
+type SeededDice struct {
+	mu    sync.Mutex
+	sides int
+	rng   *mathrand.Rand
+}
+
+func NewSeededDice(sides int, seed uint64) *SeededDice {
+	return &SeededDice{
+		sides: sides,
+		rng:   mathrand.New(mathrand.NewPCG(seed, seed)),
+	}
+}
+
+// Roll returns the next value of the seeded sequence
+func (d *SeededDice) Roll() (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rng.IntN(d.sides) + 1, nil
+}
+
+// ScriptedDice returns a fixed sequence of rolls, starting over once it is exhausted
+type ScriptedDice struct {
+	mu    sync.Mutex
+	rolls []int
+	next  int
+}
+
+func NewScriptedDice(rolls []int) *ScriptedDice {
+	return &ScriptedDice{rolls: rolls}
+}
+
+// Roll returns the next scripted value
+func (d *ScriptedDice) Roll() (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.rolls) == 0 {
+		return 0, fmt.Errorf("dice script is empty")
+	}
+	roll := d.rolls[d.next]
+	d.next = (d.next + 1) % len(d.rolls)
+	return roll, nil
+}
+
+// NewDiceFactory validates the dice configuration and returns a constructor for the configured mode
+// Every call of the constructor starts a fresh sequence, so deterministic flows can be replayed per connection
+func NewDiceFactory(environment string, conf config.DiceConfig) (func() DiceRoller, error) {
+	if conf.Mode != DiceModeCrypto && environment != DevelopmentEnvironment && environment != TestEnvironment {
+		return nil, fmt.Errorf("dice mode %s is only allowed in the %s and %s environments, not in %q", conf.Mode, DevelopmentEnvironment, TestEnvironment, environment)
+	}
+
+	switch conf.Mode {
+	case DiceModeCrypto:
+		return func() DiceRoller { return Dice{Sides: DiceSides} }, nil
+	case DiceModeSeeded:
+		return func() DiceRoller { return NewSeededDice(DiceSides, conf.Seed) }, nil
+	case DiceModeScripted:
+		if len(conf.Script) == 0 {
+			return nil, fmt.Errorf("dice mode %s requires a non empty script", DiceModeScripted)
+		}
+		for _, roll := range conf.Script {
+			if roll < 1 || roll > DiceSides {
+				return nil, fmt.Errorf("scripted roll %d is not between 1 and %d", roll, DiceSides)
+			}
+		}
+		return func() DiceRoller { return NewScriptedDice(conf.Script) }, nil
+	default:
+		return nil, fmt.Errorf("unknown dice mode: %s", conf.Mode)
+	}
+}
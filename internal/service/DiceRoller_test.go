@@ -0,0 +1,105 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeededDice_SameSeedSameSequence(t *testing.T) {
+	first, second := NewSeededDice(DiceSides, 42), NewSeededDice(DiceSides, 42)
+
+	for i := 0; i < 100; i++ {
+		firstRoll, err := first.Roll()
+		assert.NoError(t, err)
+		secondRoll, err := second.Roll()
+		assert.NoError(t, err)
+
+		assert.Equal(t, firstRoll, secondRoll)
+		assert.GreaterOrEqual(t, firstRoll, 1)
+		assert.LessOrEqual(t, firstRoll, DiceSides)
+	}
+}
+
+func TestScriptedDice_RepeatsScript(t *testing.T) {
+	dice := NewScriptedDice([]int{6, 1, 3})
+
+	var rolls []int
+	for i := 0; i < 5; i++ {
+		roll, err := dice.Roll()
+		assert.NoError(t, err)
+		rolls = append(rolls, roll)
+	}
+
+	assert.Equal(t, []int{6, 1, 3, 6, 1}, rolls)
+}
+
+func TestNewDiceFactory(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		conf        config.DiceConfig
+		expectError bool
+	}{
+		{
+			name:        "crypto_in_production",
+			environment: "production",
+			conf:        config.DiceConfig{Mode: DiceModeCrypto},
+		},
+		{
+			name:        "seeded_in_production_is_refused",
+			environment: "production",
+			conf:        config.DiceConfig{Mode: DiceModeSeeded, Seed: 1},
+			expectError: true,
+		},
+		{
+			name:        "seeded_in_staging_is_refused",
+			environment: "staging",
+			conf:        config.DiceConfig{Mode: DiceModeSeeded, Seed: 1},
+			expectError: true,
+		},
+		{
+			name:        "scripted_in_misspelt_production_is_refused",
+			environment: "prod",
+			conf:        config.DiceConfig{Mode: DiceModeScripted, Script: []int{6}},
+			expectError: true,
+		},
+		{
+			name:        "seeded_in_dev",
+			environment: DevelopmentEnvironment,
+			conf:        config.DiceConfig{Mode: DiceModeSeeded, Seed: 1},
+		},
+		{
+			name:        "scripted_in_test",
+			environment: TestEnvironment,
+			conf:        config.DiceConfig{Mode: DiceModeScripted, Script: []int{6, 1}},
+		},
+		{
+			name:        "scripted_with_invalid_face",
+			environment: TestEnvironment,
+			conf:        config.DiceConfig{Mode: DiceModeScripted, Script: []int{1, 7}},
+			expectError: true,
+		},
+		{
+			name:        "unknown_mode",
+			environment: DevelopmentEnvironment,
+			conf:        config.DiceConfig{Mode: "loaded"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newDice, err := NewDiceFactory(tt.environment, tt.conf)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, newDice)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, newDice())
+			}
+		})
+	}
+}
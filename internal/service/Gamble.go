@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// GambleEnabled reports whether players are offered the double-or-nothing gamble after a win
+func (gs *GameService) GambleEnabled() bool {
+	return gs.conf.Gamble.MaxSteps > 0
+}
+
+// Gamble rolls the open gamble of the player at double-or-nothing, starting one on the winnings of the named round
+// when none is open. A win multiplies the amount by the gamble multiplier and is collected automatically
+// at the top of the ladder, a loss ends it
+func (gs *GameService) Gamble(req domain.GambleRequest, dice DiceRoller) (domain.GambleResponse, error) {
+	log.Printf("\nGambling for client id -> %d on %s", req.ClientID, req.BetType)
+	if !gs.GambleEnabled() {
+		return domain.GambleResponse{}, appErrors.NewGambleError("gambling is disabled").WithDetail(appErrors.DetailGambleDisabled)
+	}
+	if req.BetType != domain.Even && req.BetType != domain.Odd {
+		return domain.GambleResponse{}, appErrors.NewInvalidInputError(fmt.Sprintf("gamble bet type must be even or odd: %s", req.BetType)).WithDetail(appErrors.DetailGambleBetType, req.BetType).
+			WithField("bet_type", appErrors.ConstraintOneOf, fmt.Sprintf("%s,%s", domain.Even, domain.Odd))
+	}
+
+	gamble, err := gs.openGamble(req)
+	if err != nil {
+		return domain.GambleResponse{}, err
+	}
+
+	diceResult, err := dice.Roll()
+	if err != nil {
+		return domain.GambleResponse{}, appErrors.NewDiceRollError(err.Error())
+	}
+	won := calculateOutcome(domain.PlayRequest{BetType: req.BetType}, diceResult)
+
+	next := gamble
+	next.Step++
+	switch {
+	case !won:
+		next.Amount = 0
+		next.Status = domain.GambleLost
+	case next.Step >= gs.conf.Gamble.MaxSteps:
+		next.Amount = roundCents(gamble.Amount * gs.conf.Gamble.Multiplier)
+		next.Status = domain.GambleCollected
+	default:
+		next.Amount = roundCents(gamble.Amount * gs.conf.Gamble.Multiplier)
+	}
+
+	res, err := gs.repo.SettleGamble(domain.GambleResponse{
+		ClientID:   req.ClientID,
+		Gamble:     next,
+		DiceResult: diceResult,
+		Won:        won,
+		MaxSteps:   gs.conf.Gamble.MaxSteps,
+	}, gamble.Step, domain.MessageTypeGamble)
+	if err != nil {
+		return domain.GambleResponse{}, wrapRepositoryError("Error while settling gamble", err)
+	}
+	return res, nil
+}
+
+// CollectGamble ends the open gamble of the player and credits its amount
+func (gs *GameService) CollectGamble(clientID int) (domain.GambleResponse, error) {
+	log.Printf("\nCollecting gamble for client id -> %d", clientID)
+	gamble, err := gs.repo.GetOpenGamble(clientID)
+	if err != nil {
+		return domain.GambleResponse{}, wrapRepositoryError("Error while loading gamble", err)
+	}
+	if gamble == nil {
+		return domain.GambleResponse{}, appErrors.NewGambleError(fmt.Sprintf("client ID %d has no open gamble", clientID)).WithDetail(appErrors.DetailGambleNotOpen, clientID)
+	}
+	return gs.collect(*gamble)
+}
+
+// collect credits the current amount of an open gamble
+func (gs *GameService) collect(gamble domain.Gamble) (domain.GambleResponse, error) {
+	next := gamble
+	next.Status = domain.GambleCollected
+	res, err := gs.repo.SettleGamble(domain.GambleResponse{
+		ClientID: gamble.PlayerID,
+		Gamble:   next,
+		Won:      next.Amount > 0,
+		MaxSteps: gs.conf.Gamble.MaxSteps,
+	}, gamble.Step, domain.MessageTypeGambleCollect)
+	if err != nil {
+		return domain.GambleResponse{}, wrapRepositoryError("Error while collecting gamble", err)
+	}
+	return res, nil
+}
+
+// openGamble returns the open gamble of the player or starts one on the round named by the request
+func (gs *GameService) openGamble(req domain.GambleRequest) (domain.Gamble, error) {
+	gamble, err := gs.repo.GetOpenGamble(req.ClientID)
+	if err != nil {
+		return domain.Gamble{}, wrapRepositoryError("Error while loading gamble", err)
+	}
+	if gamble != nil {
+		if req.RoundID != 0 && req.RoundID != gamble.RoundID {
+			return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("the gamble on round %d must be collected or lost first", gamble.RoundID)).WithDetail(appErrors.DetailGamblePending, gamble.RoundID)
+		}
+		return *gamble, nil
+	}
+	if req.RoundID == 0 {
+		return domain.Gamble{}, appErrors.NewGambleError(fmt.Sprintf("client ID %d has no open gamble, a winning round must be named", req.ClientID)).WithDetail(appErrors.DetailGambleRoundRequired, req.ClientID)
+	}
+
+	started, err := gs.repo.StartGamble(req.RoundID, req.ClientID)
+	if err != nil {
+		return domain.Gamble{}, wrapRepositoryError("Error while starting gamble", err)
+	}
+	return started, nil
+}
+
+// checkNoOpenGamble keeps players from placing new bets while the winnings of their last round are being gambled
+func (gs *GameService) checkNoOpenGamble(clientID int) error {
+	if !gs.GambleEnabled() {
+		return nil
+	}
+	gamble, err := gs.repo.GetOpenGamble(clientID)
+	if err != nil {
+		return wrapRepositoryError("Error while loading gamble", err)
+	}
+	if gamble != nil {
+		return appErrors.NewActiveSessionError(fmt.Sprintf("Client ID %d must collect or finish the gamble on round %d first.", clientID, gamble.RoundID)).WithDetail(appErrors.DetailGambleOpen, clientID, gamble.RoundID)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func gambleConfig(maxSteps int) config.GameConfig {
+	conf := TestGameConfig
+	conf.Gamble = config.GambleConfig{MaxSteps: maxSteps, Multiplier: 1.96}
+	return conf
+}
+
+func TestGamble(t *testing.T) {
+	open := &domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Stake: 190, Amount: 380, Step: 1, Status: domain.GambleOpen}
+	started := domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Stake: 190, Amount: 190, Status: domain.GambleOpen}
+
+	tests := []struct {
+		name           string
+		request        domain.GambleRequest
+		openGamble     *domain.Gamble
+		diceResult     int
+		expectedGamble domain.Gamble
+		expectedErr    int
+	}{
+		{
+			name:           "first_roll_starts_gamble_and_multiplies",
+			request:        domain.GambleRequest{ClientID: 1, RoundID: 7, BetType: domain.Even},
+			diceResult:     4,
+			expectedGamble: domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Stake: 190, Amount: 372.4, Step: 1, Status: domain.GambleOpen},
+		},
+		{
+			name:           "loss_ends_gamble",
+			request:        domain.GambleRequest{ClientID: 1, BetType: domain.Even},
+			openGamble:     open,
+			diceResult:     3,
+			expectedGamble: domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Stake: 190, Amount: 0, Step: 2, Status: domain.GambleLost},
+		},
+		{
+			name:           "win_at_ladder_cap_is_collected",
+			request:        domain.GambleRequest{ClientID: 1, BetType: domain.Odd},
+			openGamble:     &domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Stake: 190, Amount: 380, Step: 2, Status: domain.GambleOpen},
+			diceResult:     5,
+			expectedGamble: domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Stake: 190, Amount: 744.8, Step: 3, Status: domain.GambleCollected},
+		},
+		{
+			name:        "no_open_gamble_and_no_round",
+			request:     domain.GambleRequest{ClientID: 1, BetType: domain.Even},
+			expectedErr: appErrors.GambleErrorCode,
+		},
+		{
+			name:        "other_round_while_gamble_open",
+			request:     domain.GambleRequest{ClientID: 1, RoundID: 8, BetType: domain.Even},
+			openGamble:  open,
+			expectedErr: appErrors.GambleErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("GetOpenGamble", 1).Return(tt.openGamble, nil)
+			mockRepo.On("StartGamble", 7, 1).Return(started, nil)
+			settled := domain.GambleResponse{
+				ClientID:   1,
+				Gamble:     tt.expectedGamble,
+				DiceResult: tt.diceResult,
+				Won:        tt.expectedGamble.Status != domain.GambleLost,
+				MaxSteps:   3,
+			}
+			mockRepo.On("SettleGamble", settled, tt.expectedGamble.Step-1, domain.MessageTypeGamble).Return(domain.GambleResponse{Gamble: tt.expectedGamble, Balance: 500, Seq: 4}, nil)
+			service := NewGameService(mockRepo, gambleConfig(3))
+
+			res, err := service.Gamble(tt.request, NewScriptedDice([]int{tt.diceResult}))
+
+			if tt.expectedErr != 0 {
+				assert.Equal(t, tt.expectedErr, err.(*appErrors.GameError).Code)
+				mockRepo.AssertNotCalled(t, "SettleGamble", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedGamble, res.Gamble)
+			assert.Equal(t, 500.0, res.Balance)
+			assert.Equal(t, int64(4), res.Seq)
+			mockRepo.AssertCalled(t, "SettleGamble", settled, tt.expectedGamble.Step-1, domain.MessageTypeGamble)
+		})
+	}
+}
+
+func TestGamble_Disabled(t *testing.T) {
+	mockRepo := new(repository.MockRepository)
+	service := NewGameService(mockRepo, gambleConfig(0))
+
+	_, err := service.Gamble(domain.GambleRequest{ClientID: 1, RoundID: 7, BetType: domain.Even}, NewScriptedDice([]int{4}))
+
+	assert.Equal(t, appErrors.GambleErrorCode, err.(*appErrors.GameError).Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCollectGamble(t *testing.T) {
+	open := &domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Stake: 190, Amount: 380, Step: 1, Status: domain.GambleOpen}
+	collected := *open
+	collected.Status = domain.GambleCollected
+
+	mockRepo := new(repository.MockRepository)
+	mockRepo.On("GetOpenGamble", 1).Return(open, nil)
+	settled := domain.GambleResponse{ClientID: 1, Gamble: collected, Won: true, MaxSteps: 3}
+	mockRepo.On("SettleGamble", settled, 1, domain.MessageTypeGambleCollect).Return(domain.GambleResponse{ClientID: 1, Gamble: collected, Won: true, Balance: 880, MaxSteps: 3, Seq: 5}, nil)
+	service := NewGameService(mockRepo, gambleConfig(3))
+
+	res, err := service.CollectGamble(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, collected, res.Gamble)
+	assert.Equal(t, 880.0, res.Balance)
+	assert.Equal(t, int64(5), res.Seq)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessPlay_BlockedByOpenGamble(t *testing.T) {
+	mockRepo := new(repository.MockRepository)
+	mockRepo.On("GetOpenGamble", 1).Return(&domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Status: domain.GambleOpen}, nil)
+	service := NewGameService(mockRepo, gambleConfig(3))
+
+	_, err := service.ProcessPlay(domain.PlayRequest{ClientID: 1, BetAmount: TestValidBet, BetType: domain.Odd}, NewScriptedDice([]int{1}))
+
+	assert.Equal(t, appErrors.ActiveSessionErrorCode, err.(*appErrors.GameError).Code)
+	mockRepo.AssertNotCalled(t, "ProcessPlay", mock.Anything)
+}
+
+func TestEndPlay_CollectsOpenGamble(t *testing.T) {
+	open := &domain.Gamble{GambleID: 2, RoundID: 7, PlayerID: 1, Stake: 190, Amount: 380, Step: 1, Status: domain.GambleOpen}
+	collected := *open
+	collected.Status = domain.GambleCollected
+	settled := domain.GambleResponse{ClientID: 1, Gamble: collected, Won: true, MaxSteps: 3}
+	stored := settled
+	stored.Balance, stored.Seq = 880, 6
+
+	mockRepo := new(repository.MockRepository)
+	mockRepo.On("GetActiveSession", 1).Return(&domain.GameSession{SessionID: 3, PlayerID: 1, Active: true}, nil)
+	mockRepo.On("GetOpenGamble", 1).Return(open, nil)
+	mockRepo.On("SettleGamble", settled, 1, domain.MessageTypeGambleCollect).Return(stored, nil)
+	mockRepo.On("CloseCurrentGameSession", 1).Return(domain.SessionSummary{SessionID: 3}, nil)
+	service := NewGameService(mockRepo, gambleConfig(3))
+
+	res, err := service.EndPlay(1)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, res.CollectedGamble) {
+		assert.Equal(t, stored, *res.CollectedGamble)
+	}
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Desgue/SpicyDice/internal/config"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// DiceSides is the number of faces of the dice used by every game variant
+const DiceSides = 6
+
+// Game variants group the bet types that share the same payout multiplier
+const (
+	VariantParity = "parity"
+	VariantExact  = "exact"
+	// VariantGamble is the even/odd ladder offered on the winnings of a round, reported only when enabled
+	VariantGamble = "gamble"
+)
+
+// rtpTolerance is the accepted difference between the theoretical and the declared target RTP
+const rtpTolerance = 0.001
+
+// rtpEpsilon absorbs floating point error when comparing against the maximum RTP
+const rtpEpsilon = 1e-9
+
+// VariantRTP reports the theoretical return to player of a configured game variant
+type VariantRTP struct {
+	Variant        string
+	Multiplier     float64
+	TargetRTP      float64
+	TheoreticalRTP float64
+	JackpotRTP     float64
+}
+
+// variantBets lists every bet a player can place on each variant, used to compute its worst case RTP
+var variantBets = map[string][]domain.PlayRequest{
+	VariantParity: {
+		{BetType: domain.Even},
+		{BetType: domain.Odd},
+	},
+	VariantExact: exactBets(),
+}
+
+// exactBets builds one exact bet for each face of the dice
+func exactBets() []domain.PlayRequest {
+	bets := make([]domain.PlayRequest, 0, DiceSides)
+	for face := 1; face <= DiceSides; face++ {
+		bets = append(bets, domain.PlayRequest{BetType: domain.Exact, BetNumber: face})
+	}
+	return bets
+}
+
+// variantOf maps a bet type to the game variant defining its payout
+func variantOf(betType domain.BetType) string {
+	if betType == domain.Exact {
+		return VariantExact
+	}
+	return VariantParity
+}
+
+// CheckRTP computes the theoretical RTP of every game variant and of each gamble step, and refuses configurations
+// that are missing a variant, exceed the maximum RTP or drift from their declared target
+// The jackpot contribution is eventually paid back to players, so it counts towards the maximum
+// but not towards the target, which only covers the base game
+func CheckRTP(conf config.GameConfig) ([]VariantRTP, error) {
+	var jackpotRTP float64
+	if conf.Jackpot.Enabled {
+		jackpotRTP = conf.Jackpot.ContributionRate
+	}
+
+	reports := make([]VariantRTP, 0, len(variantBets))
+	for _, variant := range []string{VariantParity, VariantExact} {
+		variantConf, ok := conf.Variants[variant]
+		if !ok {
+			return nil, fmt.Errorf("missing payout configuration for variant %s", variant)
+		}
+
+		var theoreticalRTP float64
+		for _, bet := range variantBets[variant] {
+			theoreticalRTP = math.Max(theoreticalRTP, winProbability(bet)*variantConf.Multiplier)
+		}
+
+		report := VariantRTP{
+			Variant:        variant,
+			Multiplier:     variantConf.Multiplier,
+			TargetRTP:      variantConf.TargetRTP,
+			TheoreticalRTP: theoreticalRTP,
+			JackpotRTP:     jackpotRTP,
+		}
+		if theoreticalRTP+jackpotRTP > conf.MaxRTP+rtpEpsilon {
+			return nil, fmt.Errorf("variant %s has a theoretical RTP of %.4f which exceeds the maximum of %.4f", variant, theoreticalRTP+jackpotRTP, conf.MaxRTP)
+		}
+		if math.Abs(theoreticalRTP-variantConf.TargetRTP) > rtpTolerance {
+			return nil, fmt.Errorf("variant %s has a theoretical RTP of %.4f which does not match its target of %.4f", variant, theoreticalRTP, variantConf.TargetRTP)
+		}
+		reports = append(reports, report)
+	}
+
+	if conf.Gamble.MaxSteps > 0 {
+		gambleRTP := winProbability(domain.PlayRequest{BetType: domain.Even}) * conf.Gamble.Multiplier
+		if gambleRTP > conf.MaxRTP+rtpEpsilon {
+			return nil, fmt.Errorf("variant %s has a theoretical RTP of %.4f which exceeds the maximum of %.4f", VariantGamble, gambleRTP, conf.MaxRTP)
+		}
+		reports = append(reports, VariantRTP{
+			Variant:        VariantGamble,
+			Multiplier:     conf.Gamble.Multiplier,
+			TargetRTP:      gambleRTP,
+			TheoreticalRTP: gambleRTP,
+		})
+	}
+	return reports, nil
+}
+
+// winProbability enumerates the dice faces to find the chance of a bet being won
+func winProbability(bet domain.PlayRequest) float64 {
+	wins := 0
+	for face := 1; face <= DiceSides; face++ {
+		if calculateOutcome(bet, face) {
+			wins++
+		}
+	}
+	return float64(wins) / DiceSides
+}
+
+// calculateOutcome decides if the dice result wins the bet
+func calculateOutcome(bet domain.PlayRequest, diceResult int) bool {
+	switch bet.BetType {
+	case domain.Even:
+		return diceResult%2 == 0
+	case domain.Odd:
+		return diceResult%2 != 0
+	case domain.Exact:
+		return diceResult == bet.BetNumber
+	default:
+		return false
+	}
+}
+
+// settle computes the payout and the balance change of a bet using the multiplier of its variant
+func (gs *GameService) settle(bet domain.PlayRequest, won bool) (payout float64, changeAmount float64, err error) {
+	variant := variantOf(bet.BetType)
+	variantConf, ok := gs.conf.Variants[variant]
+	if !ok {
+		return 0, 0, fmt.Errorf("missing payout configuration for variant %s", variant)
+	}
+	if won {
+		payout = bet.BetAmount * variantConf.Multiplier
+	}
+	return payout, payout - bet.BetAmount, nil
+}
@@ -1,11 +1,9 @@
 package service
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"log"
-	"math/big"
 
 	"github.com/Desgue/SpicyDice/internal/appErrors"
 	"github.com/Desgue/SpicyDice/internal/config"
@@ -16,40 +14,98 @@ import (
 // GameService orchestrates game logic and wallet operations while maintaining transactional integrity
 type GameService struct {
 	repo repository.Repository
+	conf config.GameConfig
 }
 
-var (
-	MinBetAmount = config.New().Game.MinBetAmount
-	MaxBetAmount = config.New().Game.MaxBetAmount
-)
+// maxHistoryRounds caps the number of rounds returned by a single history request
+const maxHistoryRounds = 100
 
 // NewGameService follows the repository pattern for data persistence operations
-func NewGameService(repo repository.Repository) *GameService {
+// The game configuration provides the betting limits used when a player tier does not define its own
+func NewGameService(repo repository.Repository, conf config.GameConfig) *GameService {
 	return &GameService{
 		repo: repo,
+		conf: conf,
 	}
 }
 
-// GetBalance retrieves current balance ensuring player exists in the system
+// GetBalance retrieves current cash and bonus balances ensuring player exists in the system
 func (gs *GameService) GetBalance(playerID int) (domain.WalletResponse, error) {
 	log.Printf("\nGetting balance for client id -> %d", playerID)
 	balance, err := gs.repo.GetBalance(playerID)
 	if err != nil {
 		return domain.WalletResponse{}, appErrors.NewInternalError(err.Error())
 	}
-	return domain.WalletResponse{ClientID: playerID, Balance: balance}, nil
+	bonus, err := gs.repo.GetActiveBonus(playerID)
+	if err != nil {
+		return domain.WalletResponse{}, appErrors.NewInternalError(err.Error())
+	}
+
+	wallet := domain.WalletResponse{ClientID: playerID, Balance: balance, Bonus: bonus}
+	if bonus != nil {
+		wallet.BonusBalance = bonus.Amount
+	}
+	return wallet, nil
+}
+
+// GetBetLimits resolves the betting limits of a player from its tier, falling back to the global configuration
+func (gs *GameService) GetBetLimits(playerID int) (domain.LimitsResponse, error) {
+	log.Printf("\nGetting bet limits for client id -> %d", playerID)
+	tierLimits, err := gs.repo.GetTierLimits(playerID)
+	if err != nil {
+		gameErr := &appErrors.GameError{}
+		if errors.As(err, &gameErr) {
+			return domain.LimitsResponse{}, err
+		}
+		return domain.LimitsResponse{}, appErrors.NewInternalError(err.Error())
+	}
+
+	limits := domain.LimitsResponse{
+		ClientID:     playerID,
+		Tier:         tierLimits.Tier,
+		MinBetAmount: gs.conf.MinBetAmount,
+		MaxBetAmount: gs.conf.MaxBetAmount,
+	}
+	if tierLimits.MinBetAmount != nil {
+		limits.MinBetAmount = *tierLimits.MinBetAmount
+	}
+	if tierLimits.MaxBetAmount != nil {
+		limits.MaxBetAmount = *tierLimits.MaxBetAmount
+	}
+	return limits, nil
 }
 
 // ProcessPlay handles the complete game cycle: validation, dice roll, outcome calculation and balance update
-// Returns error if any game rules are violated or system errors occur
+// Returns error if any game rules are violated or system errors occur. Plays naming a free bet are funded by its promotion
 func (gs *GameService) ProcessPlay(msg domain.PlayRequest, dice DiceRoller) (domain.PlayResponse, error) {
+	if err := gs.checkNoOpenGamble(msg.ClientID); err != nil {
+		return domain.PlayResponse{}, err
+	}
+	if msg.FreeBetID != 0 {
+		return gs.processFreeBet(msg, dice)
+	}
 	log.Printf("\nProcessing play for user id -> %d\nBet Amount -> %g\nBet Type -> %s", msg.ClientID, msg.BetAmount, msg.BetType)
 
 	balance, err := gs.repo.GetBalance(msg.ClientID)
 	if err != nil {
 		return domain.PlayResponse{}, appErrors.NewInternalError(err.Error())
 	}
-	if err := gs.validateBetAmount(msg.BetAmount, balance); err != nil {
+	bonus, err := gs.repo.GetActiveBonus(msg.ClientID)
+	if err != nil {
+		return domain.PlayResponse{}, appErrors.NewInternalError(err.Error())
+	}
+	available := balance
+	if bonus != nil {
+		available += bonus.Amount
+	}
+	limits, err := gs.GetBetLimits(msg.ClientID)
+	if err != nil {
+		return domain.PlayResponse{}, err
+	}
+	if err := gs.validateBetAmount(msg.BetAmount, available, limits); err != nil {
+		return domain.PlayResponse{}, err
+	}
+	if err := gs.validateBetType(msg); err != nil {
 		return domain.PlayResponse{}, err
 	}
 
@@ -57,13 +113,32 @@ func (gs *GameService) ProcessPlay(msg domain.PlayRequest, dice DiceRoller) (dom
 	if err != nil {
 		return domain.PlayResponse{}, appErrors.NewDiceRollError(err.Error())
 	}
-	haveWon := gs.calculateOutcome(msg.BetType, diceResult)
+	haveWon := calculateOutcome(msg, diceResult)
+	payout, changeAmount, err := gs.settle(msg, haveWon)
+	if err != nil {
+		return domain.PlayResponse{}, appErrors.NewInternalError(err.Error())
+	}
+	jackpotHit, err := gs.rollJackpot(dice)
+	if err != nil {
+		return domain.PlayResponse{}, appErrors.NewDiceRollError(err.Error())
+	}
 
-	_, newBalance, err := gs.repo.ProcessPlay(domain.PlayTransaction{
-		Message:    msg,
-		DiceResult: diceResult,
-		Won:        haveWon,
-	})
+	transaction := domain.PlayTransaction{
+		Message:             msg,
+		DiceResult:          diceResult,
+		Won:                 haveWon,
+		ChangeAmount:        changeAmount,
+		Payout:              payout,
+		NetResult:           changeAmount,
+		JackpotContribution: gs.jackpotContribution(msg.BetAmount),
+		JackpotHit:          jackpotHit,
+		JackpotSeed:         gs.conf.Jackpot.SeedAmount,
+	}
+	if bonus != nil {
+		transaction.BonusID = bonus.BonusID
+		transaction.BonusStakeOrder = domain.BonusStakeOrder(gs.conf.Bonus.StakeOrder)
+	}
+	settlement, err := gs.repo.ProcessPlay(transaction)
 	gameRrr := &appErrors.GameError{}
 	if err != nil {
 		if errors.As(err, &gameRrr) {
@@ -72,10 +147,13 @@ func (gs *GameService) ProcessPlay(msg domain.PlayRequest, dice DiceRoller) (dom
 		return domain.PlayResponse{}, appErrors.NewInternalError(fmt.Sprintf("Error while executing play transaction: %s", err))
 	}
 
-	return domain.PlayResponse{DiceResult: diceResult, Won: haveWon, Balance: newBalance, BetAmount: msg.BetAmount}, nil
+	response := transaction.Response(settlement)
+	response.Achievements = gs.evaluateAchievements(msg.ClientID)
+	return response, nil
 }
 
-// EndPlay enforces game session closure rules and maintains data consistency
+// EndPlay closes the active game session and returns the summary of its rounds
+// A gamble still open on the last round is collected before the session closes
 func (gs *GameService) EndPlay(clientID int) (domain.EndPlayResponse, error) {
 	log.Printf("\nFinishing play session for client id -> %d", clientID)
 
@@ -84,68 +162,96 @@ func (gs *GameService) EndPlay(clientID int) (domain.EndPlayResponse, error) {
 		return domain.EndPlayResponse{}, appErrors.NewInternalError(err.Error())
 	}
 	if activeSession == nil {
-		return domain.EndPlayResponse{}, appErrors.NewActiveSessionError(fmt.Sprintf("Client ID %d does not have an active session.", clientID))
+		return domain.EndPlayResponse{}, appErrors.NewActiveSessionError(fmt.Sprintf("Client ID %d does not have an active session.", clientID)).WithDetail(appErrors.DetailNoActiveSession, clientID)
 	}
 
-	if err := gs.repo.CloseCurrentGameSession(clientID); err != nil {
+	var collected *domain.GambleResponse
+	if gs.GambleEnabled() {
+		gamble, err := gs.repo.GetOpenGamble(clientID)
+		if err != nil {
+			return domain.EndPlayResponse{}, wrapRepositoryError("Error while loading gamble", err)
+		}
+		if gamble != nil {
+			res, err := gs.collect(*gamble)
+			if err != nil {
+				return domain.EndPlayResponse{}, err
+			}
+			collected = &res
+		}
+	}
+
+	summary, err := gs.repo.CloseCurrentGameSession(clientID)
+	if err != nil {
 		return domain.EndPlayResponse{}, appErrors.NewInternalError(err.Error())
 	}
 
-	return domain.EndPlayResponse{ClientID: clientID}, nil
+	return domain.EndPlayResponse{ClientID: clientID, SessionSummary: summary, CollectedGamble: collected}, nil
+}
+
+// GetHistory returns the latest rounds of a player, limit is capped to maxHistoryRounds and defaults to it when not positive
+func (gs *GameService) GetHistory(playerID, limit int) (domain.HistoryResponse, error) {
+	log.Printf("\nGetting history for client id -> %d", playerID)
+	if limit <= 0 || limit > maxHistoryRounds {
+		limit = maxHistoryRounds
+	}
+	rounds, err := gs.repo.ListRounds(playerID, limit)
+	if err != nil {
+		return domain.HistoryResponse{}, wrapRepositoryError("Error while listing rounds", err)
+	}
+	return domain.HistoryResponse{ClientID: playerID, Rounds: rounds}, nil
 }
 
-// validateBetAmount enforces betting rules including minimum/maximum limits and available balance
-func (gs *GameService) validateBetAmount(betAmount, balance float64) error {
+// validateBetAmount enforces betting rules including the player minimum/maximum limits and available balance
+func (gs *GameService) validateBetAmount(betAmount, balance float64, limits domain.LimitsResponse) error {
 	var details string
 
+	minBet := fmt.Sprintf("%.2f", limits.MinBetAmount)
+
 	if betAmount > balance {
 		details = fmt.Sprintf("bet amount %.2f exceeds available balance %.2f", betAmount, balance)
-		return appErrors.NewInsufficientFundsError(details)
+		return appErrors.NewInsufficientFundsError(details).WithField("bet_amount", appErrors.ConstraintMax, fmt.Sprintf("%.2f", balance)).
+			WithDetail(appErrors.DetailInsufficientFunds, domain.Amount(betAmount), domain.Amount(balance))
 	}
 
 	if betAmount < 0 {
 		details = fmt.Sprintf("bet amount cannot be negative: %.2f", betAmount)
-		return appErrors.NewInvalidBetAmountError(details)
+		return appErrors.NewInvalidBetAmountError(details).WithField("bet_amount", appErrors.ConstraintMin, minBet).
+			WithDetail(appErrors.DetailBetNegative, domain.Amount(betAmount))
 	}
 	if betAmount == 0 {
 		details = "bet amount cannot be zero"
-		return appErrors.NewInvalidBetAmountError(details)
+		return appErrors.NewInvalidBetAmountError(details).WithField("bet_amount", appErrors.ConstraintMin, minBet).
+			WithDetail(appErrors.DetailBetZero)
 	}
 
-	if betAmount < config.New().Game.MinBetAmount {
-		details = fmt.Sprintf("minimum bet amount is %.2f", MinBetAmount)
-		return appErrors.NewInvalidBetAmountError(details)
+	if betAmount < limits.MinBetAmount {
+		details = fmt.Sprintf("minimum bet amount is %.2f", limits.MinBetAmount)
+		return appErrors.NewInvalidBetAmountError(details).WithField("bet_amount", appErrors.ConstraintMin, minBet).
+			WithDetail(appErrors.DetailBetBelowMin, domain.Amount(limits.MinBetAmount))
 	}
 
-	if betAmount > config.New().Game.MaxBetAmount {
-		details = fmt.Sprintf("maximum bet amount is %.2f", MaxBetAmount)
-		return appErrors.NewInvalidBetAmountError(details)
+	if betAmount > limits.MaxBetAmount {
+		details = fmt.Sprintf("maximum bet amount is %.2f", limits.MaxBetAmount)
+		return appErrors.NewInvalidBetAmountError(details).WithField("bet_amount", appErrors.ConstraintMax, fmt.Sprintf("%.2f", limits.MaxBetAmount)).
+			WithDetail(appErrors.DetailBetAboveMax, domain.Amount(limits.MaxBetAmount))
 	}
 
 	return nil
 }
 
-// DiceRoller defines the contract for dice rolling implementations
-type DiceRoller interface {
-	Roll() (int, error)
-}
-
-// Dice implements secure random number generation for fair game outcomes
-type Dice struct {
-	Sides int
-}
-
-// Roll uses crypto/rand for cryptographically secure random number generation
-func (d Dice) Roll() (int, error) {
-	bigI, err := rand.Int(rand.Reader, big.NewInt(int64(d.Sides)))
-	if err != nil {
-		return 0, err
+// validateBetType ensures the bet type is supported and exact bets target an existing dice face
+func (gs *GameService) validateBetType(msg domain.PlayRequest) error {
+	if !msg.BetType.IsValid() {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("unsupported bet type: %s", msg.BetType)).WithDetail(appErrors.DetailBetType, msg.BetType).
+			WithField("bet_type", appErrors.ConstraintOneOf, fmt.Sprintf("%s,%s,%s", domain.Even, domain.Odd, domain.Exact))
 	}
-	roll := int(bigI.Int64()) + 1
-	return roll, nil
-}
-
-func (gs *GameService) calculateOutcome(betType domain.BetType, diceResult int) bool {
-	isEven := diceResult%2 == 0
-	return (betType == domain.Even && isEven) || (betType == domain.Odd && !isEven)
+	if msg.BetType == domain.Exact && msg.BetNumber < 1 {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("bet number must be between 1 and %d", DiceSides)).WithDetail(appErrors.DetailBetNumber, DiceSides).
+			WithField("bet_number", appErrors.ConstraintMin, "1")
+	}
+	if msg.BetType == domain.Exact && msg.BetNumber > DiceSides {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("bet number must be between 1 and %d", DiceSides)).WithDetail(appErrors.DetailBetNumber, DiceSides).
+			WithField("bet_number", appErrors.ConstraintMax, fmt.Sprint(DiceSides))
+	}
+	return nil
 }
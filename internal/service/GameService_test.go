@@ -10,21 +10,26 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-type FakeDice struct {
-}
-
-func (fd FakeDice) Roll() (int, error) {
-	return 1, nil
-}
-
 const (
 	TestBalance             = 200.0
 	TestValidBet            = 100.0
 	TestInvalidBet          = 300.0
 	TestPostValidBetBalance = TestBalance + TestValidBet
 	TestBalanceWhenError    = 0.0
+	TestMinBet              = 10.0
+	TestMaxBet              = 100.0
 )
 
+var TestGameConfig = config.GameConfig{
+	MinBetAmount: TestMinBet,
+	MaxBetAmount: TestMaxBet,
+	MaxRTP:       0.99,
+	Variants: map[string]config.VariantConfig{
+		VariantParity: {Multiplier: 1.9, TargetRTP: 0.95},
+		VariantExact:  {Multiplier: 5.7, TargetRTP: 0.95},
+	},
+}
+
 func TestProcessPlay_BusinessLogic(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -44,6 +49,7 @@ func TestProcessPlay_BusinessLogic(t *testing.T) {
 			},
 			setupMock: func(mockRepo *repository.MockRepository) {
 				mockRepo.On("GetBalance", 1).Return(TestBalance, nil)
+				mockRepo.On("GetTierLimits", 1).Return(domain.TierLimits{Tier: domain.TierStandard}, nil)
 
 			},
 			expectedBalance:   TestBalance,
@@ -60,22 +66,26 @@ func TestProcessPlay_BusinessLogic(t *testing.T) {
 			},
 			setupMock: func(mockRepo *repository.MockRepository) {
 				mockRepo.On("GetBalance", 1).Return(TestBalance, nil)
+				mockRepo.On("GetTierLimits", 1).Return(domain.TierLimits{Tier: domain.TierStandard}, nil)
 				mockRepo.On("ProcessPlay", domain.PlayTransaction{
 					Message: domain.PlayRequest{
 						ClientID:  1,
 						BetAmount: 100,
 						BetType:   domain.Odd,
 					},
-					DiceResult: 1,
-					Won:        true,
-				}).Return(domain.GameSession{}, TestPostValidBetBalance, nil)
+					DiceResult:   1,
+					Won:          true,
+					ChangeAmount: 90,
+					Payout:       190,
+					NetResult:    90,
+				}).Return(domain.PlaySettlement{Balance: TestPostValidBetBalance}, nil)
 			},
 			expectedBalance: TestPostValidBetBalance,
 			expectedWin:     true,
 			expectError:     false,
 		},
 		{
-			name: "valid_bet_active_session_exists",
+			name: "valid_bet_appends_round_to_session",
 			payload: domain.PlayRequest{
 				ClientID:  1,
 				BetAmount: TestValidBet,
@@ -83,28 +93,38 @@ func TestProcessPlay_BusinessLogic(t *testing.T) {
 			},
 			setupMock: func(mockRepo *repository.MockRepository) {
 				mockRepo.On("GetBalance", 1).Return(TestBalance, nil)
+				mockRepo.On("GetTierLimits", 1).Return(domain.TierLimits{Tier: domain.TierStandard}, nil)
 				mockRepo.On("ProcessPlay", domain.PlayTransaction{
 					Message: domain.PlayRequest{
 						ClientID:  1,
 						BetAmount: TestValidBet,
 						BetType:   domain.Odd,
 					},
-					DiceResult: 1,
-					Won:        true,
-				}).Return(domain.GameSession{}, 0.0, appErrors.NewActiveSessionError(""))
+					DiceResult:   1,
+					Won:          true,
+					ChangeAmount: 90,
+					Payout:       190,
+					NetResult:    90,
+				}).Return(domain.PlaySettlement{
+					Session: domain.GameSession{SessionID: 3, PlayerID: 1, Active: true},
+					Round:   domain.Round{RoundID: 5, SessionID: 3},
+					Balance: 290,
+				}, nil)
 			},
-			expectedWin:       false,
-			expectError:       true,
-			expectedErrorCode: appErrors.ActiveSessionErrorCode,
+			expectedWin: true,
+			expectError: false,
 		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(repository.MockRepository)
-			service := NewGameService(mockRepo)
+			service := NewGameService(mockRepo, TestGameConfig)
 			tt.setupMock(mockRepo)
+			mockRepo.On("GetActiveBonus", 1).Return(nil, nil)
+			mockRepo.On("GetPlayerStats", 1).Return(domain.PlayerStats{}, nil).Maybe()
+			mockRepo.On("GetUnlockedAchievements", 1).Return(nil, nil).Maybe()
 
-			res, err := service.ProcessPlay(tt.payload, FakeDice{})
+			res, err := service.ProcessPlay(tt.payload, NewScriptedDice([]int{1}))
 			assert.Equal(t, res.Won, tt.expectedWin)
 			if tt.expectError {
 				assert.Equal(t, err.(*appErrors.GameError).Code, tt.expectedErrorCode)
@@ -117,6 +137,54 @@ func TestProcessPlay_BusinessLogic(t *testing.T) {
 	}
 }
 
+func TestEndPlay_ReturnsSessionSummary(t *testing.T) {
+	summary := domain.SessionSummary{
+		SessionID:       3,
+		RoundsPlayed:    4,
+		TotalStaked:     200,
+		TotalPayout:     190,
+		NetResult:       -10,
+		DurationSeconds: 60,
+	}
+	mockRepo := new(repository.MockRepository)
+	mockRepo.On("GetActiveSession", 1).Return(&domain.GameSession{SessionID: 3, PlayerID: 1, Active: true}, nil)
+	mockRepo.On("CloseCurrentGameSession", 1).Return(summary, nil)
+	service := NewGameService(mockRepo, TestGameConfig)
+
+	res, err := service.EndPlay(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EndPlayResponse{ClientID: 1, SessionSummary: summary}, res)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetHistory_CapsLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		expectedLimit int
+	}{
+		{name: "within_cap", limit: 20, expectedLimit: 20},
+		{name: "defaults_when_unset", limit: 0, expectedLimit: maxHistoryRounds},
+		{name: "capped", limit: 5000, expectedLimit: maxHistoryRounds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounds := []domain.Round{{RoundID: 2, SessionID: 1, PlayerID: 1}}
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("ListRounds", 1, tt.expectedLimit).Return(rounds, nil)
+			service := NewGameService(mockRepo, TestGameConfig)
+
+			res, err := service.GetHistory(1, tt.limit)
+
+			assert.NoError(t, err)
+			assert.Equal(t, domain.HistoryResponse{ClientID: 1, Rounds: rounds}, res)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestValidateBetAmount(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -154,14 +222,14 @@ func TestValidateBetAmount(t *testing.T) {
 		},
 		{
 			name:              "Bet Below Minimum",
-			betAmount:         config.New().Game.MinBetAmount - 1,
+			betAmount:         TestMinBet - 1,
 			balance:           500.0,
 			expectError:       true,
 			expectedErrorCode: appErrors.InvalidBetAmountErrorCode,
 		},
 		{
 			name:              "Bet Above Maximum",
-			betAmount:         config.New().Game.MaxBetAmount + 1,
+			betAmount:         TestMaxBet + 1,
 			balance:           2000.0,
 			expectError:       true,
 			expectedErrorCode: appErrors.InvalidBetAmountErrorCode,
@@ -170,12 +238,14 @@ func TestValidateBetAmount(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := &GameService{}
+			service := &GameService{conf: TestGameConfig}
+			limits := domain.LimitsResponse{MinBetAmount: TestMinBet, MaxBetAmount: TestMaxBet}
 
-			err := service.validateBetAmount(tt.betAmount, tt.balance)
+			err := service.validateBetAmount(tt.betAmount, tt.balance, limits)
 
 			if tt.expectError {
 				assert.Equal(t, err.(*appErrors.GameError).Code, tt.expectedErrorCode)
+				assert.Equal(t, "bet_amount", err.(*appErrors.GameError).Fields[0].Field)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -183,3 +253,262 @@ func TestValidateBetAmount(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateBetType checks the field and detail of each rejection, not only the shared invalid input code
+func TestValidateBetType(t *testing.T) {
+	tests := []struct {
+		name               string
+		bet                domain.PlayRequest
+		expectedField      string
+		expectedConstraint string
+		expectedDetail     string
+	}{
+		{name: "even", bet: domain.PlayRequest{BetType: domain.Even}},
+		{name: "exact", bet: domain.PlayRequest{BetType: domain.Exact, BetNumber: DiceSides}},
+		{
+			name:               "unknown_bet_type",
+			bet:                domain.PlayRequest{BetType: "high"},
+			expectedField:      "bet_type",
+			expectedConstraint: appErrors.ConstraintOneOf,
+			expectedDetail:     appErrors.DetailBetType,
+		},
+		{
+			name:               "exact_without_number",
+			bet:                domain.PlayRequest{BetType: domain.Exact},
+			expectedField:      "bet_number",
+			expectedConstraint: appErrors.ConstraintMin,
+			expectedDetail:     appErrors.DetailBetNumber,
+		},
+		{
+			name:               "exact_number_out_of_range",
+			bet:                domain.PlayRequest{BetType: domain.Exact, BetNumber: DiceSides + 1},
+			expectedField:      "bet_number",
+			expectedConstraint: appErrors.ConstraintMax,
+			expectedDetail:     appErrors.DetailBetNumber,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &GameService{conf: TestGameConfig}
+
+			err := service.validateBetType(tt.bet)
+
+			if tt.expectedField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			gameErr := err.(*appErrors.GameError)
+			assert.Equal(t, appErrors.InvalidInputErrorCode, gameErr.Code)
+			assert.Equal(t, tt.expectedDetail, gameErr.DetailKey)
+			if assert.Len(t, gameErr.Fields, 1) {
+				assert.Equal(t, tt.expectedField, gameErr.Fields[0].Field)
+				assert.Equal(t, tt.expectedConstraint, gameErr.Fields[0].Constraint)
+			}
+		})
+	}
+}
+
+func TestGetBetLimits(t *testing.T) {
+	vipMax := 5000.0
+	restrictedMin, restrictedMax := 20.0, 50.0
+
+	tests := []struct {
+		name           string
+		tierLimits     domain.TierLimits
+		expectedLimits domain.LimitsResponse
+	}{
+		{
+			name:       "standard_tier_uses_global_config",
+			tierLimits: domain.TierLimits{Tier: domain.TierStandard},
+			expectedLimits: domain.LimitsResponse{
+				ClientID:     1,
+				Tier:         domain.TierStandard,
+				MinBetAmount: TestMinBet,
+				MaxBetAmount: TestMaxBet,
+			},
+		},
+		{
+			name:       "vip_tier_overrides_maximum_only",
+			tierLimits: domain.TierLimits{Tier: domain.TierVIP, MaxBetAmount: &vipMax},
+			expectedLimits: domain.LimitsResponse{
+				ClientID:     1,
+				Tier:         domain.TierVIP,
+				MinBetAmount: TestMinBet,
+				MaxBetAmount: vipMax,
+			},
+		},
+		{
+			name:       "restricted_tier_overrides_both_limits",
+			tierLimits: domain.TierLimits{Tier: domain.TierRestricted, MinBetAmount: &restrictedMin, MaxBetAmount: &restrictedMax},
+			expectedLimits: domain.LimitsResponse{
+				ClientID:     1,
+				Tier:         domain.TierRestricted,
+				MinBetAmount: restrictedMin,
+				MaxBetAmount: restrictedMax,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("GetTierLimits", 1).Return(tt.tierLimits, nil)
+			service := NewGameService(mockRepo, TestGameConfig)
+
+			limits, err := service.GetBetLimits(1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedLimits, limits)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckRTP(t *testing.T) {
+	tests := []struct {
+		name        string
+		variants    map[string]config.VariantConfig
+		gamble      config.GambleConfig
+		expectError bool
+	}{
+		{
+			name: "house_edge_within_limit",
+			variants: map[string]config.VariantConfig{
+				VariantParity: {Multiplier: 1.96, TargetRTP: 0.98},
+				VariantExact:  {Multiplier: 5.7, TargetRTP: 0.95},
+			},
+			expectError: false,
+		},
+		{
+			name: "fair_payout_exceeds_limit",
+			variants: map[string]config.VariantConfig{
+				VariantParity: {Multiplier: 2.0, TargetRTP: 1.0},
+				VariantExact:  {Multiplier: 5.7, TargetRTP: 0.95},
+			},
+			expectError: true,
+		},
+		{
+			name: "target_does_not_match_multiplier",
+			variants: map[string]config.VariantConfig{
+				VariantParity: {Multiplier: 1.9, TargetRTP: 0.98},
+				VariantExact:  {Multiplier: 5.7, TargetRTP: 0.95},
+			},
+			expectError: true,
+		},
+		{
+			name: "gamble_with_house_edge",
+			variants: map[string]config.VariantConfig{
+				VariantParity: {Multiplier: 1.96, TargetRTP: 0.98},
+				VariantExact:  {Multiplier: 5.7, TargetRTP: 0.95},
+			},
+			gamble:      config.GambleConfig{MaxSteps: 5, Multiplier: 1.96},
+			expectError: false,
+		},
+		{
+			name: "fair_gamble_exceeds_limit",
+			variants: map[string]config.VariantConfig{
+				VariantParity: {Multiplier: 1.96, TargetRTP: 0.98},
+				VariantExact:  {Multiplier: 5.7, TargetRTP: 0.95},
+			},
+			gamble:      config.GambleConfig{MaxSteps: 5, Multiplier: 2},
+			expectError: true,
+		},
+		{
+			name: "missing_variant",
+			variants: map[string]config.VariantConfig{
+				VariantParity: {Multiplier: 1.96, TargetRTP: 0.98},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports, err := CheckRTP(config.GameConfig{MaxRTP: 0.99, Variants: tt.variants, Gamble: tt.gamble})
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				expectedReports := len(tt.variants)
+				if tt.gamble.MaxSteps > 0 {
+					expectedReports++
+				}
+				assert.Len(t, reports, expectedReports)
+			}
+		})
+	}
+}
+
+func TestProcessPlay_Jackpot(t *testing.T) {
+	jackpotConfig := TestGameConfig
+	jackpotConfig.Jackpot = config.JackpotConfig{
+		Enabled:          true,
+		ContributionRate: 0.01,
+		Dice:             3,
+		SeedAmount:       50,
+	}
+
+	testCases := []struct {
+		name               string
+		rolls              []int
+		expectedHit        bool
+		jackpotAward       float64
+		expectedNetResult  float64
+		expectedJackpotWon float64
+	}{
+		{
+			name:              "contribution_without_hit",
+			rolls:             []int{1, 6, 6, 5},
+			expectedHit:       false,
+			expectedNetResult: 90,
+		},
+		{
+			name:               "three_sixes_award_the_pool",
+			rolls:              []int{1, 6, 6, 6},
+			expectedHit:        true,
+			jackpotAward:       1000,
+			expectedNetResult:  1090,
+			expectedJackpotWon: 1000,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("GetBalance", 1).Return(TestBalance, nil)
+			mockRepo.On("GetActiveBonus", 1).Return(nil, nil)
+			mockRepo.On("GetPlayerStats", 1).Return(domain.PlayerStats{}, nil).Maybe()
+			mockRepo.On("GetUnlockedAchievements", 1).Return(nil, nil).Maybe()
+			mockRepo.On("GetTierLimits", 1).Return(domain.TierLimits{Tier: domain.TierStandard}, nil)
+			mockRepo.On("ProcessPlay", domain.PlayTransaction{
+				Message: domain.PlayRequest{
+					ClientID:  1,
+					BetAmount: TestValidBet,
+					BetType:   domain.Odd,
+				},
+				DiceResult:          1,
+				Won:                 true,
+				ChangeAmount:        90,
+				Payout:              190,
+				NetResult:           90,
+				JackpotContribution: 1,
+				JackpotHit:          tt.expectedHit,
+				JackpotSeed:         50,
+			}).Return(domain.PlaySettlement{Balance: TestBalance + tt.expectedNetResult, JackpotAward: tt.jackpotAward}, nil)
+			service := NewGameService(mockRepo, jackpotConfig)
+
+			res, err := service.ProcessPlay(domain.PlayRequest{
+				ClientID:  1,
+				BetAmount: TestValidBet,
+				BetType:   domain.Odd,
+			}, NewScriptedDice(tt.rolls))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedNetResult, res.NetResult)
+			assert.Equal(t, tt.expectedJackpotWon, res.JackpotWon)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// GetJackpot retrieves the current value of the progressive jackpot pool
+func (gs *GameService) GetJackpot() (domain.JackpotResponse, error) {
+	log.Printf("\nGetting jackpot pool")
+	amount, err := gs.repo.GetJackpotPool()
+	if err != nil {
+		gameErr := &appErrors.GameError{}
+		if errors.As(err, &gameErr) {
+			return domain.JackpotResponse{}, err
+		}
+		return domain.JackpotResponse{}, appErrors.NewInternalError(err.Error())
+	}
+	return domain.JackpotResponse{Amount: amount}, nil
+}
+
+// JackpotEnabled reports whether plays contribute to and can win the progressive jackpot
+func (gs *GameService) JackpotEnabled() bool {
+	return gs.conf.Jackpot.Enabled
+}
+
+// jackpotContribution computes the share of the stake added to the jackpot pool
+func (gs *GameService) jackpotContribution(betAmount float64) float64 {
+	if !gs.conf.Jackpot.Enabled {
+		return 0
+	}
+	return betAmount * gs.conf.Jackpot.ContributionRate
+}
+
+// rollJackpot performs the separate jackpot roll, won only when every jackpot dice lands on its highest face
+func (gs *GameService) rollJackpot(dice DiceRoller) (bool, error) {
+	if !gs.conf.Jackpot.Enabled {
+		return false, nil
+	}
+	hit := true
+	for i := 0; i < gs.conf.Jackpot.Dice; i++ {
+		roll, err := dice.Roll()
+		if err != nil {
+			return false, err
+		}
+		hit = hit && roll == DiceSides
+	}
+	return hit, nil
+}
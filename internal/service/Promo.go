@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// maxPromoCodeLength is the longest promo code the promo_code table can store
+const maxPromoCodeLength = 32
+
+// defaultRewardValidity is how long the rewards of a promo code stay valid when the code does not set it
+const defaultRewardValidity = 7 * 24 * time.Hour
+
+// CreatePromoCode validates and stores a promo code created by an operator, codes are stored in upper case
+// The free bet count and per player limit default to 1 and rewards stay valid for 7 days unless set
+func (gs *GameService) CreatePromoCode(promo domain.PromoCode) (domain.PromoCode, error) {
+	promo.Code = strings.ToUpper(strings.TrimSpace(promo.Code))
+	log.Printf("\nCreating %s promo code %s", promo.Kind, promo.Code)
+	if err := gs.validatePromoCode(&promo); err != nil {
+		return domain.PromoCode{}, err
+	}
+
+	created, err := gs.repo.CreatePromoCode(promo)
+	if err != nil {
+		return domain.PromoCode{}, wrapRepositoryError("Error while creating promo code", err)
+	}
+	return created, nil
+}
+
+// validatePromoCode checks the terms of a promo code, filling in the defaults and clearing the terms of the other kind
+func (gs *GameService) validatePromoCode(promo *domain.PromoCode) error {
+	if promo.Code == "" {
+		return appErrors.NewInvalidInputError("promo code cannot be empty").WithDetail(appErrors.DetailPromoCodeRequired).WithField("code", appErrors.ConstraintRequired, "")
+	}
+	if len(promo.Code) > maxPromoCodeLength {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("promo code cannot exceed %d characters", maxPromoCodeLength)).WithDetail(appErrors.DetailPromoCodeLength, maxPromoCodeLength).
+			WithField("code", appErrors.ConstraintMax, fmt.Sprint(maxPromoCodeLength))
+	}
+
+	switch promo.Kind {
+	case domain.PromoFreeBet:
+		if promo.BetAmount <= 0 {
+			return appErrors.NewInvalidInputError(fmt.Sprintf("free bet amount must be positive: %.2f", promo.BetAmount)).WithDetail(appErrors.DetailPromoBetAmount, domain.Amount(promo.BetAmount)).
+				WithField("bet_amount", appErrors.ConstraintMin, "0.01")
+		}
+		if err := gs.validateBetType(domain.PlayRequest{BetType: promo.BetType, BetNumber: promo.BetNumber}); err != nil {
+			return err
+		}
+		if promo.FreeBetCount == 0 {
+			promo.FreeBetCount = 1
+		}
+		if promo.FreeBetCount < 0 {
+			return appErrors.NewInvalidInputError(fmt.Sprintf("free bet count must be positive: %d", promo.FreeBetCount)).WithDetail(appErrors.DetailPromoFreeBetCount, promo.FreeBetCount).
+				WithField("free_bet_count", appErrors.ConstraintMin, "1")
+		}
+		promo.BonusAmount, promo.WageringMultiplier = 0, 0
+	case domain.PromoBonus:
+		if promo.BonusAmount <= 0 {
+			return appErrors.NewInvalidInputError(fmt.Sprintf("bonus amount must be positive: %.2f", promo.BonusAmount)).WithDetail(appErrors.DetailBonusAmount, domain.Amount(promo.BonusAmount)).
+				WithField("bonus_amount", appErrors.ConstraintMin, "0.01")
+		}
+		if promo.WageringMultiplier < 0 {
+			return appErrors.NewInvalidInputError(fmt.Sprintf("wagering multiplier cannot be negative: %g", promo.WageringMultiplier)).WithDetail(appErrors.DetailBonusWagering, promo.WageringMultiplier).
+				WithField("wagering_multiplier", appErrors.ConstraintMin, "0")
+		}
+		promo.BetAmount, promo.BetType, promo.BetNumber, promo.FreeBetCount = 0, "", 0, 0
+	default:
+		return appErrors.NewInvalidInputError(fmt.Sprintf("promo code kind must be free_bet or bonus: %s", promo.Kind)).WithDetail(appErrors.DetailPromoKind, promo.Kind).
+			WithField("kind", appErrors.ConstraintOneOf, fmt.Sprintf("%s,%s", domain.PromoFreeBet, domain.PromoBonus))
+	}
+
+	if promo.RewardValidForSeconds == 0 {
+		promo.RewardValidForSeconds = int64(defaultRewardValidity / time.Second)
+	}
+	if promo.RewardValidForSeconds < 0 {
+		return appErrors.NewInvalidInputError("promo rewards must be valid for a positive duration").WithDetail(appErrors.DetailPromoValidity).
+			WithField("reward_valid_for_seconds", appErrors.ConstraintMin, "1")
+	}
+	if promo.MaxPerPlayer == 0 {
+		promo.MaxPerPlayer = 1
+	}
+	if promo.MaxRedemptions < 0 || promo.MaxPerPlayer < 0 {
+		return appErrors.NewInvalidInputError("redemption limits cannot be negative").WithDetail(appErrors.DetailPromoLimits)
+	}
+	if !promo.ExpiresAt.After(time.Now()) {
+		return appErrors.NewInvalidInputError("promo code must expire in the future").WithDetail(appErrors.DetailPromoExpiry).
+			WithField("expires_at", appErrors.ConstraintMin, time.Now().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// RedeemPromoCode grants the free bets or the bonus of a promo code, codes are case insensitive
+func (gs *GameService) RedeemPromoCode(req domain.RedeemPromoRequest) (domain.RedeemPromoResponse, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	log.Printf("\nRedeeming promo code %s for client id -> %d", code, req.ClientID)
+	if code == "" {
+		return domain.RedeemPromoResponse{}, appErrors.NewInvalidInputError("promo code cannot be empty").WithDetail(appErrors.DetailPromoCodeRequired).WithField("code", appErrors.ConstraintRequired, "")
+	}
+
+	redemption, err := gs.repo.RedeemPromoCode(code, req.ClientID)
+	if err != nil {
+		return domain.RedeemPromoResponse{}, wrapRepositoryError("Error while redeeming promo code", err)
+	}
+	return redemption, nil
+}
+
+// ListFreeBets returns the free bets a player can still play
+func (gs *GameService) ListFreeBets(playerID int) (domain.FreeBetsResponse, error) {
+	freeBets, err := gs.repo.ListFreeBets(playerID)
+	if err != nil {
+		return domain.FreeBetsResponse{}, wrapRepositoryError("Error while listing free bets", err)
+	}
+	return domain.FreeBetsResponse{ClientID: playerID, FreeBets: freeBets}, nil
+}
+
+// processFreeBet plays a free bet with the stake and bet fixed by its promotion
+// The stake is funded by the promotion, so only the winnings are credited and the balance is never debited,
+// while the payout reports the stake and winnings like any other play.
+// Free bets neither count towards bonus wagering nor take part in the jackpot
+func (gs *GameService) processFreeBet(msg domain.PlayRequest, dice DiceRoller) (domain.PlayResponse, error) {
+	log.Printf("\nProcessing free bet %d for user id -> %d", msg.FreeBetID, msg.ClientID)
+
+	freeBet, err := gs.repo.GetFreeBet(msg.FreeBetID, msg.ClientID)
+	if err != nil {
+		return domain.PlayResponse{}, wrapRepositoryError("Error while loading free bet", err)
+	}
+	if freeBet.Status != domain.FreeBetAvailable || !time.Now().Before(freeBet.ExpiresAt) {
+		return domain.PlayResponse{}, appErrors.NewPromoError(fmt.Sprintf("free bet %d is no longer available", freeBet.FreeBetID)).WithDetail(appErrors.DetailFreeBetUnavailable, freeBet.FreeBetID)
+	}
+	msg.BetAmount = freeBet.BetAmount
+	msg.BetType = freeBet.BetType
+	msg.BetNumber = freeBet.BetNumber
+	if err := gs.validateBetType(msg); err != nil {
+		return domain.PlayResponse{}, err
+	}
+
+	diceResult, err := dice.Roll()
+	if err != nil {
+		return domain.PlayResponse{}, appErrors.NewDiceRollError(err.Error())
+	}
+	haveWon := calculateOutcome(msg, diceResult)
+	payout, _, err := gs.settle(msg, haveWon)
+	if err != nil {
+		return domain.PlayResponse{}, appErrors.NewInternalError(err.Error())
+	}
+	winnings := math.Max(payout-msg.BetAmount, 0)
+
+	transaction := domain.PlayTransaction{
+		Message:      msg,
+		DiceResult:   diceResult,
+		Won:          haveWon,
+		ChangeAmount: winnings,
+		Payout:       payout,
+		NetResult:    winnings,
+		FreeBetID:    freeBet.FreeBetID,
+	}
+	settlement, err := gs.repo.ProcessPlay(transaction)
+	if err != nil {
+		return domain.PlayResponse{}, wrapRepositoryError("Error while executing free bet transaction", err)
+	}
+
+	response := transaction.Response(settlement)
+	response.Achievements = gs.evaluateAchievements(msg.ClientID)
+	return response, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessPlay_FreeBet(t *testing.T) {
+	available := domain.FreeBet{
+		FreeBetID: 5,
+		PlayerID:  1,
+		Code:      "WELCOME",
+		BetAmount: 20,
+		BetType:   domain.Even,
+		Status:    domain.FreeBetAvailable,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		name             string
+		freeBet          domain.FreeBet
+		diceResult       int
+		expectedPayout   float64
+		expectedWinnings float64
+		expectedErr      int
+	}{
+		{
+			name:             "win_credits_winnings_only",
+			freeBet:          available,
+			diceResult:       2,
+			expectedPayout:   38,
+			expectedWinnings: 18,
+		},
+		{
+			name:             "loss_debits_nothing",
+			freeBet:          available,
+			diceResult:       3,
+			expectedWinnings: 0,
+		},
+		{
+			name: "already_used",
+			freeBet: func() domain.FreeBet {
+				used := available
+				used.Status = domain.FreeBetUsed
+				return used
+			}(),
+			expectedErr: appErrors.PromoErrorCode,
+		},
+		{
+			name: "expired",
+			freeBet: func() domain.FreeBet {
+				expired := available
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				return expired
+			}(),
+			expectedErr: appErrors.PromoErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("GetFreeBet", 5, 1).Return(tt.freeBet, nil)
+			mockRepo.On("ProcessPlay", mock.Anything).Return(domain.PlaySettlement{Balance: TestBalance + tt.expectedWinnings}, nil)
+			mockRepo.On("GetPlayerStats", 1).Return(domain.PlayerStats{}, nil).Maybe()
+			mockRepo.On("GetUnlockedAchievements", 1).Return(nil, nil).Maybe()
+
+			// The stake and bet of the free bet replace the ones sent by the client
+			service := NewGameService(mockRepo, TestGameConfig)
+			result, err := service.ProcessPlay(domain.PlayRequest{ClientID: 1, BetAmount: 1000, BetType: domain.Odd, FreeBetID: 5}, NewScriptedDice([]int{tt.diceResult}))
+
+			if tt.expectedErr != 0 {
+				assert.Equal(t, tt.expectedErr, err.(*appErrors.GameError).Code)
+				mockRepo.AssertNotCalled(t, "ProcessPlay", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPayout, result.Payout)
+			assert.Equal(t, tt.expectedWinnings, result.NetResult)
+			mockRepo.AssertNotCalled(t, "GetBalance", mock.Anything)
+			mockRepo.AssertCalled(t, "ProcessPlay", domain.PlayTransaction{
+				Message:      domain.PlayRequest{ClientID: 1, BetAmount: 20, BetType: domain.Even, FreeBetID: 5},
+				DiceResult:   tt.diceResult,
+				Won:          tt.expectedWinnings > 0,
+				ChangeAmount: tt.expectedWinnings,
+				Payout:       tt.expectedPayout,
+				NetResult:    tt.expectedWinnings,
+				FreeBetID:    5,
+			})
+		})
+	}
+}
+
+func TestRedeemPromoCode_NormalizesCode(t *testing.T) {
+	mockRepo := new(repository.MockRepository)
+	mockRepo.On("RedeemPromoCode", "WELCOME", 1).Return(domain.RedeemPromoResponse{ClientID: 1, Code: "WELCOME", Kind: domain.PromoFreeBet}, nil)
+
+	service := NewGameService(mockRepo, TestGameConfig)
+	result, err := service.RedeemPromoCode(domain.RedeemPromoRequest{ClientID: 1, Code: " welcome "})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PromoFreeBet, result.Kind)
+
+	_, err = service.RedeemPromoCode(domain.RedeemPromoRequest{ClientID: 1, Code: "  "})
+	assert.Equal(t, appErrors.InvalidInputErrorCode, err.(*appErrors.GameError).Code)
+	mockRepo.AssertNumberOfCalls(t, "RedeemPromoCode", 1)
+}
+
+func TestCreatePromoCode(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		promo       domain.PromoCode
+		expected    domain.PromoCode
+		expectedErr int
+	}{
+		{
+			name:  "free_bet_defaults",
+			promo: domain.PromoCode{Code: " welcome ", Kind: domain.PromoFreeBet, BetAmount: 5, BetType: domain.Even, BonusAmount: 10, ExpiresAt: expiresAt},
+			expected: domain.PromoCode{
+				Code: "WELCOME", Kind: domain.PromoFreeBet, BetAmount: 5, BetType: domain.Even, FreeBetCount: 1,
+				RewardValidForSeconds: 604800, MaxPerPlayer: 1, ExpiresAt: expiresAt,
+			},
+		},
+		{
+			name:  "bonus_clears_bet",
+			promo: domain.PromoCode{Code: "RELOAD", Kind: domain.PromoBonus, BetAmount: 5, BonusAmount: 10, WageringMultiplier: 20, MaxRedemptions: 100, MaxPerPlayer: 2, ExpiresAt: expiresAt},
+			expected: domain.PromoCode{
+				Code: "RELOAD", Kind: domain.PromoBonus, BonusAmount: 10, WageringMultiplier: 20,
+				RewardValidForSeconds: 604800, MaxRedemptions: 100, MaxPerPlayer: 2, ExpiresAt: expiresAt,
+			},
+		},
+		{
+			name:        "code_too_long",
+			promo:       domain.PromoCode{Code: "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456", Kind: domain.PromoBonus, BonusAmount: 10, ExpiresAt: expiresAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+		{
+			name:        "exact_without_number",
+			promo:       domain.PromoCode{Code: "LUCKY", Kind: domain.PromoFreeBet, BetAmount: 5, BetType: domain.Exact, ExpiresAt: expiresAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+		{
+			name:        "bonus_without_amount",
+			promo:       domain.PromoCode{Code: "RELOAD", Kind: domain.PromoBonus, ExpiresAt: expiresAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+		{
+			name:        "negative_limit",
+			promo:       domain.PromoCode{Code: "RELOAD", Kind: domain.PromoBonus, BonusAmount: 10, MaxRedemptions: -1, ExpiresAt: expiresAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("CreatePromoCode", mock.Anything).Return(tt.expected, nil)
+
+			service := NewGameService(mockRepo, TestGameConfig)
+			result, err := service.CreatePromoCode(tt.promo)
+
+			if tt.expectedErr != 0 {
+				assert.Equal(t, tt.expectedErr, err.(*appErrors.GameError).Code)
+				mockRepo.AssertNotCalled(t, "CreatePromoCode", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			mockRepo.AssertCalled(t, "CreatePromoCode", tt.expected)
+		})
+	}
+}
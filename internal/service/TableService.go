@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+)
+
+// table guards the current round of a single table
+type table struct {
+	mu    sync.Mutex
+	round *domain.TableRound
+}
+
+// TableService runs the round state machine of the shared multiplayer tables
+// Rounds move from betting open to closed, rolled and settled, every bet being settled against one roll
+type TableService struct {
+	repo   repository.TableRepository
+	game   *GameService
+	tables map[int]*table
+}
+
+// NewTableService creates the tables numbered from 1 to count
+// Bets are validated with the same rules and payouts as single player plays
+func NewTableService(repo repository.TableRepository, game *GameService, count int) *TableService {
+	tables := make(map[int]*table, count)
+	for tableID := 1; tableID <= count; tableID++ {
+		tables[tableID] = &table{}
+	}
+	return &TableService{
+		repo:   repo,
+		game:   game,
+		tables: tables,
+	}
+}
+
+// TableIDs lists every table run by the service
+func (ts *TableService) TableIDs() []int {
+	ids := make([]int, 0, len(ts.tables))
+	for tableID := 1; tableID <= len(ts.tables); tableID++ {
+		ids = append(ids, tableID)
+	}
+	return ids
+}
+
+// CurrentRound returns the state of the round being played on a table
+func (ts *TableService) CurrentRound(tableID int) (domain.TableRoundResponse, error) {
+	t, err := ts.table(tableID)
+	if err != nil {
+		return domain.TableRoundResponse{}, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.round == nil {
+		return domain.TableRoundResponse{TableID: tableID}, nil
+	}
+	return roundResponse(t.round), nil
+}
+
+// OpenRound starts a new round accepting bets until closesAt
+func (ts *TableService) OpenRound(tableID int, closesAt time.Time) (domain.TableRoundResponse, error) {
+	t, err := ts.table(tableID)
+	if err != nil {
+		return domain.TableRoundResponse{}, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.round != nil && t.round.State != domain.RoundSettled && t.round.State != domain.RoundVoided {
+		return domain.TableRoundResponse{}, appErrors.NewTableRoundError(fmt.Sprintf("round %d of table %d is still %s", t.round.RoundID, tableID, t.round.State)).WithDetail(appErrors.DetailTableRoundRunning, t.round.RoundID, tableID, t.round.State)
+	}
+
+	round, err := ts.repo.CreateTableRound(tableID, closesAt)
+	if err != nil {
+		return domain.TableRoundResponse{}, err
+	}
+	t.round = &round
+
+	log.Printf("\nOpened round %d on table %d", round.RoundID, tableID)
+	return roundResponse(t.round), nil
+}
+
+// PlaceBet validates a bet against the player limits and balance, then debits its stake
+func (ts *TableService) PlaceBet(req domain.TableBetRequest) (domain.TableBetResponse, error) {
+	log.Printf("\nPlacing table bet for user id -> %d\nTable -> %d\nBet Amount -> %g\nBet Type -> %s", req.ClientID, req.TableID, req.BetAmount, req.BetType)
+
+	t, err := ts.table(req.TableID)
+	if err != nil {
+		return domain.TableBetResponse{}, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.round == nil || t.round.State != domain.RoundBettingOpen || time.Now().After(t.round.ClosesAt) {
+		return domain.TableBetResponse{}, appErrors.NewTableRoundError(fmt.Sprintf("betting is closed on table %d", req.TableID)).WithDetail(appErrors.DetailTableBettingClosed, req.TableID)
+	}
+
+	bet := req.PlayRequest()
+	balance, err := ts.game.repo.GetBalance(req.ClientID)
+	if err != nil {
+		return domain.TableBetResponse{}, appErrors.NewInternalError(err.Error())
+	}
+	limits, err := ts.game.GetBetLimits(req.ClientID)
+	if err != nil {
+		return domain.TableBetResponse{}, err
+	}
+	if err := ts.game.validateBetAmount(bet.BetAmount, balance, limits); err != nil {
+		return domain.TableBetResponse{}, err
+	}
+	if err := ts.game.validateBetType(bet); err != nil {
+		return domain.TableBetResponse{}, err
+	}
+
+	placed, newBalance, err := ts.repo.PlaceTableBet(domain.TableBet{
+		RoundID:   t.round.RoundID,
+		PlayerID:  req.ClientID,
+		BetAmount: req.BetAmount,
+		BetType:   req.BetType,
+		BetNumber: req.BetNumber,
+	})
+	if err != nil {
+		gameErr := &appErrors.GameError{}
+		if errors.As(err, &gameErr) {
+			return domain.TableBetResponse{}, err
+		}
+		return domain.TableBetResponse{}, appErrors.NewInternalError(fmt.Sprintf("Error while placing table bet: %s", err))
+	}
+	t.round.Bets = append(t.round.Bets, placed)
+
+	return domain.TableBetResponse{
+		TableID:   req.TableID,
+		RoundID:   t.round.RoundID,
+		BetAmount: req.BetAmount,
+		Balance:   newBalance,
+	}, nil
+}
+
+// CloseBetting stops accepting bets on the current round
+func (ts *TableService) CloseBetting(tableID int) (domain.TableRoundResponse, error) {
+	return ts.transition(tableID, domain.RoundBettingOpen, domain.RoundBettingClosed, func(*domain.TableRound) error {
+		return nil
+	})
+}
+
+// Roll performs the single dice roll shared by every bet of the round
+func (ts *TableService) Roll(tableID int, dice DiceRoller) (domain.TableRoundResponse, error) {
+	return ts.transition(tableID, domain.RoundBettingClosed, domain.RoundRolled, func(round *domain.TableRound) error {
+		diceResult, err := dice.Roll()
+		if err != nil {
+			return appErrors.NewDiceRollError(err.Error())
+		}
+		round.DiceResult = diceResult
+		return nil
+	})
+}
+
+// Settle pays every winning bet of a rolled round in a single transaction
+// When settlement fails the round is voided and every stake refunded
+func (ts *TableService) Settle(tableID int) (domain.TableSettlementResponse, error) {
+	t, err := ts.table(tableID)
+	if err != nil {
+		return domain.TableSettlementResponse{}, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	round := t.round
+	if round == nil || round.State != domain.RoundRolled {
+		return domain.TableSettlementResponse{}, appErrors.NewTableRoundError(fmt.Sprintf("table %d has no rolled round to settle", tableID)).WithDetail(appErrors.DetailTableNotRolled, tableID)
+	}
+
+	results := make([]domain.TableBetResult, 0, len(round.Bets))
+	for _, bet := range round.Bets {
+		playRequest := domain.PlayRequest{ClientID: bet.PlayerID, BetAmount: bet.BetAmount, BetType: bet.BetType, BetNumber: bet.BetNumber}
+		won := calculateOutcome(playRequest, round.DiceResult)
+		payout, _, err := ts.game.settle(playRequest, won)
+		if err != nil {
+			return domain.TableSettlementResponse{}, ts.void(round, err)
+		}
+		results = append(results, domain.TableBetResult{TableBet: bet, Won: won, Payout: payout})
+	}
+
+	settlement, err := ts.repo.SettleTableRound(*round, results)
+	if err != nil {
+		return domain.TableSettlementResponse{}, ts.void(round, err)
+	}
+	round.State = domain.RoundSettled
+
+	log.Printf("\nSettled round %d on table %d with dice result %d and %d bet(s)", round.RoundID, tableID, round.DiceResult, len(settlement.Results))
+	return settlement, nil
+}
+
+// VoidRound refunds the stakes of the current round of a table unless it was already settled or voided
+// Callers retry it after any failed step so the table is never left on an unfinished round
+func (ts *TableService) VoidRound(tableID int) (domain.TableRoundResponse, error) {
+	t, err := ts.table(tableID)
+	if err != nil {
+		return domain.TableRoundResponse{}, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.round == nil {
+		return domain.TableRoundResponse{TableID: tableID}, nil
+	}
+	if t.round.State != domain.RoundSettled && t.round.State != domain.RoundVoided {
+		log.Printf("\nVoiding round %d on table %d", t.round.RoundID, tableID)
+		if err := ts.repo.VoidTableRound(t.round.RoundID); err != nil {
+			return domain.TableRoundResponse{}, appErrors.NewInternalError(fmt.Sprintf("error voiding round %d: %v", t.round.RoundID, err))
+		}
+		t.round.State = domain.RoundVoided
+	}
+	return roundResponse(t.round), nil
+}
+
+// VoidUnsettledRounds refunds the stakes of every round left unfinished by a previous run of the server
+// and returns how many rounds were voided
+func (ts *TableService) VoidUnsettledRounds() (int, error) {
+	rounds, err := ts.repo.ListUnsettledTableRounds()
+	if err != nil {
+		return 0, appErrors.NewInternalError(fmt.Sprintf("error listing unsettled table rounds: %v", err))
+	}
+	for i, round := range rounds {
+		log.Printf("\nVoiding round %d left %s on table %d", round.RoundID, round.State, round.TableID)
+		if err := ts.repo.VoidTableRound(round.RoundID); err != nil {
+			return i, appErrors.NewInternalError(fmt.Sprintf("error voiding round %d: %v", round.RoundID, err))
+		}
+	}
+	return len(rounds), nil
+}
+
+// void refunds the stakes of a round that failed to settle, callers must hold the table lock
+func (ts *TableService) void(round *domain.TableRound, cause error) error {
+	log.Printf("\nVoiding round %d on table %d: %v", round.RoundID, round.TableID, cause)
+	if err := ts.repo.VoidTableRound(round.RoundID); err != nil {
+		return appErrors.NewInternalError(fmt.Sprintf("error voiding round %d after settlement failure (%v): %v", round.RoundID, cause, err))
+	}
+	round.State = domain.RoundVoided
+	return appErrors.NewInternalError(fmt.Sprintf("round %d was voided and stakes refunded: %v", round.RoundID, cause))
+}
+
+// transition moves the current round between two states, applying the given change under the table lock
+// The new state is stored before it is applied in memory, so the table never runs ahead of the database
+func (ts *TableService) transition(tableID int, from, to domain.TableRoundState, change func(*domain.TableRound) error) (domain.TableRoundResponse, error) {
+	t, err := ts.table(tableID)
+	if err != nil {
+		return domain.TableRoundResponse{}, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.round == nil || t.round.State != from {
+		return domain.TableRoundResponse{}, appErrors.NewTableRoundError(fmt.Sprintf("table %d has no round in state %s", tableID, from)).WithDetail(appErrors.DetailTableNoRound, tableID, from)
+	}
+	next := *t.round
+	if err := change(&next); err != nil {
+		return domain.TableRoundResponse{}, err
+	}
+	next.State = to
+	if err := ts.repo.UpdateTableRound(next); err != nil {
+		return domain.TableRoundResponse{}, wrapRepositoryError(fmt.Sprintf("Error while moving round %d to %s", next.RoundID, to), err)
+	}
+	*t.round = next
+	return roundResponse(t.round), nil
+}
+
+// table looks up a table by its ID
+func (ts *TableService) table(tableID int) (*table, error) {
+	t, ok := ts.tables[tableID]
+	if !ok {
+		return nil, appErrors.NewInvalidInputError(fmt.Sprintf("unknown table: %d", tableID)).WithDetail(appErrors.DetailTableUnknown, tableID)
+	}
+	return t, nil
+}
+
+// roundResponse builds the public view of a round
+func roundResponse(round *domain.TableRound) domain.TableRoundResponse {
+	return domain.TableRoundResponse{
+		TableID:    round.TableID,
+		RoundID:    round.RoundID,
+		State:      round.State,
+		DiceResult: round.DiceResult,
+		ClosesAt:   round.ClosesAt,
+		BetsCount:  len(round.Bets),
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupTableRound opens a round on table 1 with an even bet from player 1 and an odd bet from player 2
+func setupTableRound(t *testing.T, mockRepo *repository.MockRepository) *TableService {
+	mockRepo.On("CreateTableRound", 1, mock.Anything).Return(domain.TableRound{RoundID: 7, TableID: 1, State: domain.RoundBettingOpen, ClosesAt: time.Now().Add(time.Minute)}, nil)
+	for i, betType := range []domain.BetType{domain.Even, domain.Odd} {
+		playerID := i + 1
+		mockRepo.On("GetBalance", playerID).Return(TestBalance, nil)
+		mockRepo.On("GetTierLimits", playerID).Return(domain.TierLimits{Tier: domain.TierStandard}, nil)
+		mockRepo.On("PlaceTableBet", domain.TableBet{RoundID: 7, PlayerID: playerID, BetAmount: TestValidBet, BetType: betType}).
+			Return(domain.TableBet{BetID: playerID, RoundID: 7, PlayerID: playerID, BetAmount: TestValidBet, BetType: betType}, TestBalance-TestValidBet, nil)
+	}
+
+	tables := NewTableService(mockRepo, NewGameService(mockRepo, TestGameConfig), 1)
+	_, err := tables.OpenRound(1, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	for _, bet := range []domain.TableBetRequest{
+		{ClientID: 1, TableID: 1, BetAmount: TestValidBet, BetType: domain.Even},
+		{ClientID: 2, TableID: 1, BetAmount: TestValidBet, BetType: domain.Odd},
+	} {
+		_, err := tables.PlaceBet(bet)
+		assert.NoError(t, err)
+	}
+	return tables
+}
+
+func TestTableService_RoundLifecycle(t *testing.T) {
+	mockRepo := new(repository.MockRepository)
+	mockRepo.On("UpdateTableRound", mock.Anything).Return(nil)
+	tables := setupTableRound(t, mockRepo)
+
+	round, err := tables.CloseBetting(1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoundBettingClosed, round.State)
+	assert.Equal(t, 2, round.BetsCount)
+
+	_, err = tables.PlaceBet(domain.TableBetRequest{ClientID: 1, TableID: 1, BetAmount: TestValidBet, BetType: domain.Even})
+	assert.Equal(t, appErrors.TableRoundErrorCode, err.(*appErrors.GameError).Code)
+
+	round, err = tables.Roll(1, NewScriptedDice([]int{4}))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoundRolled, round.State)
+	assert.Equal(t, 4, round.DiceResult)
+
+	// Both bets are settled against the same roll, only the even bet wins
+	expectedResults := []domain.TableBetResult{
+		{TableBet: domain.TableBet{BetID: 1, RoundID: 7, PlayerID: 1, BetAmount: TestValidBet, BetType: domain.Even}, Won: true, Payout: 190},
+		{TableBet: domain.TableBet{BetID: 2, RoundID: 7, PlayerID: 2, BetAmount: TestValidBet, BetType: domain.Odd}, Won: false, Payout: 0},
+	}
+	mockRepo.On("SettleTableRound", mock.Anything, expectedResults).Return(domain.TableSettlementResponse{TableID: 1, RoundID: 7, DiceResult: 4, Results: expectedResults}, nil)
+
+	settlement, err := tables.Settle(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, settlement.DiceResult)
+	assert.Equal(t, expectedResults, settlement.Results)
+
+	current, err := tables.CurrentRound(1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoundSettled, current.State)
+}
+
+func TestTableService_SettlementFailureVoidsRound(t *testing.T) {
+	mockRepo := new(repository.MockRepository)
+	mockRepo.On("UpdateTableRound", mock.Anything).Return(nil)
+	tables := setupTableRound(t, mockRepo)
+	mockRepo.On("SettleTableRound", mock.Anything, mock.Anything).Return(domain.TableSettlementResponse{}, errors.New("connection reset"))
+	mockRepo.On("VoidTableRound", 7).Return(nil)
+
+	_, err := tables.CloseBetting(1)
+	assert.NoError(t, err)
+	_, err = tables.Roll(1, NewScriptedDice([]int{3}))
+	assert.NoError(t, err)
+
+	_, err = tables.Settle(1)
+	assert.Equal(t, appErrors.InternalErrorCode, err.(*appErrors.GameError).Code)
+
+	current, err := tables.CurrentRound(1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoundVoided, current.State)
+	mockRepo.AssertCalled(t, "VoidTableRound", 7)
+}
+
+func TestTableService_FailedTransitionIsVoided(t *testing.T) {
+	mockRepo := new(repository.MockRepository)
+	tables := setupTableRound(t, mockRepo)
+	mockRepo.On("UpdateTableRound", mock.Anything).Return(errors.New("connection reset"))
+	mockRepo.On("VoidTableRound", 7).Return(errors.New("connection reset")).Once()
+	mockRepo.On("VoidTableRound", 7).Return(nil).Once()
+
+	_, err := tables.CloseBetting(1)
+	assert.Equal(t, appErrors.InternalErrorCode, err.(*appErrors.GameError).Code)
+	current, err := tables.CurrentRound(1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoundBettingOpen, current.State)
+
+	// A failed void leaves the round as it was so it can be retried
+	_, err = tables.VoidRound(1)
+	assert.Equal(t, appErrors.InternalErrorCode, err.(*appErrors.GameError).Code)
+	round, err := tables.VoidRound(1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoundVoided, round.State)
+
+	// Voiding a finished round does nothing
+	round, err = tables.VoidRound(1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoundVoided, round.State)
+	mockRepo.AssertNumberOfCalls(t, "VoidTableRound", 2)
+}
+
+func TestTableService_VoidUnsettledRounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		voidErr       error
+		expectedCount int
+		expectedErr   int
+	}{
+		{name: "voids_every_unsettled_round", expectedCount: 2},
+		{name: "void_failure", voidErr: errors.New("connection reset"), expectedErr: appErrors.InternalErrorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("ListUnsettledTableRounds").Return([]domain.TableRound{
+				{RoundID: 3, TableID: 1, State: domain.RoundBettingOpen},
+				{RoundID: 4, TableID: 2, State: domain.RoundRolled, DiceResult: 5},
+			}, nil)
+			mockRepo.On("VoidTableRound", mock.Anything).Return(tt.voidErr)
+			tables := NewTableService(mockRepo, NewGameService(mockRepo, TestGameConfig), 2)
+
+			voided, err := tables.VoidUnsettledRounds()
+
+			if tt.expectedErr != 0 {
+				assert.Equal(t, tt.expectedErr, err.(*appErrors.GameError).Code)
+				assert.Zero(t, voided)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, voided)
+			mockRepo.AssertCalled(t, "VoidTableRound", 3)
+			mockRepo.AssertCalled(t, "VoidTableRound", 4)
+		})
+	}
+}
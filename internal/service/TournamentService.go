@@ -0,0 +1,309 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+)
+
+// TournamentService runs time-boxed tournaments played with a tournament-only chip stack
+// Bets use the same rules and payouts as single player plays, but never touch the wallet
+type TournamentService struct {
+	repo repository.TournamentRepository
+	game *GameService
+	now  func() time.Time
+}
+
+// NewTournamentService shares the bet validation and payout rules of the game service
+func NewTournamentService(repo repository.TournamentRepository, game *GameService) *TournamentService {
+	return &TournamentService{
+		repo: repo,
+		game: game,
+		now:  time.Now,
+	}
+}
+
+// maxTournamentNameLength is the longest name the tournament table can store
+const maxTournamentNameLength = 64
+
+// CreateTournament validates and stores a tournament created by an operator, its prize pool starts empty
+// Tournaments start right away, rank by final stack and pay the whole pool to the winner unless set otherwise
+func (ts *TournamentService) CreateTournament(tournament domain.Tournament) (domain.Tournament, error) {
+	tournament.Name = strings.TrimSpace(tournament.Name)
+	log.Printf("\nCreating tournament %s", tournament.Name)
+	if err := ts.validateTournament(&tournament); err != nil {
+		return domain.Tournament{}, err
+	}
+
+	created, err := ts.repo.CreateTournament(tournament)
+	if err != nil {
+		return domain.Tournament{}, wrapRepositoryError("Error while creating tournament", err)
+	}
+	return created, nil
+}
+
+// validateTournament checks the terms of a new tournament, filling in the default start, ranking and prize shares
+func (ts *TournamentService) validateTournament(tournament *domain.Tournament) error {
+	if tournament.Name == "" || len(tournament.Name) > maxTournamentNameLength {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("tournament name must be between 1 and %d characters", maxTournamentNameLength)).WithDetail(appErrors.DetailTournamentName, maxTournamentNameLength).
+			WithField("name", appErrors.ConstraintMax, fmt.Sprint(maxTournamentNameLength))
+	}
+	if tournament.BuyIn < 0 {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("tournament buy-in cannot be negative: %.2f", tournament.BuyIn)).WithDetail(appErrors.DetailTournamentBuyInAmount, domain.Amount(tournament.BuyIn)).
+			WithField("buy_in", appErrors.ConstraintMin, "0")
+	}
+	if tournament.StartingStack <= 0 {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("starting stack must be positive: %.2f", tournament.StartingStack)).WithDetail(appErrors.DetailTournamentStack, domain.Amount(tournament.StartingStack)).
+			WithField("starting_stack", appErrors.ConstraintMin, "0.01")
+	}
+
+	if tournament.Ranking == "" {
+		tournament.Ranking = domain.RankByStack
+	}
+	if tournament.Ranking != domain.RankByStack && tournament.Ranking != domain.RankByProfit {
+		return appErrors.NewInvalidInputError(fmt.Sprintf("tournament ranking must be stack or profit: %s", tournament.Ranking)).WithDetail(appErrors.DetailTournamentRanking, tournament.Ranking).
+			WithField("ranking", appErrors.ConstraintOneOf, fmt.Sprintf("%s,%s", domain.RankByStack, domain.RankByProfit))
+	}
+
+	if len(tournament.PrizeShares) == 0 {
+		tournament.PrizeShares = []float64{1}
+	}
+	var total float64
+	for _, share := range tournament.PrizeShares {
+		if share <= 0 {
+			return appErrors.NewInvalidInputError("prize shares must be positive and add up to at most 1").WithDetail(appErrors.DetailTournamentShares).
+				WithField("prize_shares", appErrors.ConstraintMin, "0.0001")
+		}
+		total += share
+	}
+	if total > 1+1e-9 {
+		return appErrors.NewInvalidInputError("prize shares must be positive and add up to at most 1").WithDetail(appErrors.DetailTournamentShares).
+			WithField("prize_shares", appErrors.ConstraintMax, "1")
+	}
+
+	if tournament.StartsAt.IsZero() {
+		tournament.StartsAt = ts.now()
+	}
+	if !tournament.EndsAt.After(tournament.StartsAt) || !tournament.EndsAt.After(ts.now()) {
+		return appErrors.NewInvalidInputError("tournament must end after it starts and in the future").WithDetail(appErrors.DetailTournamentSchedule).
+			WithField("ends_at", appErrors.ConstraintMin, tournament.StartsAt.Format(time.RFC3339))
+	}
+	tournament.PrizePool, tournament.PrizeLeftover, tournament.Closed = 0, 0, false
+	return nil
+}
+
+// ListTournaments returns the tournaments open for registration or play
+func (ts *TournamentService) ListTournaments() (domain.TournamentsResponse, error) {
+	tournaments, err := ts.repo.ListTournaments()
+	if err != nil {
+		return domain.TournamentsResponse{}, err
+	}
+	if tournaments == nil {
+		tournaments = []domain.Tournament{}
+	}
+	return domain.TournamentsResponse{Tournaments: tournaments}, nil
+}
+
+// Register pays the buy-in from the wallet and gives the player the starting stack
+// Registration stays open until the tournament ends
+func (ts *TournamentService) Register(req domain.TournamentRegisterRequest) (domain.TournamentRegisterResponse, error) {
+	log.Printf("\nRegistering client id -> %d to tournament -> %d", req.ClientID, req.TournamentID)
+
+	tournament, err := ts.repo.GetTournament(req.TournamentID)
+	if err != nil {
+		return domain.TournamentRegisterResponse{}, err
+	}
+	if tournament.Closed || !ts.now().Before(tournament.EndsAt) {
+		return domain.TournamentRegisterResponse{}, appErrors.NewTournamentError(fmt.Sprintf("tournament %d has ended", tournament.TournamentID)).WithDetail(appErrors.DetailTournamentEnded, tournament.TournamentID)
+	}
+
+	balance, err := ts.game.repo.GetBalance(req.ClientID)
+	if err != nil {
+		return domain.TournamentRegisterResponse{}, appErrors.NewInternalError(err.Error())
+	}
+	if tournament.BuyIn > balance {
+		return domain.TournamentRegisterResponse{}, appErrors.NewInsufficientFundsError(fmt.Sprintf("buy-in %.2f exceeds available balance %.2f", tournament.BuyIn, balance)).WithDetail(appErrors.DetailTournamentBuyIn, domain.Amount(tournament.BuyIn), domain.Amount(balance))
+	}
+
+	entry, newBalance, err := ts.repo.RegisterTournamentEntry(tournament, req.ClientID)
+	if err != nil {
+		return domain.TournamentRegisterResponse{}, wrapRepositoryError("Error while registering to tournament", err)
+	}
+	return domain.TournamentRegisterResponse{
+		ClientID:     req.ClientID,
+		TournamentID: tournament.TournamentID,
+		BuyIn:        tournament.BuyIn,
+		Stack:        entry.Stack,
+		Balance:      newBalance,
+	}, nil
+}
+
+// Play settles a regular dice bet against the tournament stack while the tournament is running
+func (ts *TournamentService) Play(req domain.TournamentPlayRequest, dice DiceRoller) (domain.TournamentPlayResponse, error) {
+	log.Printf("\nProcessing tournament play for user id -> %d\nTournament -> %d\nBet Amount -> %g\nBet Type -> %s", req.ClientID, req.TournamentID, req.BetAmount, req.BetType)
+
+	tournament, err := ts.repo.GetTournament(req.TournamentID)
+	if err != nil {
+		return domain.TournamentPlayResponse{}, err
+	}
+	now := ts.now()
+	if tournament.Closed || now.Before(tournament.StartsAt) || !now.Before(tournament.EndsAt) {
+		return domain.TournamentPlayResponse{}, appErrors.NewTournamentError(fmt.Sprintf("tournament %d is not running", tournament.TournamentID)).WithDetail(appErrors.DetailTournamentNotRunning, tournament.TournamentID)
+	}
+
+	entry, err := ts.repo.GetTournamentEntry(req.TournamentID, req.ClientID)
+	if err != nil {
+		return domain.TournamentPlayResponse{}, err
+	}
+	bet := req.PlayRequest()
+	limits, err := ts.game.GetBetLimits(req.ClientID)
+	if err != nil {
+		return domain.TournamentPlayResponse{}, err
+	}
+	if err := ts.game.validateBetAmount(bet.BetAmount, entry.Stack, limits); err != nil {
+		return domain.TournamentPlayResponse{}, err
+	}
+	if err := ts.game.validateBetType(bet); err != nil {
+		return domain.TournamentPlayResponse{}, err
+	}
+
+	diceResult, err := dice.Roll()
+	if err != nil {
+		return domain.TournamentPlayResponse{}, appErrors.NewDiceRollError(err.Error())
+	}
+	won := calculateOutcome(bet, diceResult)
+	payout, changeAmount, err := ts.game.settle(bet, won)
+	if err != nil {
+		return domain.TournamentPlayResponse{}, appErrors.NewInternalError(err.Error())
+	}
+
+	res, err := ts.repo.UpdateTournamentStack(req.ClientID, changeAmount, domain.TournamentPlayResponse{
+		TournamentID: req.TournamentID,
+		DiceResult:   diceResult,
+		Won:          won,
+		BetAmount:    req.BetAmount,
+		Payout:       payout,
+	})
+	if err != nil {
+		return domain.TournamentPlayResponse{}, wrapRepositoryError("Error while executing tournament play", err)
+	}
+	return res, nil
+}
+
+// Leaderboard ranks the entries of a tournament, including the prizes they would be paid
+func (ts *TournamentService) Leaderboard(tournamentID int) (domain.LeaderboardResponse, error) {
+	tournament, err := ts.repo.GetTournament(tournamentID)
+	if err != nil {
+		return domain.LeaderboardResponse{}, err
+	}
+	entries, err := ts.repo.GetTournamentEntries(tournamentID)
+	if err != nil {
+		return domain.LeaderboardResponse{}, err
+	}
+	return domain.LeaderboardResponse{
+		TournamentID: tournament.TournamentID,
+		Ranking:      tournament.Ranking,
+		Closed:       tournament.Closed,
+		Entries:      rankEntries(tournament, entries),
+	}, nil
+}
+
+// CloseDueTournaments closes every tournament past its end and pays its prizes
+// Returns the final leaderboard of each tournament closed by this call
+func (ts *TournamentService) CloseDueTournaments() ([]domain.LeaderboardResponse, error) {
+	due, err := ts.repo.DueTournaments()
+	if err != nil {
+		return nil, err
+	}
+
+	var closed []domain.LeaderboardResponse
+	for _, tournament := range due {
+		entries, err := ts.repo.GetTournamentEntries(tournament.TournamentID)
+		if err != nil {
+			return closed, err
+		}
+		ranked := rankEntries(tournament, entries)
+
+		// The pool is left partly unallocated when fewer players entered than there are shares,
+		// when the shares add up to less than 1 and by the rounding of each prize down to the cent
+		var prizes []domain.TournamentPrize
+		leftover := tournament.PrizePool
+		for _, entry := range ranked {
+			if entry.Prize > 0 {
+				prizes = append(prizes, domain.TournamentPrize{PlayerID: entry.PlayerID, Rank: entry.Rank, Amount: entry.Prize})
+				leftover -= entry.Prize
+			}
+		}
+		leftover = math.Round(leftover*100) / 100
+		ok, err := ts.repo.CloseTournament(tournament.TournamentID, prizes, leftover)
+		if err != nil {
+			return closed, wrapRepositoryError(fmt.Sprintf("Error while closing tournament %d", tournament.TournamentID), err)
+		}
+		if !ok {
+			continue
+		}
+
+		log.Printf("\nClosed tournament %d paying %d prize(s), %.2f of the prize pool left unallocated", tournament.TournamentID, len(prizes), leftover)
+		closed = append(closed, domain.LeaderboardResponse{
+			TournamentID: tournament.TournamentID,
+			Ranking:      tournament.Ranking,
+			Closed:       true,
+			Entries:      ranked,
+		})
+	}
+	return closed, nil
+}
+
+// rankEntries sorts the entries by score, ties going to the player who registered first,
+// and assigns each rank its share of the prize pool
+func rankEntries(tournament domain.Tournament, entries []domain.TournamentEntry) []domain.LeaderboardEntry {
+	ranked := make([]domain.LeaderboardEntry, 0, len(entries))
+	sorted := append([]domain.TournamentEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := score(tournament, sorted[i]), score(tournament, sorted[j])
+		if si != sj {
+			return si > sj
+		}
+		return sorted[i].RegisteredAt.Before(sorted[j].RegisteredAt)
+	})
+
+	for i, entry := range sorted {
+		leaderboardEntry := domain.LeaderboardEntry{
+			Rank:         i + 1,
+			PlayerID:     entry.PlayerID,
+			Stack:        entry.Stack,
+			Score:        score(tournament, entry),
+			RoundsPlayed: entry.RoundsPlayed,
+		}
+		if i < len(tournament.PrizeShares) {
+			leaderboardEntry.Prize = math.Floor(tournament.PrizePool*tournament.PrizeShares[i]*100) / 100
+		}
+		ranked = append(ranked, leaderboardEntry)
+	}
+	return ranked
+}
+
+// score is the value a tournament ranks its entries by
+func score(tournament domain.Tournament, entry domain.TournamentEntry) float64 {
+	if tournament.Ranking == domain.RankByProfit {
+		return entry.Stack - tournament.StartingStack
+	}
+	return entry.Stack
+}
+
+// wrapRepositoryError keeps game errors as they are and reports any other repository failure as internal
+func wrapRepositoryError(message string, err error) error {
+	gameErr := &appErrors.GameError{}
+	if errors.As(err, &gameErr) {
+		return err
+	}
+	return appErrors.NewInternalError(fmt.Sprintf("%s: %s", message, err))
+}
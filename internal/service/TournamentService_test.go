@@ -0,0 +1,271 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// runningTournament has started an hour ago and ends in an hour
+func runningTournament(ranking domain.TournamentRanking) domain.Tournament {
+	return domain.Tournament{
+		TournamentID:  3,
+		Name:          "Weekly",
+		BuyIn:         50,
+		StartingStack: 1000,
+		Ranking:       ranking,
+		PrizePool:     150,
+		PrizeShares:   []float64{0.6, 0.3, 0.1},
+		StartsAt:      time.Now().Add(-time.Hour),
+		EndsAt:        time.Now().Add(time.Hour),
+	}
+}
+
+func TestTournamentService_Play(t *testing.T) {
+	tests := []struct {
+		name          string
+		tournament    domain.Tournament
+		stack         float64
+		betAmount     float64
+		expectedStack float64
+		expectedErr   int
+	}{
+		{
+			name:          "winning_bet_updates_stack",
+			tournament:    runningTournament(domain.RankByStack),
+			stack:         1000,
+			betAmount:     TestValidBet,
+			expectedStack: 1090,
+		},
+		{
+			name:        "bet_exceeding_stack",
+			tournament:  runningTournament(domain.RankByStack),
+			stack:       50,
+			betAmount:   TestValidBet,
+			expectedErr: appErrors.InsufficientFundsErrorCode,
+		},
+		{
+			name: "tournament_not_started",
+			tournament: func() domain.Tournament {
+				tournament := runningTournament(domain.RankByStack)
+				tournament.StartsAt = time.Now().Add(time.Minute)
+				return tournament
+			}(),
+			stack:       1000,
+			betAmount:   TestValidBet,
+			expectedErr: appErrors.TournamentErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("GetTournament", 3).Return(tt.tournament, nil)
+			mockRepo.On("GetTournamentEntry", 3, 1).Return(domain.TournamentEntry{TournamentID: 3, PlayerID: 1, Stack: tt.stack}, nil)
+			mockRepo.On("GetTierLimits", 1).Return(domain.TierLimits{Tier: domain.TierStandard}, nil)
+			played := domain.TournamentPlayResponse{TournamentID: 3, DiceResult: 2, Won: true, BetAmount: tt.betAmount, Payout: 190}
+			stacked := played
+			stacked.Stack = tt.expectedStack
+			mockRepo.On("UpdateTournamentStack", 1, 90.0, played).Return(stacked, nil)
+
+			tournaments := NewTournamentService(mockRepo, NewGameService(mockRepo, TestGameConfig))
+			result, err := tournaments.Play(domain.TournamentPlayRequest{
+				ClientID:     1,
+				TournamentID: 3,
+				BetAmount:    tt.betAmount,
+				BetType:      domain.Even,
+			}, NewScriptedDice([]int{2}))
+
+			if tt.expectedErr != 0 {
+				assert.Equal(t, tt.expectedErr, err.(*appErrors.GameError).Code)
+				mockRepo.AssertNotCalled(t, "UpdateTournamentStack", 1, 90.0, played)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, result.Won)
+			assert.Equal(t, tt.expectedStack, result.Stack)
+			mockRepo.AssertNotCalled(t, "ProcessPlay")
+		})
+	}
+}
+
+func TestTournamentService_CloseDueTournaments(t *testing.T) {
+	registeredAt := time.Now().Add(-time.Hour)
+	entries := []domain.TournamentEntry{
+		{TournamentID: 3, PlayerID: 1, Stack: 900, RegisteredAt: registeredAt},
+		{TournamentID: 3, PlayerID: 2, Stack: 1400, RegisteredAt: registeredAt},
+		{TournamentID: 3, PlayerID: 3, Stack: 1100, RegisteredAt: registeredAt.Add(time.Minute)},
+		{TournamentID: 3, PlayerID: 4, Stack: 1100, RegisteredAt: registeredAt},
+	}
+
+	tests := []struct {
+		name            string
+		ranking         domain.TournamentRanking
+		alreadyClosed   bool
+		expectedPlayers []int
+		expectedScores  []float64
+	}{
+		{
+			name:            "ranked_by_stack",
+			ranking:         domain.RankByStack,
+			expectedPlayers: []int{2, 4, 3, 1},
+			expectedScores:  []float64{1400, 1100, 1100, 900},
+		},
+		{
+			name:            "ranked_by_profit",
+			ranking:         domain.RankByProfit,
+			expectedPlayers: []int{2, 4, 3, 1},
+			expectedScores:  []float64{400, 100, 100, -100},
+		},
+		{
+			name:          "closed_concurrently",
+			ranking:       domain.RankByStack,
+			alreadyClosed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tournament := runningTournament(tt.ranking)
+			tournament.EndsAt = time.Now().Add(-time.Minute)
+
+			// Ties go to the player who registered first, prizes are paid to the first three ranks
+			prizes := []domain.TournamentPrize{
+				{PlayerID: 2, Rank: 1, Amount: 90},
+				{PlayerID: 4, Rank: 2, Amount: 45},
+				{PlayerID: 3, Rank: 3, Amount: 15},
+			}
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("DueTournaments").Return([]domain.Tournament{tournament}, nil)
+			mockRepo.On("GetTournamentEntries", 3).Return(entries, nil)
+			mockRepo.On("CloseTournament", 3, prizes, 0.0).Return(!tt.alreadyClosed, nil)
+
+			tournaments := NewTournamentService(mockRepo, NewGameService(mockRepo, TestGameConfig))
+			closed, err := tournaments.CloseDueTournaments()
+			assert.NoError(t, err)
+			mockRepo.AssertCalled(t, "CloseTournament", 3, prizes, 0.0)
+
+			if tt.alreadyClosed {
+				assert.Empty(t, closed)
+				return
+			}
+			assert.Len(t, closed, 1)
+			assert.True(t, closed[0].Closed)
+			for i, entry := range closed[0].Entries {
+				assert.Equal(t, i+1, entry.Rank)
+				assert.Equal(t, tt.expectedPlayers[i], entry.PlayerID)
+				assert.Equal(t, tt.expectedScores[i], entry.Score)
+			}
+		})
+	}
+}
+
+func TestTournamentService_CloseDueTournaments_RecordsLeftover(t *testing.T) {
+	tournament := runningTournament(domain.RankByStack)
+	tournament.PrizePool = 150.05
+	tournament.EndsAt = time.Now().Add(-time.Minute)
+	entries := []domain.TournamentEntry{
+		{TournamentID: 3, PlayerID: 1, Stack: 900},
+		{TournamentID: 3, PlayerID: 2, Stack: 1400},
+	}
+
+	// The third share has no player and both prizes are rounded down to the cent
+	prizes := []domain.TournamentPrize{
+		{PlayerID: 2, Rank: 1, Amount: 90.03},
+		{PlayerID: 1, Rank: 2, Amount: 45.01},
+	}
+	mockRepo := new(repository.MockRepository)
+	mockRepo.On("DueTournaments").Return([]domain.Tournament{tournament}, nil)
+	mockRepo.On("GetTournamentEntries", 3).Return(entries, nil)
+	mockRepo.On("CloseTournament", 3, prizes, 15.01).Return(true, nil)
+
+	tournaments := NewTournamentService(mockRepo, NewGameService(mockRepo, TestGameConfig))
+	closed, err := tournaments.CloseDueTournaments()
+	assert.NoError(t, err)
+	assert.Len(t, closed, 1)
+	mockRepo.AssertCalled(t, "CloseTournament", 3, prizes, 15.01)
+}
+
+func TestTournamentService_CreateTournament(t *testing.T) {
+	now := time.Now()
+	endsAt := now.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		tournament  domain.Tournament
+		expected    domain.Tournament
+		expectedErr int
+	}{
+		{
+			name:       "defaults",
+			tournament: domain.Tournament{Name: " Weekly ", BuyIn: 50, StartingStack: 1000, PrizePool: 500, EndsAt: endsAt},
+			expected: domain.Tournament{
+				Name: "Weekly", BuyIn: 50, StartingStack: 1000, Ranking: domain.RankByStack,
+				PrizeShares: []float64{1}, StartsAt: now, EndsAt: endsAt,
+			},
+		},
+		{
+			name:       "freeroll_ranked_by_profit",
+			tournament: domain.Tournament{Name: "Freeroll", StartingStack: 500, Ranking: domain.RankByProfit, PrizeShares: []float64{0.5, 0.3}, StartsAt: endsAt, EndsAt: endsAt.Add(time.Hour)},
+			expected: domain.Tournament{
+				Name: "Freeroll", StartingStack: 500, Ranking: domain.RankByProfit,
+				PrizeShares: []float64{0.5, 0.3}, StartsAt: endsAt, EndsAt: endsAt.Add(time.Hour),
+			},
+		},
+		{
+			name:        "missing_name",
+			tournament:  domain.Tournament{StartingStack: 1000, EndsAt: endsAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+		{
+			name:        "negative_buy_in",
+			tournament:  domain.Tournament{Name: "Weekly", BuyIn: -1, StartingStack: 1000, EndsAt: endsAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+		{
+			name:        "no_starting_stack",
+			tournament:  domain.Tournament{Name: "Weekly", EndsAt: endsAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+		{
+			name:        "unknown_ranking",
+			tournament:  domain.Tournament{Name: "Weekly", StartingStack: 1000, Ranking: "luck", EndsAt: endsAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+		{
+			name:        "shares_above_pool",
+			tournament:  domain.Tournament{Name: "Weekly", StartingStack: 1000, PrizeShares: []float64{0.7, 0.4}, EndsAt: endsAt},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+		{
+			name:        "ends_before_start",
+			tournament:  domain.Tournament{Name: "Weekly", StartingStack: 1000, StartsAt: endsAt, EndsAt: now.Add(time.Minute)},
+			expectedErr: appErrors.InvalidInputErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("CreateTournament", mock.Anything).Return(tt.expected, nil)
+
+			tournaments := NewTournamentService(mockRepo, NewGameService(mockRepo, TestGameConfig))
+			tournaments.now = func() time.Time { return now }
+			result, err := tournaments.CreateTournament(tt.tournament)
+
+			if tt.expectedErr != 0 {
+				assert.Equal(t, tt.expectedErr, err.(*appErrors.GameError).Code)
+				mockRepo.AssertNotCalled(t, "CreateTournament", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			mockRepo.AssertCalled(t, "CreateTournament", tt.expected)
+		})
+	}
+}
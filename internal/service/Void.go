@@ -0,0 +1,33 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+)
+
+// VoidRound reverses a settled round, recording the reason and the operator
+// The cash balance, jackpot pool, bonus wallet and used free bet are all put back, rounds whose winnings were gambled are refused
+func (gs *GameService) VoidRound(req domain.VoidRoundRequest) (domain.RoundVoid, error) {
+	reason := strings.TrimSpace(req.Reason)
+	voidedBy := strings.TrimSpace(req.VoidedBy)
+	log.Printf("\nVoiding round id -> %d by %s: %s", req.RoundID, voidedBy, reason)
+	if req.RoundID <= 0 {
+		return domain.RoundVoid{}, appErrors.NewInvalidInputError(fmt.Sprintf("invalid round id: %d", req.RoundID)).WithDetail(appErrors.DetailVoidRoundID, req.RoundID).WithField("round_id", appErrors.ConstraintMin, "1")
+	}
+	if reason == "" {
+		return domain.RoundVoid{}, appErrors.NewInvalidInputError("void reason cannot be empty").WithDetail(appErrors.DetailVoidReason).WithField("reason", appErrors.ConstraintRequired, "")
+	}
+	if voidedBy == "" {
+		return domain.RoundVoid{}, appErrors.NewInvalidInputError("voiding operator cannot be empty").WithDetail(appErrors.DetailVoidOperator).WithField("voided_by", appErrors.ConstraintRequired, "")
+	}
+
+	void, err := gs.repo.VoidRound(domain.RoundVoid{RoundID: req.RoundID, Reason: reason, VoidedBy: voidedBy})
+	if err != nil {
+		return domain.RoundVoid{}, wrapRepositoryError("Error while voiding round", err)
+	}
+	return void, nil
+}
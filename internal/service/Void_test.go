@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Desgue/SpicyDice/internal/appErrors"
+	"github.com/Desgue/SpicyDice/internal/domain"
+	"github.com/Desgue/SpicyDice/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVoidRound(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     domain.VoidRoundRequest
+		repoVoid    domain.RoundVoid
+		repoErr     error
+		expectRepo  bool
+		expectedErr int
+	}{
+		{
+			name:       "valid_request_is_trimmed",
+			request:    domain.VoidRoundRequest{RoundID: 9, Reason: " dice malfunction ", VoidedBy: " ops "},
+			repoVoid:   domain.RoundVoid{RoundID: 9, PlayerID: 1, Reason: "dice malfunction", VoidedBy: "ops", ReversedAmount: -90, Balance: 110},
+			expectRepo: true,
+		},
+		{
+			name:       "repeated_void_is_returned",
+			request:    domain.VoidRoundRequest{RoundID: 9, Reason: "dice malfunction", VoidedBy: "ops"},
+			repoVoid:   domain.RoundVoid{RoundID: 9, PlayerID: 1, Reason: "dice malfunction", VoidedBy: "ops", ReversedAmount: -90, Balance: 110, AlreadyVoided: true},
+			expectRepo: true,
+		},
+		{
+			name:        "unknown_round",
+			request:     domain.VoidRoundRequest{RoundID: 9, Reason: "dice malfunction", VoidedBy: "ops"},
+			repoErr:     appErrors.NewVoidError("unknown round: 9"),
+			expectRepo:  true,
+			expectedErr: appErrors.VoidErrorCode,
+		},
+		{
+			name:    "missing_round_id",
+			request: domain.VoidRoundRequest{Reason: "dice malfunction", VoidedBy: "ops"},
+		},
+		{
+			name:    "missing_reason",
+			request: domain.VoidRoundRequest{RoundID: 9, Reason: "  ", VoidedBy: "ops"},
+		},
+		{
+			name:    "missing_operator",
+			request: domain.VoidRoundRequest{RoundID: 9, Reason: "dice malfunction"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(repository.MockRepository)
+			mockRepo.On("VoidRound", mock.Anything).Return(tt.repoVoid, tt.repoErr)
+			service := NewGameService(mockRepo, TestGameConfig)
+
+			void, err := service.VoidRound(tt.request)
+
+			if !tt.expectRepo {
+				assert.Equal(t, appErrors.InvalidInputErrorCode, err.(*appErrors.GameError).Code)
+				mockRepo.AssertNotCalled(t, "VoidRound", mock.Anything)
+				return
+			}
+			mockRepo.AssertCalled(t, "VoidRound", domain.RoundVoid{RoundID: 9, Reason: "dice malfunction", VoidedBy: "ops"})
+			if tt.expectedErr != 0 {
+				assert.Equal(t, tt.expectedErr, err.(*appErrors.GameError).Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.repoVoid, void)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS player (
+  id SERIAL PRIMARY KEY,
+  balance decimal(10,2)
+);
+
+CREATE TABLE IF NOT EXISTS  game_session (
+  session_id  SERIAL PRIMARY KEY,
+  player_id int,
+  bet_amount decimal(10,2),
+  dice_result int,
+  won boolean,
+  active boolean,
+  session_start timestamptz,
+  session_end timestamptz DEFAULT NULL,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_active_player_session ON game_session (player_id)
+WHERE active = true;
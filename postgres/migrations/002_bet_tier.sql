@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS bet_tier (
+  name varchar(32) PRIMARY KEY,
+  min_bet decimal(10,2) DEFAULT NULL,
+  max_bet decimal(10,2) DEFAULT NULL
+);
+
+INSERT INTO bet_tier (name, min_bet, max_bet)
+VALUES
+  ('standard', NULL, NULL),
+  ('vip', 10, 5000),
+  ('restricted', 10, 50)
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE player ADD COLUMN IF NOT EXISTS tier varchar(32) NOT NULL DEFAULT 'standard' REFERENCES bet_tier (name);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS jackpot_pool (
+  id int PRIMARY KEY,
+  amount decimal(12,2) NOT NULL DEFAULT 0,
+  updated_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO jackpot_pool (id, amount) VALUES (1, 0)
+ON CONFLICT (id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS jackpot_award (
+  award_id SERIAL PRIMARY KEY,
+  player_id int,
+  session_id int,
+  amount decimal(12,2),
+  awarded_at timestamptz DEFAULT NOW(),
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE,
+  FOREIGN KEY (session_id) REFERENCES game_session (session_id) ON DELETE CASCADE
+);
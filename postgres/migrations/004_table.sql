@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS table_round (
+  round_id SERIAL PRIMARY KEY,
+  table_id int NOT NULL,
+  state varchar(32) NOT NULL,
+  dice_result int DEFAULT NULL,
+  opened_at timestamptz NOT NULL DEFAULT NOW(),
+  settled_at timestamptz DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS table_bet (
+  bet_id SERIAL PRIMARY KEY,
+  round_id int NOT NULL,
+  player_id int NOT NULL,
+  bet_amount decimal(10,2),
+  bet_type varchar(16),
+  bet_number int DEFAULT NULL,
+  won boolean DEFAULT NULL,
+  payout decimal(10,2) DEFAULT NULL,
+  placed_at timestamptz NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (round_id) REFERENCES table_round (round_id) ON DELETE CASCADE,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
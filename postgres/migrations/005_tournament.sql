@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS ledger_entry (
+  entry_id SERIAL PRIMARY KEY,
+  player_id int NOT NULL,
+  amount decimal(12,2) NOT NULL,
+  kind varchar(32) NOT NULL,
+  reference varchar(64),
+  created_at timestamptz NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tournament (
+  tournament_id SERIAL PRIMARY KEY,
+  name varchar(64) NOT NULL,
+  buy_in decimal(10,2) NOT NULL,
+  starting_stack decimal(12,2) NOT NULL,
+  ranking varchar(16) NOT NULL DEFAULT 'stack',
+  prize_pool decimal(12,2) NOT NULL DEFAULT 0,
+  prize_shares decimal(5,4)[] NOT NULL DEFAULT '{1}',
+  starts_at timestamptz NOT NULL,
+  ends_at timestamptz NOT NULL,
+  closed boolean NOT NULL DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS tournament_entry (
+  tournament_id int NOT NULL,
+  player_id int NOT NULL,
+  stack decimal(12,2) NOT NULL,
+  rounds_played int NOT NULL DEFAULT 0,
+  registered_at timestamptz NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tournament_id, player_id),
+  FOREIGN KEY (tournament_id) REFERENCES tournament (tournament_id) ON DELETE CASCADE,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
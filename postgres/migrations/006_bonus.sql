@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS player_bonus (
+  bonus_id SERIAL PRIMARY KEY,
+  player_id int NOT NULL,
+  amount decimal(10,2) NOT NULL,
+  granted_amount decimal(10,2) NOT NULL,
+  wagering_requirement decimal(12,2) NOT NULL,
+  wagered decimal(12,2) NOT NULL DEFAULT 0,
+  status varchar(16) NOT NULL DEFAULT 'active',
+  granted_at timestamptz NOT NULL DEFAULT NOW(),
+  expires_at timestamptz NOT NULL,
+  closed_at timestamptz,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS player_bonus_active_idx ON player_bonus (player_id) WHERE status = 'active';
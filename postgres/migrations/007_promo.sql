@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS promo_code (
+  code varchar(32) PRIMARY KEY,
+  kind varchar(16) NOT NULL,
+  bet_amount decimal(10,2),
+  bet_type varchar(8),
+  bet_number int,
+  free_bet_count int NOT NULL DEFAULT 1,
+  bonus_amount decimal(10,2),
+  wagering_multiplier decimal(6,2) NOT NULL DEFAULT 0,
+  reward_valid_for interval NOT NULL DEFAULT '7 days',
+  max_redemptions int,
+  max_per_player int NOT NULL DEFAULT 1,
+  redemptions int NOT NULL DEFAULT 0,
+  expires_at timestamptz NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT NOW(),
+  CHECK (kind IN ('free_bet', 'bonus')),
+  CHECK (kind <> 'free_bet' OR (bet_amount > 0 AND bet_type IS NOT NULL)),
+  CHECK (kind <> 'bonus' OR bonus_amount > 0)
+);
+
+CREATE TABLE IF NOT EXISTS promo_redemption (
+  redemption_id SERIAL PRIMARY KEY,
+  code varchar(32) NOT NULL,
+  player_id int NOT NULL,
+  redeemed_at timestamptz NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (code) REFERENCES promo_code (code) ON DELETE CASCADE,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS free_bet (
+  free_bet_id SERIAL PRIMARY KEY,
+  player_id int NOT NULL,
+  code varchar(32) NOT NULL,
+  bet_amount decimal(10,2) NOT NULL,
+  bet_type varchar(8) NOT NULL,
+  bet_number int,
+  status varchar(16) NOT NULL DEFAULT 'available',
+  expires_at timestamptz NOT NULL,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE,
+  FOREIGN KEY (code) REFERENCES promo_code (code) ON DELETE CASCADE
+);
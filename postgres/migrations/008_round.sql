@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS round (
+  round_id SERIAL PRIMARY KEY,
+  session_id int NOT NULL,
+  player_id int NOT NULL,
+  bet_amount decimal(10,2) NOT NULL,
+  bet_type varchar(8) NOT NULL,
+  bet_number int,
+  dice_result int NOT NULL,
+  won boolean NOT NULL,
+  payout decimal(10,2) NOT NULL,
+  net_result decimal(12,2) NOT NULL,
+  free_bet_id int,
+  played_at timestamptz NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (session_id) REFERENCES game_session (session_id) ON DELETE CASCADE,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS round_session_idx ON round (session_id);
+
+-- Sessions held their single even/odd play before rounds existed, move them into round and drop the old columns
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM information_schema.columns
+    WHERE table_schema = current_schema() AND table_name = 'game_session' AND column_name = 'bet_amount'
+  ) THEN
+    INSERT INTO round (session_id, player_id, bet_amount, bet_type, dice_result, won, payout, net_result, played_at)
+    SELECT session_id, player_id, bet_amount,
+      CASE WHEN (dice_result % 2 = 0) = won THEN 'even' ELSE 'odd' END,
+      dice_result, won,
+      CASE WHEN won THEN bet_amount * 2 ELSE 0 END,
+      CASE WHEN won THEN bet_amount ELSE -bet_amount END,
+      session_start
+    FROM game_session
+    WHERE bet_amount IS NOT NULL AND dice_result IS NOT NULL AND won IS NOT NULL;
+
+    ALTER TABLE game_session DROP COLUMN bet_amount, DROP COLUMN dice_result, DROP COLUMN won;
+  END IF;
+END $$;
+
+ALTER TABLE free_bet ADD COLUMN IF NOT EXISTS round_id int REFERENCES round (round_id);
+ALTER TABLE free_bet DROP COLUMN IF EXISTS session_id;
@@ -0,0 +1,24 @@
+-- Rounds played before voiding existed were paid in cash, their balance change is their net result
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM information_schema.columns
+    WHERE table_schema = current_schema() AND table_name = 'round' AND column_name = 'balance_change'
+  ) THEN
+    ALTER TABLE round ADD COLUMN balance_change decimal(12,2) NOT NULL DEFAULT 0;
+    UPDATE round SET balance_change = net_result WHERE free_bet_id IS NULL;
+  END IF;
+END $$;
+
+ALTER TABLE round ADD COLUMN IF NOT EXISTS voided boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS round_void (
+  round_id int PRIMARY KEY,
+  player_id int NOT NULL,
+  reason text NOT NULL,
+  voided_by varchar(64) NOT NULL,
+  reversed_amount decimal(12,2) NOT NULL,
+  voided_at timestamptz NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (round_id) REFERENCES round (round_id) ON DELETE CASCADE,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
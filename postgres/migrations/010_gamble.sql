@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS gamble (
+  gamble_id SERIAL PRIMARY KEY,
+  session_id int NOT NULL,
+  round_id int NOT NULL UNIQUE,
+  player_id int NOT NULL,
+  stake decimal(12,2) NOT NULL,
+  amount decimal(12,2) NOT NULL,
+  step int NOT NULL DEFAULT 0,
+  status varchar(16) NOT NULL DEFAULT 'open',
+  started_at timestamptz NOT NULL DEFAULT NOW(),
+  closed_at timestamptz DEFAULT NULL,
+  FOREIGN KEY (session_id) REFERENCES game_session (session_id) ON DELETE CASCADE,
+  FOREIGN KEY (round_id) REFERENCES round (round_id) ON DELETE CASCADE,
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS gamble_open_idx ON gamble (player_id) WHERE status = 'open';
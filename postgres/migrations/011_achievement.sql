@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS player_achievement (
+  player_id int NOT NULL,
+  achievement_id varchar(32) NOT NULL,
+  unlocked_at timestamptz NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (player_id, achievement_id),
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
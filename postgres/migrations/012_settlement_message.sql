@@ -0,0 +1,11 @@
+ALTER TABLE player ADD COLUMN IF NOT EXISTS message_seq bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS settlement_message (
+  player_id int NOT NULL,
+  seq bigint NOT NULL,
+  message_type varchar(32) NOT NULL,
+  payload jsonb NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (player_id, seq),
+  FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
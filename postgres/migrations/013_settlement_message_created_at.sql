@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS settlement_message_created_at_idx ON settlement_message (created_at);
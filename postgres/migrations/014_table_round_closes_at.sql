@@ -0,0 +1,3 @@
+ALTER TABLE table_round ADD COLUMN IF NOT EXISTS closes_at timestamptz DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS table_round_state_idx ON table_round (state);
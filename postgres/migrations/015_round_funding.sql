@@ -0,0 +1,12 @@
+-- Rounds keep what they took from and paid into the jackpot pool and the bonus wallet, so a void can reverse each part
+ALTER TABLE round ADD COLUMN IF NOT EXISTS jackpot_contribution decimal(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE round ADD COLUMN IF NOT EXISTS jackpot_award decimal(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE round ADD COLUMN IF NOT EXISTS bonus_id int REFERENCES player_bonus (bonus_id);
+ALTER TABLE round ADD COLUMN IF NOT EXISTS bonus_change decimal(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE round ADD COLUMN IF NOT EXISTS bonus_converted decimal(12,2) NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS round_bonus_idx ON round (bonus_id) WHERE bonus_id IS NOT NULL;
+
+ALTER TABLE round_void ADD COLUMN IF NOT EXISTS jackpot_reversed decimal(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE round_void ADD COLUMN IF NOT EXISTS bonus_restored decimal(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE round_void ADD COLUMN IF NOT EXISTS free_bet_restored boolean NOT NULL DEFAULT false;
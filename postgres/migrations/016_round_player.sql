@@ -0,0 +1,2 @@
+-- Achievement stats scan the rounds of a player and look up their last lost round by id
+CREATE INDEX IF NOT EXISTS round_player_idx ON round (player_id, round_id);
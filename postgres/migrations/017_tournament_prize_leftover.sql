@@ -0,0 +1,3 @@
+-- The part of the prize pool left unallocated at close, when fewer players entered than there are prize shares
+-- or the shares add up to less than the whole pool, is kept with the tournament
+ALTER TABLE tournament ADD COLUMN IF NOT EXISTS prize_leftover decimal(12,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+// Package migrations embeds the forward migrations of the database schema, applied in file name order
+// Every migration is idempotent since the development database image also runs them when it is created
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var Files embed.FS
@@ -0,0 +1,19 @@
+CREATE OR REPLACE FUNCTION random_decimal(min_val decimal, max_val decimal) 
+RETURNS decimal AS $$
+BEGIN
+    RETURN (random() * (max_val - min_val) + min_val)::decimal(10,2);
+END;
+$$ LANGUAGE plpgsql;
+
+TRUNCATE TABLE player CASCADE;
+ALTER SEQUENCE player_id_seq RESTART WITH 1;
+ALTER SEQUENCE game_session_session_id_seq RESTART WITH 1;
+
+WITH generate_series AS (
+    SELECT generate_series(1, 100000) AS id
+)
+INSERT INTO player (balance)
+SELECT 
+    random_decimal(100, 10000)
+FROM 
+    generate_series;